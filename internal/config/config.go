@@ -3,15 +3,48 @@ package config
 import (
 	"github.com/kelseyhightower/envconfig"
 	"log/slog"
+	"net/url"
 	"sync"
+	"time"
 )
 
+// redacted replaces secrets in the logged config
+const redacted = "***"
+
 type Config struct {
-	Env      string `envconfig:"ENV"`
-	LogLevel string `envconfig:"LOG_LEVEL"`
-	MongoURI string `envconfig:"MONGODB_URI"`
-	HTTPAddr string `envconfig:"HTTP_ADDR"`
-	ApiKey   string `envconfig:"API_KEY"`
+	Env         string `envconfig:"ENV"`
+	LogLevel    string `envconfig:"LOG_LEVEL"`
+	Repository  string `envconfig:"REPOSITORY" default:"mongodb"`
+	MongoURI    string `envconfig:"MONGODB_URI"`
+	PostgresURI string `envconfig:"POSTGRES_URI"`
+	HTTPAddr    string `envconfig:"HTTP_ADDR"`
+	MaxWinMode  string `envconfig:"MAX_WIN_MODE" default:"cap"`
+
+	// BonusSpendOrder is the order debits spend real and bonus money in, realFirst or bonusFirst
+	BonusSpendOrder string `envconfig:"BONUS_SPEND_ORDER" default:"realFirst"`
+
+	// AdminTokens maps operator name to its admin api token, "name:token,name2:token2"
+	AdminTokens map[string]string `envconfig:"ADMIN_TOKENS"`
+
+	// TrustedProxies are CIDR ranges of proxies allowed to set client ip by X-Forwarded-For header
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+
+	// SignClockSkew is the allowed difference between the request sign timestamp and the server time
+	SignClockSkew time.Duration `envconfig:"SIGN_CLOCK_SKEW" default:"5m"`
+
+	SessionTTL           time.Duration `envconfig:"SESSION_TTL" default:"4h"`
+	SessionSweepInterval time.Duration `envconfig:"SESSION_SWEEP_INTERVAL" default:"1m"`
+
+	// OutboxSink is where events of wallet movements are published: stdout, file or webhook, empty keeps them in the outbox
+	OutboxSink           string        `envconfig:"OUTBOX_SINK"`
+	OutboxFile           string        `envconfig:"OUTBOX_FILE" default:"outbox.jsonl"`
+	OutboxWebhookURL     string        `envconfig:"OUTBOX_WEBHOOK_URL"`
+	OutboxWebhookTimeout time.Duration `envconfig:"OUTBOX_WEBHOOK_TIMEOUT" default:"5s"`
+	OutboxRelayInterval  time.Duration `envconfig:"OUTBOX_RELAY_INTERVAL" default:"1s"`
+	OutboxBatchSize      int           `envconfig:"OUTBOX_BATCH_SIZE" default:"100"`
+	// OutboxMaxAttempts failed deliveries make the event dead, retries wait OutboxRetryBackoff doubled with every attempt
+	OutboxMaxAttempts  int           `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+	OutboxRetryBackoff time.Duration `envconfig:"OUTBOX_RETRY_BACKOFF" default:"5s"`
 }
 
 var (
@@ -32,6 +65,39 @@ func Get(logger *slog.Logger) *Config {
 	return &config
 }
 
+// loggedConfig is the config without the LogValue method, so the redacted copy is logged as is
+type loggedConfig Config
+
+// LogValue logs the config with admin tokens and credentials of the urls redacted
+func (c Config) LogValue() slog.Value {
+	logged := loggedConfig(c)
+	logged.MongoURI = redactURL(c.MongoURI)
+	logged.PostgresURI = redactURL(c.PostgresURI)
+	logged.OutboxWebhookURL = redactURL(c.OutboxWebhookURL)
+	if c.AdminTokens != nil {
+		logged.AdminTokens = make(map[string]string, len(c.AdminTokens))
+		for operator := range c.AdminTokens {
+			logged.AdminTokens[operator] = redacted
+		}
+	}
+	return slog.AnyValue(logged)
+}
+
+// redactURL hides the password and the query of the url, which may carry credentials, the unparsable url is hidden whole
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = redacted
+	}
+	return u.Redacted()
+}
+
 func (c *Config) GetSlogLevel() slog.Level {
 	switch c.LogLevel {
 	case "debug":
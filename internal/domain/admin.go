@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultPageLimit is used when the page limit isn't set
+	DefaultPageLimit = 50
+	// MaxPageLimit protects listing from loading the whole collection
+	MaxPageLimit = 500
+)
+
+type Pagination struct {
+	Offset int
+	Limit  int
+}
+
+// Normalize applies default and maximum page limits
+func (p Pagination) Normalize() Pagination {
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	return p
+}
+
+// BalanceAdjustReq is the manual balance correction, positive amount adds money, negative one takes it away,
+// bonus correction moves bonus money, the granted bonus money comes with its wagering requirement
+type BalanceAdjustReq struct {
+	UserUID  string
+	Currency string
+	Amount   int
+	Bonus    bool
+	Wagering int
+	Operator string
+	Reason   string
+}
+
+// TransactionFilter selects transactions for operators, empty fields aren't applied
+type TransactionFilter struct {
+	UserUID                string
+	SessionUID             string
+	RoundUID               string
+	ProviderTransactionUID string
+	Currency               string
+	Type                   TransactionType
+	Status                 TransactionStatus
+	From                   time.Time
+	To                     time.Time
+	Pagination
+}
+
+type UserPage struct {
+	Users []User
+	Total int
+}
+
+type TransactionPage struct {
+	Transactions []Transaction
+	Total        int
+}
@@ -1,8 +1,100 @@
 package domain
 
+// BonusOrder is the order the debit spends real and bonus money of the balance in
+type BonusOrder string
+
+const (
+	// BonusOrderRealFirst spends bonus money only when real money isn't enough
+	BonusOrderRealFirst BonusOrder = "realFirst"
+	// BonusOrderBonusFirst spends real money only when bonus money isn't enough
+	BonusOrderBonusFirst BonusOrder = "bonusFirst"
+)
+
+func (o BonusOrder) IsValid() bool {
+	return o == BonusOrderRealFirst || o == BonusOrderBonusFirst
+}
+
 type Balance struct {
-	UserUID      string
-	Amount       int
+	UserUID string
+	// Amount is the whole balance, real and bonus money
+	Amount int
+	// Bonus is the bonus money part of Amount, it can't be withdrawn until the wagering requirement is met
+	Bonus int
+	// Wagering is the amount left to bet before the bonus money turns into real money
+	Wagering     int
 	Currency     string
 	Denomination int
 }
+
+// Real returns the real money part of the balance
+func (b *Balance) Real() int {
+	return b.Amount - b.Bonus
+}
+
+// DebitBonus returns the bonus money part of the debit spent in the order
+func (b *Balance) DebitBonus(amount int, order BonusOrder) int {
+	if order == BonusOrderBonusFirst {
+		return min(amount, b.Bonus)
+	}
+	return min(max(amount-b.Real(), 0), b.Bonus)
+}
+
+// CreditBonus returns the bonus money part of the win, the win goes back to real and bonus money
+// in proportion of the round bets, bonus money isn't credited without wagering requirement
+func (b *Balance) CreditBonus(amount int, round *Round) int {
+	if b.Wagering == 0 || round == nil || round.TotalBet <= 0 || round.TotalBonusBet <= 0 {
+		return 0
+	}
+	return amount * min(round.TotalBonusBet, round.TotalBet) / round.TotalBet
+}
+
+// Book applies the transaction moving delta of the whole balance and sets its bonus money part and the balance snapshots,
+// the debit spends bonus money in its order and counts to the wagering requirement, the credit is split by the round bets,
+// rollback returns the bonus money part of the original transaction and the wagering of the debit, bonus adjustment and bonus win of free rounds
+// add their wagering requirement
+func (b *Balance) Book(txn *Transaction, delta int, original *Transaction, round *Round) {
+	bonus, wagered := 0, 0
+	switch txn.Type {
+	case TransactionTypeDebit:
+		bonus, wagered = -b.DebitBonus(txn.Amount, txn.BonusOrder), txn.Amount
+	case TransactionTypeCredit:
+		bonus = b.CreditBonus(txn.Amount, round)
+		if txn.Wagering > 0 {
+			bonus = txn.Amount
+			b.Wagering += txn.Wagering
+		}
+	case TransactionTypeAdjustment:
+		if txn.BonusAmount != 0 {
+			bonus = max(delta, -b.Bonus)
+			b.Wagering += txn.Wagering
+		}
+	case TransactionTypeRollback:
+		if original != nil && original.Type == TransactionTypeDebit && b.Wagering > 0 {
+			// the bet is returned, so it no longer counts to the wagering requirement
+			bonus, wagered = original.BonusAmount, -original.Amount
+		}
+		if original != nil && original.Type == TransactionTypeCredit {
+			bonus = -min(original.BonusAmount, b.Bonus)
+		}
+	}
+
+	txn.BalanceBefore = b.Amount
+	b.move(delta, bonus, wagered)
+	txn.Balance = b.Amount
+	txn.BonusBalance = b.Bonus
+	txn.BonusAmount = bonus
+	if txn.Type != TransactionTypeAdjustment && bonus < 0 {
+		txn.BonusAmount = -bonus
+	}
+}
+
+// move applies delta of which bonus is the bonus money part, wagered amount counts to the wagering requirement,
+// the bonus money turns into real money once the requirement is met
+func (b *Balance) move(delta, bonus, wagered int) {
+	b.Amount += delta
+	b.Bonus = min(max(b.Bonus+bonus, 0), b.Amount)
+	b.Wagering = max(b.Wagering-wagered, 0)
+	if b.Wagering == 0 {
+		b.Bonus = 0
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBalanceBook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("debit spends real money first", func(t *testing.T) {
+		balance := &Balance{Amount: 1000, Bonus: 300, Wagering: 5000}
+		txn := &Transaction{Type: TransactionTypeDebit, Amount: 800, BonusOrder: BonusOrderRealFirst}
+
+		balance.Book(txn, -800, nil, nil)
+
+		assert.Equal(t, 100, txn.BonusAmount)
+		assert.Equal(t, 200, balance.Amount)
+		assert.Equal(t, 200, balance.Bonus)
+		assert.Equal(t, 4200, balance.Wagering)
+		assert.Equal(t, 1000, txn.BalanceBefore)
+		assert.Equal(t, 200, txn.BonusBalance)
+	})
+
+	t.Run("debit spends bonus money first", func(t *testing.T) {
+		balance := &Balance{Amount: 1000, Bonus: 300, Wagering: 5000}
+		txn := &Transaction{Type: TransactionTypeDebit, Amount: 200, BonusOrder: BonusOrderBonusFirst}
+
+		balance.Book(txn, -200, nil, nil)
+
+		assert.Equal(t, 200, txn.BonusAmount)
+		assert.Equal(t, 800, balance.Amount)
+		assert.Equal(t, 100, balance.Bonus)
+	})
+
+	t.Run("win split by round bets", func(t *testing.T) {
+		balance := &Balance{Amount: 1000, Bonus: 300, Wagering: 5000}
+		txn := &Transaction{Type: TransactionTypeCredit, Amount: 1000}
+
+		balance.Book(txn, 1000, nil, &Round{TotalBet: 400, TotalBonusBet: 100})
+
+		assert.Equal(t, 250, txn.BonusAmount)
+		assert.Equal(t, 2000, balance.Amount)
+		assert.Equal(t, 550, balance.Bonus)
+	})
+
+	t.Run("wagering met releases bonus money", func(t *testing.T) {
+		balance := &Balance{Amount: 1000, Bonus: 300, Wagering: 100}
+		txn := &Transaction{Type: TransactionTypeDebit, Amount: 100, BonusOrder: BonusOrderRealFirst}
+
+		balance.Book(txn, -100, nil, nil)
+
+		assert.Equal(t, 900, balance.Amount)
+		assert.Equal(t, 0, balance.Bonus)
+		assert.Equal(t, 0, balance.Wagering)
+		assert.Equal(t, 900, balance.Real())
+	})
+
+	t.Run("win without wagering is real money", func(t *testing.T) {
+		balance := &Balance{Amount: 1000}
+		txn := &Transaction{Type: TransactionTypeCredit, Amount: 100}
+
+		balance.Book(txn, 100, nil, &Round{TotalBet: 100, TotalBonusBet: 100})
+
+		assert.Equal(t, 0, txn.BonusAmount)
+		assert.Equal(t, 0, balance.Bonus)
+	})
+
+	t.Run("rollback returns bonus money of the debit", func(t *testing.T) {
+		balance := &Balance{Amount: 800, Bonus: 100, Wagering: 4800}
+		original := &Transaction{Type: TransactionTypeDebit, Amount: 200, BonusAmount: 200}
+		txn := &Transaction{Type: TransactionTypeRollback, Amount: 200}
+
+		balance.Book(txn, 200, original, nil)
+
+		assert.Equal(t, 200, txn.BonusAmount)
+		assert.Equal(t, 1000, balance.Amount)
+		assert.Equal(t, 300, balance.Bonus)
+		assert.Equal(t, 5000, balance.Wagering)
+	})
+
+	t.Run("debit and its rollback leave wagering as it was", func(t *testing.T) {
+		balance := &Balance{Amount: 1000, Bonus: 500, Wagering: 1000}
+
+		for i := range 3 {
+			debit := &Transaction{Type: TransactionTypeDebit, Amount: 400, BonusOrder: BonusOrderBonusFirst}
+			balance.Book(debit, -400, nil, nil)
+			rollback := &Transaction{Type: TransactionTypeRollback, Amount: 400}
+			balance.Book(rollback, 400, debit, nil)
+
+			assert.Equal(t, 1000, balance.Amount, i)
+			assert.Equal(t, 500, balance.Bonus, i)
+			assert.Equal(t, 1000, balance.Wagering, i)
+		}
+	})
+
+	t.Run("bonus grant adds wagering", func(t *testing.T) {
+		balance := &Balance{Amount: 1000}
+		txn := &Transaction{Type: TransactionTypeAdjustment, Amount: 500, BonusAmount: 500, Wagering: 10000}
+
+		balance.Book(txn, 500, nil, nil)
+
+		assert.Equal(t, 1500, balance.Amount)
+		assert.Equal(t, 500, balance.Bonus)
+		assert.Equal(t, 10000, balance.Wagering)
+		assert.Equal(t, 500, txn.BonusBalance)
+	})
+}
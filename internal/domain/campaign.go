@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+type CampaignStatus string
+
+const (
+	CampaignStatusActive    CampaignStatus = "active"
+	CampaignStatusCompleted CampaignStatus = "completed"
+	CampaignStatusCancelled CampaignStatus = "cancelled"
+)
+
+// CampaignWin is the money the winnings of the free rounds are credited as
+type CampaignWin string
+
+const (
+	CampaignWinReal  CampaignWin = "real"
+	CampaignWinBonus CampaignWin = "bonus"
+)
+
+func (w CampaignWin) IsValid() bool {
+	return w == CampaignWinReal || w == CampaignWinBonus
+}
+
+// Campaign is the free rounds granted to the user in the game, the bets of free rounds are paid by the campaign
+// instead of the balance and every bet takes one of the rounds left
+type Campaign struct {
+	UID      string
+	UserUID  string
+	GameUID  string
+	Currency string
+	Rounds   int
+	// RoundsLeft is the number of free rounds not played yet, the campaign is completed when it comes to zero
+	RoundsLeft int
+	// BetValue is the bet of every free round the game is launched with
+	BetValue int
+	Win      CampaignWin
+	// WageringMultiplier of bonus money win sets the wagering requirement of the win
+	WageringMultiplier int
+	Status             CampaignStatus
+	Operator           string
+	CreatedAt          time.Time
+	ExpiresAt          time.Time
+}
+
+// IsAvailable reports whether free round can be played at the moment
+func (c *Campaign) IsAvailable(now time.Time) bool {
+	return c.Status == CampaignStatusActive && c.RoundsLeft > 0 && now.Before(c.ExpiresAt)
+}
+
+// Wagering returns the wagering requirement of the win, zero means the win is real money
+func (c *Campaign) Wagering(win int) int {
+	if c.Win != CampaignWinBonus {
+		return 0
+	}
+	return win * c.WageringMultiplier
+}
+
+// CampaignFilter selects campaigns for operators, empty fields aren't applied
+type CampaignFilter struct {
+	UserUID string
+	Status  CampaignStatus
+}
@@ -1,6 +1,126 @@
 package domain
 
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxDenomination is the finest denomination of the amounts, 10^18 is the largest power of ten fitting int64
+const MaxDenomination = 18
+
+// decimalRe is the format of the decimal amount of major units, e.g. 12.34
+var decimalRe = regexp.MustCompile(`^(-?)([0-9]+)(?:\.([0-9]+))?$`)
+
 type Currency struct {
 	Code         string
 	Denomination int
+	// MaxWin is the default win limit in the currency, zero means unlimited
+	MaxWin int
+}
+
+// Denominate converts the amount in minor units of the from denomination into minor units of the to denomination,
+// false is returned when the amount can't be expressed in the to denomination without losing precision
+func Denominate(amount, from, to int) (int, bool) {
+	if from >= to {
+		factor, ok := pow10(from - to)
+		if !ok {
+			// any int is finer than the factor
+			return 0, amount == 0
+		}
+		return amount / factor, amount%factor == 0
+	}
+	factor, ok := pow10(to - from)
+	if !ok {
+		return 0, amount == 0
+	}
+	if amount > math.MaxInt/factor || amount < math.MinInt/factor {
+		return 0, false
+	}
+	return amount * factor, true
+}
+
+// DenominateDown converts the amount in minor units of the from denomination into minor units of the to denomination
+// rounding down, it's used to report amounts in coarser denomination than they are kept
+// the amount not fitting the finer denomination is capped by the int range
+func DenominateDown(amount, from, to int) int {
+	if from <= to {
+		res, ok := Denominate(amount, from, to)
+		if !ok && amount > 0 {
+			return math.MaxInt
+		}
+		if !ok {
+			return math.MinInt
+		}
+		return res
+	}
+	factor, ok := pow10(from - to)
+	if !ok {
+		if amount < 0 {
+			return -1
+		}
+		return 0
+	}
+	res := amount / factor
+	if amount%factor < 0 {
+		res--
+	}
+	return res
+}
+
+// IsDecimal reports whether the string is the decimal amount
+func IsDecimal(s string) bool {
+	return decimalRe.MatchString(s)
+}
+
+// ParseDecimal reads the decimal string of major units into minor units of the denomination,
+// false is returned for malformed value, value with more significant fraction digits than the denomination
+// and denomination out of 0..MaxDenomination
+func ParseDecimal(s string, denomination int) (int, bool) {
+	m := decimalRe.FindStringSubmatch(s)
+	if m == nil || denomination < 0 || denomination > MaxDenomination {
+		return 0, false
+	}
+	fraction := strings.TrimRight(m[3], "0")
+	if len(fraction) > denomination {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(m[2] + fraction + strings.Repeat("0", denomination-len(fraction)))
+	if err != nil {
+		return 0, false
+	}
+	if m[1] != "" {
+		amount = -amount
+	}
+	return amount, true
+}
+
+// FormatDecimal formats minor units of the denomination as decimal string of major units
+func FormatDecimal(amount, denomination int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	if denomination <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= denomination {
+		digits = strings.Repeat("0", denomination-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-denomination] + "." + digits[len(digits)-denomination:]
+}
+
+// pow10 returns 10^n, false is returned when it doesn't fit int, see MaxDenomination
+func pow10(n int) (int, bool) {
+	if n > MaxDenomination {
+		return 0, false
+	}
+	res := 1
+	for range n {
+		res *= 10
+	}
+	return res, true
 }
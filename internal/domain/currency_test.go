@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestDenominate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finer denomination", func(t *testing.T) {
+		amount, ok := Denominate(125, 2, 3)
+
+		assert.True(t, ok)
+		assert.Equal(t, 1250, amount)
+	})
+
+	t.Run("coarser denomination", func(t *testing.T) {
+		amount, ok := Denominate(1250, 3, 2)
+
+		assert.True(t, ok)
+		assert.Equal(t, 125, amount)
+	})
+
+	t.Run("precision lost", func(t *testing.T) {
+		_, ok := Denominate(1255, 3, 2)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		_, ok := Denominate(math.MaxInt/10, 0, 2)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("rounded down", func(t *testing.T) {
+		assert.Equal(t, 125, DenominateDown(1259, 3, 2))
+		assert.Equal(t, -126, DenominateDown(-1259, 3, 2))
+		assert.Equal(t, 12590, DenominateDown(1259, 3, 4))
+	})
+
+	t.Run("finest denomination", func(t *testing.T) {
+		amount, ok := Denominate(5, 0, MaxDenomination)
+
+		assert.True(t, ok)
+		assert.Equal(t, 5_000_000_000_000_000_000, amount)
+	})
+
+	t.Run("denominations out of int range", func(t *testing.T) {
+		for _, diff := range []int{MaxDenomination + 1, 63, 64, 100} {
+			_, ok := Denominate(1, 0, diff)
+			assert.False(t, ok, diff)
+
+			_, ok = Denominate(1, diff, 0)
+			assert.False(t, ok, diff)
+
+			amount, ok := Denominate(0, diff, 0)
+			assert.True(t, ok, diff)
+			assert.Equal(t, 0, amount, diff)
+		}
+	})
+
+	t.Run("rounded down out of int range", func(t *testing.T) {
+		assert.Equal(t, 0, DenominateDown(math.MaxInt, 64, 0))
+		assert.Equal(t, -1, DenominateDown(-1, 64, 0))
+		assert.Equal(t, math.MaxInt, DenominateDown(1, 0, 64))
+		assert.Equal(t, math.MinInt, DenominateDown(-1, 0, 19))
+		assert.Equal(t, 0, DenominateDown(0, 0, 64))
+	})
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse", func(t *testing.T) {
+		for s, want := range map[string]int{"12.34": 1234, "12.3": 1230, "12": 1200, "0.05": 5, "12.340": 1234, "-1.5": -150} {
+			amount, ok := ParseDecimal(s, 2)
+
+			assert.True(t, ok, s)
+			assert.Equal(t, want, amount, s)
+		}
+	})
+
+	t.Run("parse rejects malformed and precision loss", func(t *testing.T) {
+		for _, s := range []string{"", "1.", ".5", "1,5", "1e2", "12.345", "99999999999999999999"} {
+			_, ok := ParseDecimal(s, 2)
+
+			assert.False(t, ok, s)
+		}
+	})
+
+	t.Run("parse rejects denomination out of range", func(t *testing.T) {
+		for _, denomination := range []int{-1, MaxDenomination + 1, 64} {
+			_, ok := ParseDecimal("0", denomination)
+
+			assert.False(t, ok, denomination)
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		assert.Equal(t, "12.34", FormatDecimal(1234, 2))
+		assert.Equal(t, "0.05", FormatDecimal(5, 2))
+		assert.Equal(t, "-0.05", FormatDecimal(-5, 2))
+		assert.Equal(t, "1200", FormatDecimal(1200, 0))
+	})
+}
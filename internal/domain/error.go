@@ -15,6 +15,8 @@ type Error struct {
 	Source   string  `json:"source"`
 	Internal []Error `json:"internal"`
 	External []error `json:"external"`
+	// Violations are the failed validation rules of the request fields
+	Violations []Violation `json:"violations,omitempty"`
 }
 
 func NewError(source string) *Error {
@@ -62,6 +64,11 @@ func (e *Error) SetSource(source string) *Error {
 	return e
 }
 
+func (e *Error) SetViolations(violations ...Violation) *Error {
+	e.Violations = violations
+	return e
+}
+
 func (e *Error) Add(err error) *Error {
 	if err == nil {
 		return e
@@ -111,8 +118,46 @@ const (
 	ErrRepoCreate             = "REPO_CREATE_ERROR"
 	ErrEmptyTransactionUID    = "EMPTY_TRANSACTION_ID"
 	ErrTransactionNotFound    = "TRANSACTION_NOT_FOUND"
+	ErrTransactionConflict    = "TRANSACTION_CONFLICT"
+	ErrTransactionRolledBack  = "TRANSACTION_ROLLED_BACK"
 	ErrUnknownCurrency        = "UNKNOWN_CURRENCY"
 	ErrSignInvalid            = "INVALID_SIGN"
 	ErrSignEmpty              = "SIGN_NOT_PROVIDED"
 	ErrReadBody               = "READ_BODY_ERROR"
+	ErrEmptyBetUID            = "EMPTY_BET_ID"
+	ErrRoundNotFound          = "ROUND_NOT_FOUND"
+	ErrRoundClosed            = "ROUND_CLOSED"
+	ErrRoundUser              = "ROUND_USER_MISMATCH"
+	ErrRollbackAmbiguous      = "ROLLBACK_AMBIGUOUS"
+	ErrRoundNotReconciled     = "ROUND_NOT_RECONCILED"
+	ErrRoundClose             = "ROUND_CLOSE_ERROR"
+	ErrMaxWinExceeded         = "MAX_WIN_EXCEEDED"
+	ErrJackpotNotFound        = "JACKPOT_NOT_FOUND"
+	ErrJackpotPayout          = "JACKPOT_PAYOUT_ERROR"
+	ErrSessionClosed          = "SESSION_CLOSED"
+	ErrSessionExpired         = "SESSION_EXPIRED"
+	ErrSessionCurrency        = "SESSION_CURRENCY_MISMATCH"
+	ErrSessionClose           = "SESSION_CLOSE_ERROR"
+	ErrUnauthorized           = "UNAUTHORIZED"
+	ErrRepoUpdate             = "REPO_UPDATE_ERROR"
+	ErrRepoDelete             = "REPO_DELETE_ERROR"
+	ErrAdjustment             = "ADJUSTMENT_ERROR"
+	ErrUserHasBalance         = "USER_HAS_BALANCE"
+	ErrProviderEmpty          = "PROVIDER_NOT_PROVIDED"
+	ErrProviderNotFound       = "PROVIDER_NOT_FOUND"
+	ErrProviderDisabled       = "PROVIDER_DISABLED"
+	ErrProviderIP             = "PROVIDER_IP_NOT_ALLOWED"
+	ErrProviderCurrency       = "PROVIDER_CURRENCY_NOT_ALLOWED"
+	ErrProviderSession        = "PROVIDER_SESSION_NOT_ALLOWED"
+	ErrSignTimestamp          = "INVALID_SIGN_TIMESTAMP"
+	ErrSignNonce              = "INVALID_SIGN_NONCE"
+	ErrSignReplay             = "SIGN_REPLAYED"
+	ErrBatchAborted           = "BATCH_ABORTED"
+	ErrCampaignNotFound       = "CAMPAIGN_NOT_FOUND"
+	ErrCampaignUnavailable    = "CAMPAIGN_UNAVAILABLE"
+	ErrGamingLimitExceeded    = "GAMING_LIMIT_EXCEEDED"
+	ErrDepositLimitExceeded   = "DEPOSIT_LIMIT_EXCEEDED"
+	ErrPlayerExcluded         = "PLAYER_EXCLUDED"
+	ErrExclusionShortened     = "EXCLUSION_SHORTENED"
+	ErrSessionTimeLimit       = "SESSION_TIME_LIMIT"
 )
@@ -0,0 +1,98 @@
+package domain
+
+import "net/http"
+
+// ErrorInfo describes the error code for the provider
+type ErrorInfo struct {
+	HTTPStatus int
+	// ProviderCode is the numeric code of the error in provider api:
+	// 1xxx rejected request, 2xxx final business rejection, 5xxx infrastructure failure
+	ProviderCode int
+	Message      string
+	// Retryable reports whether the same request could succeed later
+	Retryable bool
+}
+
+// errorCatalogue maps error code to its description, codes missing here are treated as internal error
+var errorCatalogue = map[string]ErrorInfo{
+	ErrNone: {http.StatusOK, 0, "Success", false},
+
+	// rejected request
+	ErrInvalidApiCommand:      {http.StatusBadRequest, 1001, "Unknown api command", false},
+	ErrInvalidRequest:         {http.StatusBadRequest, 1002, "Invalid request", false},
+	ErrInvalidTransactionType: {http.StatusBadRequest, 1003, "Invalid transaction type", false},
+	ErrEmptyTransactionUID:    {http.StatusBadRequest, 1004, "Transaction id is required", false},
+	ErrEmptyBetUID:            {http.StatusBadRequest, 1005, "Bet id is required", false},
+	ErrReadBody:               {http.StatusBadRequest, 1006, "Request body can't be read", false},
+	ErrUserHasBalance:         {http.StatusBadRequest, 1007, "User has money on balance", false},
+
+	// authentication
+	ErrSignEmpty:        {http.StatusUnauthorized, 1101, "Sign is not provided", false},
+	ErrSignInvalid:      {http.StatusUnauthorized, 1102, "Sign is invalid", false},
+	ErrSignTimestamp:    {http.StatusUnauthorized, 1103, "Sign timestamp is invalid or out of allowed window", false},
+	ErrSignNonce:        {http.StatusUnauthorized, 1104, "Sign nonce is not provided", false},
+	ErrSignReplay:       {http.StatusConflict, 1105, "Request has been already received", false},
+	ErrProviderEmpty:    {http.StatusUnauthorized, 1106, "Provider is not provided", false},
+	ErrProviderNotFound: {http.StatusNotFound, 1107, "Provider not found", false},
+	ErrProviderDisabled: {http.StatusForbidden, 1108, "Provider is disabled", false},
+	ErrProviderIP:       {http.StatusForbidden, 1109, "Request from the ip is not allowed", false},
+	ErrProviderCurrency: {http.StatusForbidden, 1110, "Currency is not allowed for the provider", false},
+	ErrUnauthorized:     {http.StatusUnauthorized, 1111, "Unauthorized", false},
+	ErrProviderSession:  {http.StatusForbidden, 1112, "Game session belongs to another provider", false},
+
+	// not found
+	ErrNotFound:            {http.StatusNotFound, 2001, "Not found", false},
+	ErrSessionNotFound:     {http.StatusNotFound, 2002, "Game session not found", false},
+	ErrUserNotFound:        {http.StatusNotFound, 2003, "User not found", false},
+	ErrBalanceNotFound:     {http.StatusNotFound, 2004, "Balance not found", false},
+	ErrTransactionNotFound: {http.StatusNotFound, 2005, "Transaction not found", false},
+	ErrRoundNotFound:       {http.StatusNotFound, 2006, "Round not found", false},
+	ErrJackpotNotFound:     {http.StatusNotFound, 2007, "Jackpot not found", false},
+	ErrUnknownCurrency:     {http.StatusNotFound, 2008, "Unknown currency", false},
+	ErrCampaignNotFound:    {http.StatusNotFound, 2009, "Free rounds campaign not found", false},
+
+	// business rejection
+	ErrDecrement:             {http.StatusPaymentRequired, 2101, "Insufficient balance", false},
+	ErrMaxWinExceeded:        {http.StatusUnprocessableEntity, 2102, "Max win exceeded", false},
+	ErrSessionClosed:         {http.StatusUnprocessableEntity, 2103, "Game session is closed", false},
+	ErrSessionExpired:        {http.StatusUnprocessableEntity, 2104, "Game session is expired", false},
+	ErrSessionCurrency:       {http.StatusUnprocessableEntity, 2105, "Currency doesn't match game session", false},
+	ErrTransactionConflict:   {http.StatusConflict, 2106, "Transaction conflicts with the processed one", false},
+	ErrTransactionRolledBack: {http.StatusConflict, 2107, "Transaction has been rolled back", false},
+	ErrRoundClosed:           {http.StatusConflict, 2108, "Round is closed", false},
+	ErrRoundNotReconciled:    {http.StatusConflict, 2109, "Round totals don't match transactions", false},
+	ErrJackpotPayout:         {http.StatusConflict, 2110, "Jackpot can't cover the payout", false},
+	ErrRepoCreate:            {http.StatusConflict, 2111, "Record can't be created", false},
+	ErrAdjustment:            {http.StatusConflict, 2112, "Balance can't be adjusted", false},
+	ErrSessionClose:          {http.StatusConflict, 2113, "Game session can't be closed", false},
+	ErrBatchAborted:          {http.StatusConflict, 2114, "Batch item isn't processed, another item of the batch failed", false},
+	ErrCampaignUnavailable:   {http.StatusConflict, 2115, "Free rounds campaign is expired, cancelled or has no rounds left", false},
+	ErrGamingLimitExceeded:   {http.StatusUnprocessableEntity, 2116, "Responsible gaming limit exceeded", false},
+	ErrPlayerExcluded:        {http.StatusForbidden, 2117, "Player is self-excluded or on cool-off", false},
+	ErrSessionTimeLimit:      {http.StatusUnprocessableEntity, 2118, "Session time limit reached", false},
+	ErrRoundUser:             {http.StatusConflict, 2119, "Bet id is used by the round of another user", false},
+	ErrRollbackAmbiguous:     {http.StatusConflict, 2120, "Transaction id refers both the bet and the win, roll back them by the returned transaction ids", false},
+	ErrDepositLimitExceeded:  {http.StatusUnprocessableEntity, 2121, "Deposit limit exceeded", false},
+	ErrExclusionShortened:    {http.StatusConflict, 2122, "Active self-exclusion or cool-off can't be shortened", false},
+
+	// infrastructure failure
+	ErrServer:            {http.StatusInternalServerError, 5000, "Internal server error", true},
+	ErrConfig:            {http.StatusInternalServerError, 5001, "Server configuration error", true},
+	ErrConnect:           {http.StatusServiceUnavailable, 5002, "Storage is unavailable", true},
+	ErrRepoInit:          {http.StatusServiceUnavailable, 5003, "Storage is not initialized", true},
+	ErrProcessingRequest: {http.StatusInternalServerError, 5004, "Request processing failed", true},
+	ErrIncrement:         {http.StatusInternalServerError, 5005, "Credit failed", true},
+	ErrRollback:          {http.StatusInternalServerError, 5006, "Rollback failed", true},
+	ErrRoundClose:        {http.StatusInternalServerError, 5007, "Round can't be closed", true},
+	ErrRepoUpdate:        {http.StatusInternalServerError, 5008, "Record can't be updated", true},
+	ErrRepoDelete:        {http.StatusInternalServerError, 5009, "Record can't be deleted", true},
+}
+
+// ErrorInfoOf returns description of the error code, unknown codes are described as internal error
+func ErrorInfoOf(code string) ErrorInfo {
+	info, ok := errorCatalogue[code]
+	if !ok {
+		return errorCatalogue[ErrServer]
+	}
+	return info
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCatalogue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("provider codes are unique", func(t *testing.T) {
+		seen := make(map[int]string, len(errorCatalogue))
+		for code, info := range errorCatalogue {
+			other, ok := seen[info.ProviderCode]
+			assert.False(t, ok, "%s and %s share provider code %d", code, other, info.ProviderCode)
+			seen[info.ProviderCode] = code
+			assert.NotEmpty(t, info.Message, code)
+		}
+	})
+
+	t.Run("business rejection is final", func(t *testing.T) {
+		info := ErrorInfoOf(ErrDecrement)
+
+		assert.Equal(t, http.StatusPaymentRequired, info.HTTPStatus)
+		assert.Equal(t, 2101, info.ProviderCode)
+		assert.False(t, info.Retryable)
+	})
+
+	t.Run("unknown code is retryable internal error", func(t *testing.T) {
+		info := ErrorInfoOf(AsError(assert.AnError).Code)
+
+		assert.Equal(t, http.StatusInternalServerError, info.HTTPStatus)
+		assert.Equal(t, 5000, info.ProviderCode)
+		assert.True(t, info.Retryable)
+	})
+}
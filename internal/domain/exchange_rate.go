@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// Rounding is the direction the converted amount is rounded in to whole minor units
+type Rounding int
+
+const (
+	RoundDown Rounding = iota
+	RoundUp
+)
+
+// ExchangeRate converts amounts of the From currency into the To currency
+type ExchangeRate struct {
+	From string
+	To   string
+	// Rate is the amount of To currency units for one unit of From currency
+	Rate      float64
+	UpdatedAt time.Time
+	// inverted is the rate this one is the inverse of, the conversion inverts its exact value instead of the rounded Rate
+	inverted *ExchangeRate
+}
+
+// Inverse returns the rate converting the To currency back into the From currency
+func (r *ExchangeRate) Inverse() *ExchangeRate {
+	return &ExchangeRate{From: r.To, To: r.From, Rate: 1 / r.Rate, UpdatedAt: r.UpdatedAt, inverted: r}
+}
+
+// ConvertUp converts the amount in minor units of the From currency into minor units of the To currency rounding up,
+// debits are converted up and credits down, so the conversion never pays out more than it takes
+func (r *ExchangeRate) ConvertUp(amount, fromDenomination, toDenomination int) int {
+	return r.Convert(amount, fromDenomination, toDenomination, RoundUp)
+}
+
+// ConvertDown converts the amount in minor units of the From currency into minor units of the To currency rounding down
+func (r *ExchangeRate) ConvertDown(amount, fromDenomination, toDenomination int) int {
+	return r.Convert(amount, fromDenomination, toDenomination, RoundDown)
+}
+
+// Convert converts the amount in minor units of the From currency into minor units of the To currency,
+// the conversion is exact and only the result is rounded, the result out of the int range saturates
+func (r *ExchangeRate) Convert(amount, fromDenomination, toDenomination int, rounding Rounding) int {
+	ratio := r.ratio()
+	if ratio == nil {
+		return 0
+	}
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), ratio)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(toDenomination-fromDenomination))), nil))
+	if toDenomination >= fromDenomination {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	// the denominator of the rational is always positive, so the euclidean division rounds down
+	res, rem := new(big.Int).DivMod(v.Num(), v.Denom(), new(big.Int))
+	if rounding == RoundUp && rem.Sign() != 0 {
+		res.Add(res, big.NewInt(1))
+	}
+	switch {
+	case !res.IsInt64() && res.Sign() > 0:
+		return math.MaxInt
+	case !res.IsInt64():
+		return math.MinInt
+	}
+	return int(res.Int64())
+}
+
+// ratio is the exact value of the rate, the float is read as its shortest decimal form, so 1.1 is 11/10
+// and not its binary approximation, nil is returned for the rate which isn't a positive number
+func (r *ExchangeRate) ratio() *big.Rat {
+	if r.inverted != nil {
+		ratio := r.inverted.ratio()
+		if ratio == nil {
+			return nil
+		}
+		return ratio.Inv(ratio)
+	}
+	ratio, ok := new(big.Rat).SetString(strconv.FormatFloat(r.Rate, 'g', -1, 64))
+	if !ok || ratio.Sign() <= 0 {
+		return nil
+	}
+	return ratio
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestExchangeRateConvert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rate     *ExchangeRate
+		amount   int
+		from     int
+		to       int
+		rounding Rounding
+		want     int
+	}{
+		{name: "exact decimal rate rounded up", rate: &ExchangeRate{Rate: 1.1}, amount: 100, from: 2, to: 2, rounding: RoundUp, want: 110},
+		{name: "exact decimal rate rounded down", rate: &ExchangeRate{Rate: 1.1}, amount: 100, from: 2, to: 2, rounding: RoundDown, want: 110},
+		{name: "fraction rounded up", rate: &ExchangeRate{Rate: 1.25}, amount: 101, from: 2, to: 2, rounding: RoundUp, want: 127},
+		{name: "fraction rounded down", rate: &ExchangeRate{Rate: 1.25}, amount: 101, from: 2, to: 2, rounding: RoundDown, want: 126},
+		{name: "finer denomination", rate: &ExchangeRate{Rate: 0.3}, amount: 7, from: 0, to: 2, rounding: RoundDown, want: 210},
+		{name: "coarser denomination rounded up", rate: &ExchangeRate{Rate: 1}, amount: 1001, from: 3, to: 2, rounding: RoundUp, want: 101},
+		{name: "coarser denomination rounded down", rate: &ExchangeRate{Rate: 1}, amount: 1009, from: 3, to: 2, rounding: RoundDown, want: 100},
+		{name: "inverse of exact rate", rate: (&ExchangeRate{Rate: 1.1}).Inverse(), amount: 110, from: 2, to: 2, rounding: RoundDown, want: 100},
+		{name: "inverse rounded up", rate: (&ExchangeRate{Rate: 3}).Inverse(), amount: 100, from: 2, to: 2, rounding: RoundUp, want: 34},
+		{name: "large amount keeps precision", rate: &ExchangeRate{Rate: 1.1}, amount: 1 << 60, from: 2, to: 2, rounding: RoundDown, want: 1268213655067531673},
+		{name: "overflow saturates", rate: &ExchangeRate{Rate: 10}, amount: math.MaxInt / 2, from: 2, to: 2, rounding: RoundDown, want: math.MaxInt},
+		{name: "invalid rate", rate: &ExchangeRate{Rate: math.NaN()}, amount: 100, from: 2, to: 2, rounding: RoundUp, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rate.Convert(tt.amount, tt.from, tt.to, tt.rounding))
+		})
+	}
+}
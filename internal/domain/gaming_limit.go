@@ -0,0 +1,127 @@
+package domain
+
+import "time"
+
+// LimitPeriod is the period responsible gaming limits are counted within, periods are calendar ones in UTC
+type LimitPeriod string
+
+const (
+	LimitPeriodDay   LimitPeriod = "day"
+	LimitPeriodWeek  LimitPeriod = "week"
+	LimitPeriodMonth LimitPeriod = "month"
+)
+
+// LimitPeriods lists every period the repository keeps counters of
+var LimitPeriods = []LimitPeriod{LimitPeriodDay, LimitPeriodWeek, LimitPeriodMonth}
+
+// Start returns the beginning of the period the moment belongs to, weeks start on Monday
+func (p LimitPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case LimitPeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case LimitPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// PeriodLimits caps the amount within the day, week and month, zero means no limit
+type PeriodLimits struct {
+	Day   int
+	Week  int
+	Month int
+}
+
+// Of returns the limit of the period
+func (l PeriodLimits) Of(p LimitPeriod) int {
+	switch p {
+	case LimitPeriodDay:
+		return l.Day
+	case LimitPeriodWeek:
+		return l.Week
+	case LimitPeriodMonth:
+		return l.Month
+	}
+	return 0
+}
+
+// IsValid reports whether the limits aren't negative
+func (l PeriodLimits) IsValid() bool {
+	return l.Day >= 0 && l.Week >= 0 && l.Month >= 0
+}
+
+// GamingLimits are responsible gaming limits of the user in the currency, in minor units of the currency
+type GamingLimits struct {
+	// Loss caps the bets minus the wins
+	Loss PeriodLimits
+	// Wager caps the bets
+	Wager PeriodLimits
+	// Deposit caps the real money added to the balance by adjustments
+	Deposit PeriodLimits
+}
+
+// Exceeded returns the counter which the bet of the amount would take over its limit, nil means the bet is allowed
+func (l GamingLimits) Exceeded(counters []GamingCounter, amount int) *GamingCounter {
+	for i, counter := range counters {
+		if limit := l.Loss.Of(counter.Period); limit > 0 && counter.Loss+amount > limit {
+			return &counters[i]
+		}
+		if limit := l.Wager.Of(counter.Period); limit > 0 && counter.Wager+amount > limit {
+			return &counters[i]
+		}
+	}
+	return nil
+}
+
+// DepositExceeded returns the counter which the deposit of the amount would take over its limit, nil means the deposit is allowed
+func (l GamingLimits) DepositExceeded(counters []GamingCounter, amount int) *GamingCounter {
+	for i, counter := range counters {
+		if limit := l.Deposit.Of(counter.Period); limit > 0 && counter.Deposit+amount > limit {
+			return &counters[i]
+		}
+	}
+	return nil
+}
+
+// GamingCounter is the money the user bet and lost in the currency within the period,
+// the repository keeps counters along with the money movement so limits are checked without scanning transactions
+type GamingCounter struct {
+	UserUID  string
+	Currency string
+	Period   LimitPeriod
+	Start    time.Time
+	Wager    int
+	// Loss is the bets minus the wins, negative loss is the net win
+	Loss int
+	// Deposit is the real money added by adjustments
+	Deposit int
+}
+
+// GamingDelta returns the change of the wager and loss counters by the transaction, rollback reverts the change
+// of the original transaction
+func GamingDelta(txn *Transaction, original *Transaction) (wager, loss int) {
+	switch txn.Type {
+	case TransactionTypeDebit:
+		return txn.Amount, txn.Amount
+	case TransactionTypeCredit:
+		return 0, -txn.Amount
+	case TransactionTypeRollback:
+		if original == nil {
+			return 0, 0
+		}
+		wager, loss = GamingDelta(original, nil)
+		return -wager, -loss
+	}
+	return 0, 0
+}
+
+// DepositDelta returns the change of the deposit counters by the transaction, the adjustment adding real money
+// is the deposit, taking money away or granting bonus money isn't
+func DepositDelta(txn *Transaction) int {
+	if txn.IsDeposit() {
+		return txn.Amount
+	}
+	return 0
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLimitPeriodStart(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC), LimitPeriodDay.Start(now))
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), LimitPeriodWeek.Start(now))
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), LimitPeriodMonth.Start(now))
+
+	// Sunday belongs to the week started on Monday before
+	sunday := time.Date(2026, time.October, 18, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), LimitPeriodWeek.Start(sunday))
+}
+
+func TestGamingLimitsExceeded(t *testing.T) {
+	t.Parallel()
+
+	limits := GamingLimits{
+		Loss:  PeriodLimits{Day: 500},
+		Wager: PeriodLimits{Week: 2000},
+	}
+	counters := []GamingCounter{
+		{Period: LimitPeriodDay, Wager: 1500, Loss: 400},
+		{Period: LimitPeriodWeek, Wager: 1500, Loss: 400},
+		{Period: LimitPeriodMonth, Wager: 1500, Loss: 400},
+	}
+
+	assert.Nil(t, limits.Exceeded(counters, 100))
+	assert.Equal(t, LimitPeriodDay, limits.Exceeded(counters, 101).Period)
+
+	// net win leaves room for loss, but not for wager
+	counters[0].Loss = -1000
+	assert.Equal(t, LimitPeriodWeek, limits.Exceeded(counters, 600).Period)
+}
+
+func TestGamingDelta(t *testing.T) {
+	t.Parallel()
+
+	debit := &Transaction{Type: TransactionTypeDebit, Amount: 100}
+	credit := &Transaction{Type: TransactionTypeCredit, Amount: 250}
+	rollback := &Transaction{Type: TransactionTypeRollback}
+
+	wager, loss := GamingDelta(debit, nil)
+	assert.Equal(t, []int{100, 100}, []int{wager, loss})
+
+	wager, loss = GamingDelta(credit, nil)
+	assert.Equal(t, []int{0, -250}, []int{wager, loss})
+
+	wager, loss = GamingDelta(rollback, debit)
+	assert.Equal(t, []int{-100, -100}, []int{wager, loss})
+
+	wager, loss = GamingDelta(rollback, credit)
+	assert.Equal(t, []int{0, 250}, []int{wager, loss})
+
+	wager, loss = GamingDelta(&Transaction{Type: TransactionTypeAdjustment, Amount: 1000}, nil)
+	assert.Equal(t, []int{0, 0}, []int{wager, loss})
+}
+
+func TestGamingLimitsDepositExceeded(t *testing.T) {
+	t.Parallel()
+
+	limits := GamingLimits{
+		Wager:   PeriodLimits{Day: 100},
+		Deposit: PeriodLimits{Month: 1000},
+	}
+	counters := []GamingCounter{
+		{Period: LimitPeriodDay, Wager: 500, Deposit: 800},
+		{Period: LimitPeriodWeek, Wager: 500, Deposit: 800},
+		{Period: LimitPeriodMonth, Wager: 500, Deposit: 800},
+	}
+
+	// wager over its limit doesn't stop the deposit
+	assert.Nil(t, limits.DepositExceeded(counters, 200))
+	assert.Equal(t, LimitPeriodMonth, limits.DepositExceeded(counters, 201).Period)
+	assert.Nil(t, GamingLimits{}.DepositExceeded(counters, 1000000))
+}
+
+func TestDepositDelta(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1000, DepositDelta(&Transaction{Type: TransactionTypeAdjustment, Amount: 1000}))
+	assert.Equal(t, 0, DepositDelta(&Transaction{Type: TransactionTypeAdjustment, Amount: -1000}))
+	assert.Equal(t, 0, DepositDelta(&Transaction{Type: TransactionTypeAdjustment, Amount: 1000, BonusAmount: 1000}))
+	assert.Equal(t, 0, DepositDelta(&Transaction{Type: TransactionTypeCredit, Amount: 1000}))
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Jackpot is a named pool in the currency, funded by contributions from bets and paid out by jackpot wins
+type Jackpot struct {
+	Key      string
+	Name     string
+	Currency string
+	// GameUID binds the pool to the game, empty means the pool is shared by all games
+	GameUID string
+	Amount  int
+	// ContributionPercent is the part of each bet added to the pool
+	ContributionPercent float64
+	IsActive            bool
+}
+
+// Contribution returns the part of the bet which goes to the pool
+func (j *Jackpot) Contribution(bet int) int {
+	return int(float64(bet) * j.ContributionPercent / 100)
+}
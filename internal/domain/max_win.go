@@ -0,0 +1,22 @@
+package domain
+
+type MaxWinMode string
+
+const (
+	// MaxWinModeCap credits only the part of the win within the limit
+	MaxWinModeCap MaxWinMode = "cap"
+	// MaxWinModeReject rejects the whole win exceeding the limit
+	MaxWinModeReject MaxWinMode = "reject"
+)
+
+// MaxWinLimit resolves the win limit of the game, session limit overrides user limit in the currency,
+// which overrides currency default, zero means unlimited
+func MaxWinLimit(cur *Currency, user *User, session *Session) int {
+	if session != nil && session.MaxWin > 0 {
+		return session.MaxWin
+	}
+	if user != nil && user.MaxWin[cur.Code] > 0 {
+		return user.MaxWin[cur.Code]
+	}
+	return cur.MaxWin
+}
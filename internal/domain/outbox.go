@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	// OutboxStatusDead is the event given up after the last delivery attempt, it's kept for investigation
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// outboxMaxBackoffShift caps the doubling of the retry delay
+const outboxMaxBackoffShift = 10
+
+// OutboxEvent is the wallet movement stored along with its transaction to be published to downstream services,
+// the event is delivered at least once, so consumers tell repeated events by UID
+type OutboxEvent struct {
+	UID         string
+	Transaction Transaction
+	Status      OutboxStatus
+	// Attempts is the number of failed deliveries, LastError is the error of the last one
+	Attempts  int
+	LastError string
+	// NextAttemptAt is the moment the pending event is published at
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+// NewOutboxEvent makes the pending event of the stored transaction
+func NewOutboxEvent(txn *Transaction) *OutboxEvent {
+	return &OutboxEvent{
+		UID:           GenUID(),
+		Transaction:   *txn,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: txn.CreatedAt,
+		CreatedAt:     txn.CreatedAt,
+	}
+}
+
+// Delivered marks the event published
+func (e *OutboxEvent) Delivered(now time.Time) {
+	e.Status = OutboxStatusDelivered
+	e.DeliveredAt = now
+}
+
+// Failed registers the failed delivery, the event is retried after the backoff doubled with every attempt,
+// the event failed maxAttempts times is dead
+func (e *OutboxEvent) Failed(err error, now time.Time, backoff time.Duration, maxAttempts int) {
+	e.Attempts++
+	e.LastError = err.Error()
+	if e.Attempts >= maxAttempts {
+		e.Status = OutboxStatusDead
+		return
+	}
+	e.NextAttemptAt = now.Add(backoff << min(e.Attempts-1, outboxMaxBackoffShift))
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOutboxEventFailed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+	event := NewOutboxEvent(&Transaction{UID: "1", Type: TransactionTypeDebit, CreatedAt: now})
+	assert.Equal(t, OutboxStatusPending, event.Status)
+	assert.Equal(t, now, event.NextAttemptAt)
+
+	event.Failed(errors.New("timeout"), now, time.Second, 3)
+	assert.Equal(t, OutboxStatusPending, event.Status)
+	assert.Equal(t, now.Add(time.Second), event.NextAttemptAt)
+
+	// the delay doubles with every attempt
+	event.Failed(errors.New("timeout"), now, time.Second, 3)
+	assert.Equal(t, now.Add(2*time.Second), event.NextAttemptAt)
+
+	event.Failed(errors.New("bad gateway"), now, time.Second, 3)
+	assert.Equal(t, OutboxStatusDead, event.Status)
+	assert.Equal(t, 3, event.Attempts)
+	assert.Equal(t, "bad gateway", event.LastError)
+}
@@ -1,21 +1,32 @@
 package domain
 
 type ProcessBalanceReq struct {
+	// ProviderUID is the game provider authenticated by the request sign, it can't reach sessions of another provider
+	ProviderUID    string
 	GameSessionUID string
 	Currency       string
 }
 
 type ProcessBalanceRes struct {
-	UserUID      string
-	UserNick     string
-	Amount       int
+	UserUID  string
+	UserNick string
+	Amount   int
+	// RealAmount and BonusAmount are the parts of Amount, Wagering is the amount left to bet to turn bonus money into real
+	RealAmount   int
+	BonusAmount  int
+	Wagering     int
 	Currency     string
 	Denomination int
 	MaxWin       int
 	JpKey        string
+	Jackpots     []Jackpot
+	// RealityCheck asks the provider to show the reality check dialog, nil when it isn't due
+	RealityCheck *RealityCheck
 }
 
 type ProcessDebitCreditRollbackReq struct {
+	// ProviderUID scopes the provider transaction id, ids of different providers don't clash
+	ProviderUID    string
 	TransactionUID string
 	GameSessionUID string
 	UserUID        string
@@ -27,21 +38,59 @@ type ProcessDebitCreditRollbackReq struct {
 	JpKey          string
 	SpinMeta       string
 	BetMeta        string
+	BetUID         string
+	// CampaignUID is the free rounds campaign which pays the debit or which the credit is the win of
+	CampaignUID string
 }
 
 type ProcessDebitCreditRollbackRes struct {
 	TransactionUID string
 	UserNick       string
 	Amount         int
+	Balance        int
 	Currency       string
 	Denomination   int
 	MaxWin         int
+	// RealityCheck asks the provider to show the reality check dialog, nil when it isn't due
+	RealityCheck *RealityCheck
+}
+
+// ProcessDebitCreditReq is the bet and the win of the instant round, both are stored under the provider transaction id
+type ProcessDebitCreditReq struct {
+	ProviderUID    string
+	TransactionUID string
+	GameSessionUID string
+	UserUID        string
+	UserNick       string
+	BetAmount      int
+	WinAmount      int
+	Currency       string
+	Denomination   int
+	MaxWin         int
+	JpKey          string
+	SpinMeta       string
+	BetMeta        string
+	BetUID         string
+}
+
+type ProcessDebitCreditRes struct {
+	DebitTransactionUID  string
+	CreditTransactionUID string
+	UserNick             string
+	BetAmount            int
+	WinAmount            int
+	Balance              int
+	Currency             string
+	Denomination         int
+	MaxWin               int
 }
 
 type ProcessApiDataApi string
 
 const (
 	ProcessApiDataApiRoundComplete ProcessApiDataApi = "roundComplete"
+	// ProcessApiDataApiRealityCheck confirms the user has seen the reality check dialog
+	ProcessApiDataApiRealityCheck ProcessApiDataApi = "realityCheck"
 )
 
 type ProcessApiDataData struct {
@@ -49,6 +98,7 @@ type ProcessApiDataData struct {
 }
 
 type ProcessMetaDataReq struct {
+	ProviderUID    string
 	UserUID        string
 	GameSessionUID string
 	Currency       string
@@ -57,6 +107,39 @@ type ProcessMetaDataReq struct {
 }
 
 type ProcessMetaDataRes struct {
-	Api  ProcessApiDataApi
-	Data string
+	Api          ProcessApiDataApi
+	Round        *Round
+	RealityCheck *RealityCheck
+}
+
+type ProcessBatchMode string
+
+const (
+	// ProcessBatchModeAtomic stores all the items or none of them
+	ProcessBatchModeAtomic ProcessBatchMode = "atomic"
+	// ProcessBatchModeBestEffort processes every item on its own, failed items don't stop the rest
+	ProcessBatchModeBestEffort ProcessBatchMode = "bestEffort"
+)
+
+// ProcessBatchItemReq is the debit, credit or rollback of the batch, told by the transaction type
+type ProcessBatchItemReq struct {
+	Type TransactionType
+	Data ProcessDebitCreditRollbackReq
+}
+
+type ProcessBatchReq struct {
+	Mode  ProcessBatchMode
+	Items []ProcessBatchItemReq
+}
+
+// ProcessBatchItemRes is the result of the batch item in the order of the request, failed item has error only
+type ProcessBatchItemRes struct {
+	Type   TransactionType
+	Result *ProcessDebitCreditRollbackRes
+	Error  error
+}
+
+type ProcessBatchRes struct {
+	Mode  ProcessBatchMode
+	Items []ProcessBatchItemRes
 }
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// MaxProviderSecrets is the number of secrets active at once, the current one and the previous one during key rotation
+const MaxProviderSecrets = 2
+
+// SignScheme is the algorithm the provider signs requests with
+type SignScheme string
+
+const (
+	// SignSchemeHMACSHA256 signs method, path, timestamp, nonce and body, it's the default one
+	SignSchemeHMACSHA256 SignScheme = "hmac-sha256"
+	// SignSchemeMD5 is the legacy md5 of body and secret without replay protection, it's used only when opted in
+	SignSchemeMD5 SignScheme = "md5"
+)
+
+func (s SignScheme) IsValid() bool {
+	return s == SignSchemeHMACSHA256 || s == SignSchemeMD5
+}
+
+// AmountFormat is the json encoding of the amounts the provider sends and receives
+type AmountFormat string
+
+const (
+	// AmountFormatMinor is the integer number of minor units in the request denomination, it's the default one
+	AmountFormatMinor AmountFormat = "minor"
+	// AmountFormatDecimal is the decimal string of major units, e.g. "12.34"
+	AmountFormatDecimal AmountFormat = "decimal"
+)
+
+func (f AmountFormat) IsValid() bool {
+	return f == AmountFormatMinor || f == AmountFormatDecimal
+}
+
+// Provider is the game provider calling the games processor api
+type Provider struct {
+	UID  string
+	Name string
+	// Secrets are the signing keys, the first one is current, the rest are accepted until the rotation is finished
+	Secrets []string
+	// AllowedIPs are addresses or CIDR ranges requests are accepted from, empty means any
+	AllowedIPs []string
+	Enabled    bool
+	// Currencies the provider is allowed to operate with, empty means any
+	Currencies []string
+	SignScheme SignScheme
+	// AmountFormat is the encoding of the amounts in the games processor api
+	AmountFormat AmountFormat
+}
+
+// Scheme returns the sign scheme of the provider, hmac is used when it isn't set
+func (p *Provider) Scheme() SignScheme {
+	if p.SignScheme == "" {
+		return SignSchemeHMACSHA256
+	}
+	return p.SignScheme
+}
+
+// Format returns the amount format of the provider, minor units are used when it isn't set
+func (p *Provider) Format() AmountFormat {
+	if p.AmountFormat == "" {
+		return AmountFormatMinor
+	}
+	return p.AmountFormat
+}
+
+// IsIPAllowed reports whether the request from the address is accepted
+func (p *Provider) IsIPAllowed(ip string) bool {
+	if len(p.AllowedIPs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, allowed := range p.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			prefix, err := netip.ParsePrefix(allowed)
+			if err == nil && prefix.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		allowedAddr, err := netip.ParseAddr(allowed)
+		if err == nil && allowedAddr.Unmap() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCurrencyAllowed reports whether the provider may operate with the currency
+func (p *Provider) IsCurrencyAllowed(code string) bool {
+	if len(p.Currencies) == 0 {
+		return true
+	}
+	for _, cur := range p.Currencies {
+		if cur == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RotateSecret makes the secret current and keeps the previous one active until the next rotation
+func (p *Provider) RotateSecret(secret string) {
+	secrets := append([]string{secret}, p.Secrets...)
+	if len(secrets) > MaxProviderSecrets {
+		secrets = secrets[:MaxProviderSecrets]
+	}
+	p.Secrets = secrets
+}
+
+// SignPayload is the signed part of the request
+type SignPayload struct {
+	Method string
+	// Path is the request uri with query
+	Path string
+	// Timestamp is unix time in seconds the request was signed at
+	Timestamp string
+	// Nonce is the unique value of the request used once
+	Nonce string
+	Body  []byte
+	// Response marks the payload of the response, its sign differs from the sign of the request with the same fields,
+	// so the signed response can't be replayed as the request
+	Response bool
+}
+
+// ProviderAuthReq is the incoming request of the provider to authenticate
+type ProviderAuthReq struct {
+	ProviderUID string
+	IP          string
+	Payload     SignPayload
+	Sign        string
+}
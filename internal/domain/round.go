@@ -0,0 +1,72 @@
+package domain
+
+type RoundStatus string
+
+const (
+	RoundStatusOpen   RoundStatus = "open"
+	RoundStatusClosed RoundStatus = "closed"
+)
+
+// RoundTransaction is a money movement made within the game round
+type RoundTransaction struct {
+	UID                  string
+	Type                 TransactionType
+	Amount               int
+	ParentTransactionUID string
+}
+
+// Round groups all transactions of one game round, identified by bet id of the game provider
+type Round struct {
+	// ProviderUID is the game provider of the round, bet ids are unique within the provider only
+	ProviderUID string
+	UID         string
+	SessionUID  string
+	UserUID     string
+	Currency    string
+	Status      RoundStatus
+	TotalBet    int
+	TotalWin    int
+	// TotalBonusBet is the bonus money part of TotalBet, the wins of the round are split by it
+	TotalBonusBet int
+	// Transactions are the stored transactions of the round, read apart from the totals kept by money movements
+	Transactions []RoundTransaction
+}
+
+func (r *Round) IsClosed() bool {
+	return r.Status == RoundStatusClosed
+}
+
+// Reconcile checks that round totals match the sum of its stored transactions, so a money movement which updated
+// the totals without storing its transaction or the other way around is caught before the round is closed,
+// rollbacks are subtracted from the totals of the transaction type they compensate
+func (r *Round) Reconcile() bool {
+	types := make(map[string]TransactionType, len(r.Transactions))
+	for _, txn := range r.Transactions {
+		types[txn.UID] = txn.Type
+	}
+
+	bet, win := 0, 0
+	for _, txn := range r.Transactions {
+		switch txn.Type {
+		case TransactionTypeDebit:
+			bet += txn.Amount
+		case TransactionTypeCredit:
+			win += txn.Amount
+		case TransactionTypeRollback:
+			if txn.ParentTransactionUID == "" {
+				// tombstone doesn't move money
+				continue
+			}
+			switch types[txn.ParentTransactionUID] {
+			case TransactionTypeDebit:
+				bet -= txn.Amount
+			case TransactionTypeCredit:
+				win -= txn.Amount
+			default:
+				return false
+			}
+		}
+	}
+
+	return bet == r.TotalBet && win == r.TotalWin
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRoundReconcile(t *testing.T) {
+	t.Parallel()
+
+	transactions := []RoundTransaction{
+		{UID: "bet", Type: TransactionTypeDebit, Amount: 100},
+		{UID: "win", Type: TransactionTypeCredit, Amount: 250},
+		{UID: "bet-2", Type: TransactionTypeDebit, Amount: 50},
+		{UID: "refund", Type: TransactionTypeRollback, Amount: 50, ParentTransactionUID: "bet-2"},
+		{UID: "tombstone", Type: TransactionTypeRollback},
+	}
+
+	round := &Round{TotalBet: 100, TotalWin: 250, Transactions: transactions}
+	assert.True(t, round.Reconcile())
+
+	// totals moved without the stored transaction
+	round = &Round{TotalBet: 100, TotalWin: 300, Transactions: transactions}
+	assert.False(t, round.Reconcile())
+
+	// transaction stored without moving the totals
+	round = &Round{TotalBet: 100, TotalWin: 250, Transactions: append(transactions, RoundTransaction{UID: "win-2", Type: TransactionTypeCredit, Amount: 10})}
+	assert.False(t, round.Reconcile())
+
+	// rollback of the transaction outside of the round
+	round = &Round{TotalBet: 100, TotalWin: 250, Transactions: append(transactions, RoundTransaction{UID: "refund-2", Type: TransactionTypeRollback, Amount: 10, ParentTransactionUID: "unknown"})}
+	assert.False(t, round.Reconcile())
+}
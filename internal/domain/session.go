@@ -1,6 +1,105 @@
 package domain
 
+import "time"
+
+type SessionStatus string
+
+const (
+	SessionStatusOpen    SessionStatus = "open"
+	SessionStatusClosed  SessionStatus = "closed"
+	SessionStatusExpired SessionStatus = "expired"
+)
+
 type Session struct {
-	UID     string
-	UserUID string
+	UID         string
+	UserUID     string
+	GameUID     string
+	ProviderUID string
+	// Currency is the game currency and WalletCurrency is the currency of the balance the session plays from,
+	// they differ when the game currency is converted into the wallet by the exchange rate
+	Currency       string
+	WalletCurrency string
+	// MaxWin overrides user and currency win limits for the game session
+	MaxWin         int
+	Status         SessionStatus
+	CreatedAt      time.Time
+	LastActivityAt time.Time
+	// ExpiresAt is the moment the session stops accepting bets, the activity moves it forward, zero means never
+	ExpiresAt time.Time
+	// TotalBet and TotalWin are the money bet and won in the session in minor units of the wallet currency,
+	// the repository keeps them along with the balance
+	TotalBet int
+	TotalWin int
+	// RealityCheckedAt is the moment the user last confirmed the reality check, zero means never
+	RealityCheckedAt time.Time
+}
+
+// IsActive reports whether the session accepts new bets at the moment
+func (s *Session) IsActive(now time.Time) bool {
+	if s.Status == SessionStatusClosed || s.Status == SessionStatusExpired {
+		return false
+	}
+	return s.ExpiresAt.IsZero() || now.Before(s.ExpiresAt)
+}
+
+// Elapsed returns the time passed since the session start
+func (s *Session) Elapsed(now time.Time) time.Duration {
+	return now.Sub(s.CreatedAt)
+}
+
+// NetResult returns the wins minus the bets of the session
+func (s *Session) NetResult() int {
+	return s.TotalWin - s.TotalBet
+}
+
+// TimeLimitReached reports whether the session lasts the limit or longer, zero limit means no limit
+func (s *Session) TimeLimitReached(now time.Time, limit time.Duration) bool {
+	return limit > 0 && s.Elapsed(now) >= limit
+}
+
+// RealityCheckDue reports whether the interval passed since the last reality check or the session start
+func (s *Session) RealityCheckDue(now time.Time, interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+	from := s.RealityCheckedAt
+	if from.IsZero() {
+		from = s.CreatedAt
+	}
+	return now.Sub(from) >= interval
+}
+
+// SessionDelta returns the change of the session bets and wins the transaction makes,
+// rollback takes back the original transaction
+func SessionDelta(txn *Transaction, original *Transaction) (bet, win int) {
+	switch txn.Type {
+	case TransactionTypeDebit:
+		return txn.Amount, 0
+	case TransactionTypeCredit:
+		return 0, txn.Amount
+	case TransactionTypeRollback:
+		if original != nil {
+			bet, win = SessionDelta(original, nil)
+			return -bet, -win
+		}
+	}
+	return 0, 0
+}
+
+// RealityCheck reminds the user of the time and the money spent in the game session
+type RealityCheck struct {
+	Elapsed time.Duration
+	// NetResult is the wins minus the bets of the session in minor units of the game currency and denomination
+	NetResult    int
+	Currency     string
+	Denomination int
+}
+
+// SessionOpenReq is the request to launch the game for the user
+type SessionOpenReq struct {
+	UserUID     string
+	GameUID     string
+	ProviderUID string
+	Currency    string
+	MaxWin      int
 }
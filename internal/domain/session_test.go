@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSessionRealityCheckDue(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+	session := &Session{CreatedAt: start}
+
+	assert.False(t, session.RealityCheckDue(start.Add(time.Hour), 0))
+	assert.False(t, session.RealityCheckDue(start.Add(29*time.Minute), 30*time.Minute))
+	assert.True(t, session.RealityCheckDue(start.Add(30*time.Minute), 30*time.Minute))
+
+	// the next check is due the whole interval after the confirmed one
+	session.RealityCheckedAt = start.Add(40 * time.Minute)
+	assert.False(t, session.RealityCheckDue(start.Add(time.Hour), 30*time.Minute))
+	assert.True(t, session.RealityCheckDue(start.Add(70*time.Minute), 30*time.Minute))
+}
+
+func TestSessionTimeLimitReached(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+	session := &Session{CreatedAt: start}
+
+	assert.False(t, session.TimeLimitReached(start.Add(24*time.Hour), 0))
+	assert.False(t, session.TimeLimitReached(start.Add(59*time.Minute), time.Hour))
+	assert.True(t, session.TimeLimitReached(start.Add(time.Hour), time.Hour))
+}
+
+func TestSessionDelta(t *testing.T) {
+	t.Parallel()
+
+	debit := &Transaction{Type: TransactionTypeDebit, Amount: 100}
+	credit := &Transaction{Type: TransactionTypeCredit, Amount: 250}
+	rollback := &Transaction{Type: TransactionTypeRollback}
+
+	bet, win := SessionDelta(debit, nil)
+	assert.Equal(t, []int{100, 0}, []int{bet, win})
+
+	bet, win = SessionDelta(credit, nil)
+	assert.Equal(t, []int{0, 250}, []int{bet, win})
+
+	bet, win = SessionDelta(rollback, credit)
+	assert.Equal(t, []int{0, -250}, []int{bet, win})
+
+	bet, win = SessionDelta(&Transaction{Type: TransactionTypeAdjustment, Amount: 50}, nil)
+	assert.Equal(t, []int{0, 0}, []int{bet, win})
+}
@@ -1,19 +1,109 @@
 package domain
 
+import "time"
+
 type TransactionType string
 
 const (
 	TransactionTypeDebit    TransactionType = "debit"
 	TransactionTypeCredit   TransactionType = "credit"
 	TransactionTypeRollback TransactionType = "rollback"
+	// TransactionTypeAdjustment is the manual balance correction made by an operator
+	TransactionTypeAdjustment TransactionType = "adjustment"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionStatusCommitted  TransactionStatus = "committed"
+	TransactionStatusRolledBack TransactionStatus = "rolled_back"
+	// TransactionStatusTombstone marks rollback of the transaction which never reached us
+	TransactionStatusTombstone TransactionStatus = "tombstone"
 )
 
 type Transaction struct {
-	UID          string
-	UserUID      string
-	SessionUID   string
-	Amount       int
+	UID string
+	// ProviderUID is the game provider the transaction was received from
+	ProviderUID string
+	// ProviderTransactionUID is the transaction id sent by the game provider,
+	// used as idempotency key for retried requests within the provider
+	ProviderTransactionUID string
+	UserUID                string
+	SessionUID             string
+	// RoundUID is the bet id of the game round sent by the game provider
+	RoundUID string
+	Amount   int
+	// CappedAmount is the part of the win which wasn't credited due to max win limit
+	CappedAmount int
 	Currency     string
 	Denomination int
 	Type         TransactionType
+	Status       TransactionStatus
+	// ParentTransactionUID links rollback transaction to the rolled back one
+	ParentTransactionUID string
+	// RollbackTransactionUID links rolled back transaction to its rollback
+	RollbackTransactionUID string
+	// OriginalAmount and OriginalCurrency are the requested amount before the max win cap in the currency of the game,
+	// set when it was converted into the wallet currency by ExchangeRate
+	OriginalAmount   int
+	OriginalCurrency string
+	ExchangeRate     float64
+	// JackpotKey is the pool the debit contributed to or the credit was paid out from
+	JackpotKey          string
+	JackpotContribution int
+	// CampaignUID is the free rounds campaign the debit is paid by or the credit is the win of
+	CampaignUID string
+	// BalanceBefore and Balance are snapshots of the user balance right before and after the transaction
+	BalanceBefore int
+	Balance       int
+	// BonusAmount is the bonus money part of Amount and BonusBalance is the bonus money part of Balance,
+	// manual adjustment with BonusAmount set moves bonus money and adds Wagering to the wagering requirement,
+	// credit with Wagering is the bonus money win of free rounds campaign
+	BonusAmount  int
+	BonusBalance int
+	Wagering     int
+	// BonusOrder is the order the debit spends real and bonus money in, it isn't stored
+	BonusOrder BonusOrder
+	// GamingLimits are the responsible gaming limits the debit is checked against within the movement, they aren't stored
+	GamingLimits *GamingLimits
+	// Operator and Reason are set for manual adjustments only
+	Operator  string
+	Reason    string
+	CreatedAt time.Time
+	Meta      TransactionMeta
+}
+
+// TransactionMeta is the raw bet context sent by the game provider, stored as is for compliance
+type TransactionMeta struct {
+	SpinMeta     string
+	BetMeta      string
+	JpKey        string
+	Denomination int
+	MaxWin       int
+}
+
+// IsRolledBack reports whether the transaction was already compensated by a rollback
+func (t *Transaction) IsRolledBack() bool {
+	return t.Status == TransactionStatusRolledBack
+}
+
+// RequestedAmount is the amount requested by the game provider in its currency, before the max win cap and the conversion
+func (t *Transaction) RequestedAmount() int {
+	if t.OriginalCurrency != "" {
+		return t.OriginalAmount
+	}
+	return t.Amount + t.CappedAmount
+}
+
+// RequestedCurrency is the currency the amount was requested in by the game provider, before the conversion
+func (t *Transaction) RequestedCurrency() string {
+	if t.OriginalCurrency != "" {
+		return t.OriginalCurrency
+	}
+	return t.Currency
+}
+
+// IsDeposit reports whether the transaction is the adjustment adding real money to the balance
+func (t *Transaction) IsDeposit() bool {
+	return t.Type == TransactionTypeAdjustment && t.Amount > 0 && t.BonusAmount == 0
 }
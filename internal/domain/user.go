@@ -1,6 +1,25 @@
 package domain
 
+import "time"
+
 type User struct {
 	UID  string
 	Nick string
+	// MaxWin overrides currency win limits for the user, by currency code
+	MaxWin map[string]int
+	// Limits are responsible gaming limits of the user, by currency code
+	Limits map[string]GamingLimits
+	// SelfExcludedUntil blocks bets of the user who excluded themselves from gaming, zero means not excluded
+	SelfExcludedUntil time.Time
+	// CoolOffUntil blocks bets during the break the user takes from gaming, zero means no break
+	CoolOffUntil time.Time
+	// SessionLimit is the longest game session the user can bet in, zero means no limit
+	SessionLimit time.Duration
+	// RealityCheckInterval is how often the user is reminded of the time and money spent in the session, zero means never
+	RealityCheckInterval time.Duration
+}
+
+// IsExcluded reports whether the user can't bet at the moment because of self-exclusion or cool-off
+func (u *User) IsExcluded(now time.Time) bool {
+	return now.Before(u.SelfExcludedUntil) || now.Before(u.CoolOffUntil)
 }
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// currencyCodeRe is the format of ISO 4217 alphabetic currency code
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Violation is the failed validation rule of the request field
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rule checks the value of the field
+type Rule[T any] struct {
+	Name    string
+	Message string
+	Check   func(v T) bool
+}
+
+// Check is the field validation prepared by Field
+type Check func() *Violation
+
+// Field binds the field name and value to the rules, the first failed rule is reported
+func Field[T any](name string, value T, rules ...Rule[T]) Check {
+	return func() *Violation {
+		for _, rule := range rules {
+			if !rule.Check(value) {
+				return &Violation{Field: name, Rule: rule.Name, Message: rule.Message}
+			}
+		}
+		return nil
+	}
+}
+
+// When applies the checks only if the condition holds
+func When(condition bool, checks ...Check) Check {
+	return func() *Violation {
+		if !condition {
+			return nil
+		}
+		for _, check := range checks {
+			if v := check(); v != nil {
+				return v
+			}
+		}
+		return nil
+	}
+}
+
+// Validate runs all the checks and collects violations
+func Validate(checks ...Check) []Violation {
+	var violations []Violation
+	for _, check := range checks {
+		if v := check(); v != nil {
+			violations = append(violations, *v)
+		}
+	}
+	return violations
+}
+
+// Required fails for empty string
+var Required = Rule[string]{
+	Name:    "required",
+	Message: "value is required",
+	Check:   func(v string) bool { return v != "" },
+}
+
+// CurrencyCode fails for value not matching ISO 4217 alphabetic code format
+var CurrencyCode = Rule[string]{
+	Name:    "currency",
+	Message: "value must be 3 uppercase letters currency code",
+	Check:   func(v string) bool { return currencyCodeRe.MatchString(v) },
+}
+
+// Positive fails for zero and negative numbers
+var Positive = Rule[int]{
+	Name:    "positive",
+	Message: "value must be greater than zero",
+	Check:   func(v int) bool { return v > 0 },
+}
+
+// NotNegative fails for negative numbers
+var NotNegative = Rule[int]{
+	Name:    "notNegative",
+	Message: "value must not be negative",
+	Check:   func(v int) bool { return v >= 0 },
+}
+
+// Max fails for numbers greater than the limit
+func Max(limit int) Rule[int] {
+	return Rule[int]{
+		Name:    "max",
+		Message: fmt.Sprintf("value must not be greater than %d", limit),
+		Check:   func(v int) bool { return v <= limit },
+	}
+}
+
+// OneOf fails for value not in the list
+func OneOf[T comparable](values ...T) Rule[T] {
+	return Rule[T]{
+		Name:    "oneOf",
+		Message: fmt.Sprintf("value must be one of %v", values),
+		Check: func(v T) bool {
+			for _, value := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
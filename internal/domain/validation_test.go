@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid fields", func(t *testing.T) {
+		violations := Validate(
+			Field("currency", "USD", Required, CurrencyCode),
+			Field("amount", 100, Positive),
+		)
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("first failed rule of every field is reported", func(t *testing.T) {
+		violations := Validate(
+			Field("currency", "", Required, CurrencyCode),
+			Field("amount", -1, NotNegative, Positive),
+			Field("api", "debit", OneOf("credit", "rollback")),
+			Field("items", 101, Positive, Max(100)),
+		)
+
+		assert.Equal(t, []Violation{
+			{Field: "currency", Rule: "required", Message: "value is required"},
+			{Field: "amount", Rule: "notNegative", Message: "value must not be negative"},
+			{Field: "api", Rule: "oneOf", Message: "value must be one of [credit rollback]"},
+			{Field: "items", Rule: "max", Message: "value must not be greater than 100"},
+		}, violations)
+	})
+
+	t.Run("conditional checks", func(t *testing.T) {
+		violations := Validate(
+			When(false, Field("currency", "usd", CurrencyCode)),
+			When(true, Field("gameSessionId", "", Required)),
+		)
+
+		assert.Equal(t, []Violation{
+			{Field: "gameSessionId", Rule: "required", Message: "value is required"},
+		}, violations)
+	})
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	// errors prefix
+	balanceErrorSource = "[repository.memory.balance]"
+)
+
+var (
+	// errTransactionRolledBack signals that provider transaction arrived after its rollback
+	errTransactionRolledBack = errors.New("provider transaction already rolled back")
+	// errInsufficientFunds signals that balance can't cover the movement
+	errInsufficientFunds = errors.New("insufficient funds")
+	// errTransactionConflict signals that replayed provider transaction doesn't match the stored one
+	errTransactionConflict = errors.New("provider transaction conflicts with the stored one")
+)
+
+func (mr *Repo) BalanceGetByUserUIDAndCurrency(_ context.Context, userUID, currency string) (*domain.Balance, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	balance, ok := mr.balances[balanceKey{userUID: userUID, currency: currency}]
+	if !ok {
+		mr.logger.Error("failed to find balance", "userUid", userUID, "currency", currency)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &balance, nil
+}
+
+func (mr *Repo) BalanceCreate(_ context.Context, balance *domain.Balance) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	key := balanceKey{userUID: balance.UserUID, currency: balance.Currency}
+	if _, ok := mr.balances[key]; ok {
+		mr.logger.Error("failed to create balance", "userUid", balance.UserUID, "currency", balance.Currency, "error", errDuplicate)
+		return domain.NewError(balanceErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.balances[key] = *balance
+	return nil
+}
+
+func (mr *Repo) BalanceListByUserUID(_ context.Context, userUID string) ([]domain.Balance, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	balances := make([]domain.Balance, 0)
+	for key, balance := range mr.balances {
+		if key.userUID == userUID {
+			balances = append(balances, balance)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Currency < balances[j].Currency
+	})
+	return balances, nil
+}
+
+// BalanceAdjust applies manual correction of user balance, the balance can't become negative
+func (mr *Repo) BalanceAdjust(_ context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeAdjustment
+
+	required := 0
+	if txn.Amount < 0 {
+		required = -txn.Amount
+	}
+
+	result, err := mr.balanceMove(&draft, txn.Amount, required)
+	if err != nil {
+		mr.logger.Error("failed to adjust balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrAdjustment)
+	}
+	return result, nil
+}
+
+// BalanceDecrementByUserUIDAndCurrency debits user balance, replayed provider transaction returns the original one
+func (mr *Repo) BalanceDecrementByUserUIDAndCurrency(_ context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeDebit
+
+	result, err := mr.balanceMove(&draft, -txn.Amount, txn.Amount)
+	if err != nil {
+		mr.logger.Error("failed to decrement balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrDecrement)
+	}
+	return result, nil
+}
+
+// BalanceIncrementByUserUIDAndCurrency credits user balance, replayed provider transaction returns the original one
+func (mr *Repo) BalanceIncrementByUserUIDAndCurrency(_ context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeCredit
+
+	result, err := mr.balanceMove(&draft, txn.Amount, 0)
+	if err != nil {
+		mr.logger.Error("failed to increment balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrIncrement)
+	}
+	return result, nil
+}
+
+// balanceMoveError wraps error of balance movement keeping business error codes
+func balanceMoveError(err error, code string) *domain.Error {
+	switch {
+	case errors.Is(err, errTransactionRolledBack):
+		code = domain.ErrTransactionRolledBack
+	case errors.Is(err, errRoundClosed):
+		code = domain.ErrRoundClosed
+	case errors.Is(err, errRoundUser):
+		code = domain.ErrRoundUser
+	case errors.Is(err, errJackpotPayout):
+		code = domain.ErrJackpotPayout
+	case errors.Is(err, errTransactionConflict):
+		code = domain.ErrTransactionConflict
+	case errors.Is(err, errCampaignUnavailable):
+		code = domain.ErrCampaignUnavailable
+	case errors.Is(err, errGamingLimitExceeded):
+		code = domain.ErrGamingLimitExceeded
+	case errors.Is(err, errDepositLimitExceeded):
+		code = domain.ErrDepositLimitExceeded
+	}
+	return domain.NewError(balanceErrorSource).SetCode(code).Add(err)
+}
+
+// balanceMove applies delta to the balance holding at least required amount and stores the transaction atomically,
+// if the provider transaction was already processed the stored transaction is returned without moving money
+func (mr *Repo) balanceMove(txn *domain.Transaction, delta, required int) (*domain.Transaction, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	return mr.balanceMoveLocked(txn, delta, required)
+}
+
+// balanceMoveLocked is balanceMove for the caller holding write lock
+func (mr *Repo) balanceMoveLocked(txn *domain.Transaction, delta, required int) (*domain.Transaction, error) {
+	if txn.ProviderTransactionUID != "" {
+		if replayed, ok := mr.transactionFindByProviderUID(txn.ProviderUID, txn.ProviderTransactionUID, txn.Type); ok {
+			mr.logger.Info("provider transaction replayed", "providerTransactionUid", replayed.ProviderTransactionUID, "uid", replayed.UID)
+			return replayed, nil
+		}
+		// rollback could come before the transaction itself, late transaction must not move money
+		if _, ok := mr.transactionFindByProviderUID(txn.ProviderUID, txn.ProviderTransactionUID, domain.TransactionTypeRollback); ok {
+			return nil, errTransactionRolledBack
+		}
+	}
+
+	key := balanceKey{userUID: txn.UserUID, currency: txn.Currency}
+	balance, ok := mr.balances[key]
+	if !ok {
+		return nil, errNotFound
+	}
+	if balance.Amount < required {
+		return nil, errInsufficientFunds
+	}
+
+	// everything is checked before the first change, so failed movement leaves no traces
+	if err := mr.gamingLimitCheck(txn); err != nil {
+		return nil, err
+	}
+	if err := mr.roundCheck(txn); err != nil {
+		return nil, err
+	}
+	jackpotDelta, err := mr.jackpotDelta(txn)
+	if err != nil {
+		return nil, err
+	}
+	if err = mr.campaignCheck(txn); err != nil {
+		return nil, err
+	}
+
+	txn.UID = domain.GenUID()
+	txn.Denomination = balance.Denomination
+	txn.Status = domain.TransactionStatusCommitted
+	txn.CreatedAt = time.Now()
+	balance.Book(txn, delta, nil, mr.roundFind(txn))
+	mr.balances[key] = balance
+	mr.transactionStore(txn)
+
+	switch txn.Type {
+	case domain.TransactionTypeDebit:
+		mr.roundApply(txn, txn.Amount, txn.BonusAmount, 0)
+	case domain.TransactionTypeCredit:
+		mr.roundApply(txn, 0, 0, txn.Amount)
+	}
+	mr.jackpotMove(txn.JackpotKey, txn.Currency, jackpotDelta)
+	mr.campaignApply(txn)
+	mr.gamingCounterApply(txn, nil)
+	mr.sessionApply(txn, nil)
+	mr.outboxAdd(txn)
+
+	return transactionCopy(txn), nil
+}
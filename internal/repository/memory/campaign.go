@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	// errors prefix
+	campaignErrorSource = "[repository.memory.campaign]"
+)
+
+// errCampaignUnavailable signals that the campaign can't pay the free round
+var errCampaignUnavailable = errors.New("campaign not found, not active or has no rounds left")
+
+func (mr *Repo) CampaignGetByUID(_ context.Context, uid string) (*domain.Campaign, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	campaign, ok := mr.campaigns[uid]
+	if !ok {
+		mr.logger.Error("failed to find campaign", "uid", uid)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &campaign, nil
+}
+
+// CampaignList searches campaigns by the filter, newest first
+func (mr *Repo) CampaignList(_ context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	campaigns := make([]domain.Campaign, 0)
+	for _, campaign := range mr.campaigns {
+		if (filter.UserUID != "" && campaign.UserUID != filter.UserUID) || (filter.Status != "" && campaign.Status != filter.Status) {
+			continue
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		if !campaigns[i].CreatedAt.Equal(campaigns[j].CreatedAt) {
+			return campaigns[i].CreatedAt.After(campaigns[j].CreatedAt)
+		}
+		return campaigns[i].UID > campaigns[j].UID
+	})
+	return campaigns, nil
+}
+
+func (mr *Repo) CampaignCreate(_ context.Context, campaign *domain.Campaign) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.campaigns[campaign.UID]; ok {
+		mr.logger.Error("failed to create campaign", "uid", campaign.UID, "error", errDuplicate)
+		return domain.NewError(campaignErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.campaigns[campaign.UID] = *campaign
+	return nil
+}
+
+// CampaignCancel cancels the active campaign, its rounds left can't be played anymore
+func (mr *Repo) CampaignCancel(_ context.Context, uid string) (*domain.Campaign, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	campaign, ok := mr.campaigns[uid]
+	if !ok {
+		mr.logger.Error("failed to cancel campaign", "uid", uid, "error", errNotFound)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	if campaign.Status != domain.CampaignStatusActive {
+		mr.logger.Error("failed to cancel campaign", "uid", uid, "error", errCampaignUnavailable)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrCampaignUnavailable).Add(errCampaignUnavailable)
+	}
+
+	campaign.Status = domain.CampaignStatusCancelled
+	mr.campaigns[uid] = campaign
+	return &campaign, nil
+}
+
+// campaignCheck reports whether the campaign of the free round debit can pay it, must be called under write lock
+func (mr *Repo) campaignCheck(txn *domain.Transaction) error {
+	if txn.CampaignUID == "" || txn.Type != domain.TransactionTypeDebit {
+		return nil
+	}
+
+	campaign, ok := mr.campaigns[txn.CampaignUID]
+	if !ok || campaign.UserUID != txn.UserUID || !campaign.IsAvailable(time.Now()) {
+		return errCampaignUnavailable
+	}
+	return nil
+}
+
+// campaignApply takes the round of the free round debit from its campaign, must be called under write lock after campaignCheck
+func (mr *Repo) campaignApply(txn *domain.Transaction) {
+	if txn.CampaignUID != "" && txn.Type == domain.TransactionTypeDebit {
+		mr.campaignMove(txn.CampaignUID, -1)
+	}
+}
+
+// campaignRevert returns the round of the rolled back free round debit to its campaign, must be called under write lock
+func (mr *Repo) campaignRevert(original *domain.Transaction) {
+	if original.CampaignUID != "" && original.Type == domain.TransactionTypeDebit {
+		mr.campaignMove(original.CampaignUID, 1)
+	}
+}
+
+// campaignMove changes rounds left of the campaign, the campaign is completed without rounds left
+// and is active again when the round comes back, must be called under write lock
+func (mr *Repo) campaignMove(uid string, delta int) {
+	campaign, ok := mr.campaigns[uid]
+	if !ok {
+		return
+	}
+
+	campaign.RoundsLeft += delta
+	switch {
+	case campaign.Status == domain.CampaignStatusActive && campaign.RoundsLeft == 0:
+		campaign.Status = domain.CampaignStatusCompleted
+	case campaign.Status == domain.CampaignStatusCompleted && campaign.RoundsLeft > 0:
+		campaign.Status = domain.CampaignStatusActive
+	}
+	mr.campaigns[uid] = campaign
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"sort"
+)
+
+const (
+	// errors prefix
+	currencyErrorSource = "[repository.memory.currency]"
+)
+
+func (mr *Repo) CurrencyGetByCode(_ context.Context, code string) (*domain.Currency, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	cur, ok := mr.currencies[code]
+	if !ok {
+		mr.logger.Error("failed to find currency", "code", code)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &cur, nil
+}
+
+func (mr *Repo) CurrencyCreate(_ context.Context, cur *domain.Currency) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.currencies[cur.Code]; ok {
+		mr.logger.Error("failed to create currency", "code", cur.Code, "error", errDuplicate)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.currencies[cur.Code] = *cur
+	return nil
+}
+
+func (mr *Repo) CurrencyUpdate(_ context.Context, cur *domain.Currency) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.currencies[cur.Code]; !ok {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	mr.currencies[cur.Code] = *cur
+	return nil
+}
+
+func (mr *Repo) CurrencyDelete(_ context.Context, code string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.currencies[code]; !ok {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	delete(mr.currencies, code)
+	return nil
+}
+
+func (mr *Repo) CurrencyList(_ context.Context) ([]domain.Currency, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	currencies := make([]domain.Currency, 0, len(mr.currencies))
+	for _, cur := range mr.currencies {
+		currencies = append(currencies, cur)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies, nil
+}
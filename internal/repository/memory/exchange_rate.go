@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"sort"
+)
+
+const (
+	// errors prefix
+	exchangeRateErrorSource = "[repository.memory.exchange_rate]"
+)
+
+type exchangeRateKey struct {
+	from string
+	to   string
+}
+
+func (mr *Repo) ExchangeRateGet(_ context.Context, from, to string) (*domain.ExchangeRate, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	rate, ok := mr.exchangeRates[exchangeRateKey{from: from, to: to}]
+	if !ok {
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &rate, nil
+}
+
+func (mr *Repo) ExchangeRateList(_ context.Context) ([]domain.ExchangeRate, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	rates := make([]domain.ExchangeRate, 0, len(mr.exchangeRates))
+	for _, rate := range mr.exchangeRates {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].From != rates[j].From {
+			return rates[i].From < rates[j].From
+		}
+		return rates[i].To < rates[j].To
+	})
+	return rates, nil
+}
+
+// ExchangeRateSave creates the rate of the currency pair or replaces the existing one
+func (mr *Repo) ExchangeRateSave(_ context.Context, rate *domain.ExchangeRate) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.exchangeRates[exchangeRateKey{from: rate.From, to: rate.To}] = *rate
+	return nil
+}
+
+func (mr *Repo) ExchangeRateDelete(_ context.Context, from, to string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	key := exchangeRateKey{from: from, to: to}
+	if _, ok := mr.exchangeRates[key]; !ok {
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound)
+	}
+	delete(mr.exchangeRates, key)
+	return nil
+}
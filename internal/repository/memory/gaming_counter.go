@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+var (
+	// errGamingLimitExceeded signals that the debit takes the user over the responsible gaming limit
+	errGamingLimitExceeded = errors.New("responsible gaming limit exceeded")
+	// errDepositLimitExceeded signals that the deposit takes the user over the deposit limit
+	errDepositLimitExceeded = errors.New("deposit limit exceeded")
+)
+
+type gamingCounterKey struct {
+	userUID  string
+	currency string
+	period   domain.LimitPeriod
+	start    time.Time
+}
+
+// GamingCounterList returns the counters of the user in the currency for the periods the moment belongs to,
+// the period without money movement has zero counter
+func (mr *Repo) GamingCounterList(_ context.Context, userUID, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return mr.gamingCounterListLocked(userUID, currency, now), nil
+}
+
+// gamingCounterListLocked is GamingCounterList under the lock held by the caller
+func (mr *Repo) gamingCounterListLocked(userUID, currency string, now time.Time) []domain.GamingCounter {
+	counters := make([]domain.GamingCounter, 0, len(domain.LimitPeriods))
+	for _, period := range domain.LimitPeriods {
+		key := gamingCounterKey{userUID: userUID, currency: currency, period: period, start: period.Start(now)}
+		counter, ok := mr.gamingCounters[key]
+		if !ok {
+			counter = domain.GamingCounter{UserUID: userUID, Currency: currency, Period: period, Start: key.start}
+		}
+		counters = append(counters, counter)
+	}
+	return counters
+}
+
+// gamingLimitCheck checks the debit or the deposit against the gaming limits of the draft, the counters are read under
+// the same write lock the money is booked under, so concurrent debits or deposits can't take the user over the limit together
+func (mr *Repo) gamingLimitCheck(txn *domain.Transaction) error {
+	if txn.GamingLimits == nil || (txn.Type != domain.TransactionTypeDebit && !txn.IsDeposit()) {
+		return nil
+	}
+	counters := mr.gamingCounterListLocked(txn.UserUID, txn.Currency, time.Now())
+	if txn.IsDeposit() {
+		if txn.GamingLimits.DepositExceeded(counters, txn.Amount) != nil {
+			return errDepositLimitExceeded
+		}
+		return nil
+	}
+	if txn.GamingLimits.Exceeded(counters, txn.Amount) != nil {
+		return errGamingLimitExceeded
+	}
+	return nil
+}
+
+// gamingCounterApply counts the transaction to the periods of its creation, rollback is counted to the periods
+// of the original transaction, must be called under write lock
+func (mr *Repo) gamingCounterApply(txn, original *domain.Transaction) {
+	wager, loss := domain.GamingDelta(txn, original)
+	deposit := domain.DepositDelta(txn)
+	if wager == 0 && loss == 0 && deposit == 0 {
+		return
+	}
+
+	at := txn.CreatedAt
+	if original != nil {
+		at = original.CreatedAt
+	}
+	for _, period := range domain.LimitPeriods {
+		key := gamingCounterKey{userUID: txn.UserUID, currency: txn.Currency, period: period, start: period.Start(at)}
+		counter, ok := mr.gamingCounters[key]
+		if !ok {
+			counter = domain.GamingCounter{UserUID: txn.UserUID, Currency: txn.Currency, Period: period, Start: key.start}
+		}
+		counter.Wager += wager
+		counter.Loss += loss
+		counter.Deposit += deposit
+		mr.gamingCounters[key] = counter
+	}
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"sort"
+)
+
+const (
+	// errors prefix
+	jackpotErrorSource = "[repository.memory.jackpot]"
+)
+
+// errJackpotPayout signals that jackpot pool can't cover the payout
+var errJackpotPayout = errors.New("jackpot pool not found or insufficient")
+
+func (mr *Repo) JackpotGetByKey(_ context.Context, key, currency string) (*domain.Jackpot, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	jackpot, ok := mr.jackpots[jackpotKey{key: key, currency: currency}]
+	if !ok {
+		mr.logger.Error("failed to find jackpot", "key", key, "currency", currency)
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &jackpot, nil
+}
+
+// JackpotListActiveByGame returns active pools of the game in the currency including shared ones
+func (mr *Repo) JackpotListActiveByGame(_ context.Context, gameUID, currency string) ([]domain.Jackpot, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	jackpots := make([]domain.Jackpot, 0)
+	for _, jackpot := range mr.jackpots {
+		if jackpot.Currency != currency || !jackpot.IsActive || (jackpot.GameUID != gameUID && jackpot.GameUID != "") {
+			continue
+		}
+		jackpots = append(jackpots, jackpot)
+	}
+	// game pools go before shared ones
+	sort.Slice(jackpots, func(i, j int) bool {
+		if jackpots[i].GameUID != jackpots[j].GameUID {
+			return jackpots[i].GameUID > jackpots[j].GameUID
+		}
+		return jackpots[i].Key < jackpots[j].Key
+	})
+	return jackpots, nil
+}
+
+func (mr *Repo) JackpotCreate(_ context.Context, jackpot *domain.Jackpot) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	key := jackpotKey{key: jackpot.Key, currency: jackpot.Currency}
+	if _, ok := mr.jackpots[key]; ok {
+		mr.logger.Error("failed to create jackpot", "key", jackpot.Key, "currency", jackpot.Currency, "error", errDuplicate)
+		return domain.NewError(jackpotErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.jackpots[key] = *jackpot
+	return nil
+}
+
+// jackpotDelta returns how the transaction changes the pool and whether the pool can take it,
+// must be called under write lock
+func (mr *Repo) jackpotDelta(txn *domain.Transaction) (int, error) {
+	if txn.JackpotKey == "" {
+		return 0, nil
+	}
+
+	jackpot, ok := mr.jackpots[jackpotKey{key: txn.JackpotKey, currency: txn.Currency}]
+	switch txn.Type {
+	case domain.TransactionTypeDebit:
+		if !ok {
+			return 0, errJackpotPayout
+		}
+		return txn.JackpotContribution, nil
+	case domain.TransactionTypeCredit:
+		if !ok || jackpot.Amount < txn.Amount {
+			return 0, errJackpotPayout
+		}
+		return -txn.Amount, nil
+	}
+	return 0, nil
+}
+
+// jackpotMove changes the pool amount, must be called under write lock
+func (mr *Repo) jackpotMove(key, currency string, delta int) {
+	if key == "" || delta == 0 {
+		return
+	}
+	k := jackpotKey{key: key, currency: currency}
+	if jackpot, ok := mr.jackpots[k]; ok {
+		jackpot.Amount += delta
+		mr.jackpots[k] = jackpot
+	}
+}
+
+// jackpotRevert returns jackpot contribution or payout of the rolled back transaction, must be called under write lock
+func (mr *Repo) jackpotRevert(original *domain.Transaction) {
+	switch original.Type {
+	case domain.TransactionTypeDebit:
+		mr.jackpotMove(original.JackpotKey, original.Currency, -original.JackpotContribution)
+	case domain.TransactionTypeCredit:
+		mr.jackpotMove(original.JackpotKey, original.Currency, original.Amount)
+	}
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"open-api-games/internal/domain"
+	"sync"
+)
+
+var (
+	// errNotFound signals that the record is missing
+	errNotFound = errors.New("record not found")
+	// errDuplicate signals that the record with the same key already exists
+	errDuplicate = errors.New("record already exists")
+)
+
+type balanceKey struct {
+	userUID  string
+	currency string
+}
+
+type jackpotKey struct {
+	key      string
+	currency string
+}
+
+type providerTransactionKey struct {
+	providerUID    string
+	transactionUID string
+	txnType        domain.TransactionType
+}
+
+// roundKey is the bet id of the provider, bet ids of different providers don't clash
+type roundKey struct {
+	providerUID string
+	uid         string
+}
+
+// Repo keeps all the records in process memory, one lock guards all collections,
+// so every method is atomic the same way as db transaction of the persistent repositories
+type Repo struct {
+	mu     sync.RWMutex
+	logger *slog.Logger
+
+	users        map[string]domain.User
+	currencies   map[string]domain.Currency
+	balances     map[balanceKey]domain.Balance
+	sessions     map[string]domain.Session
+	transactions map[string]domain.Transaction
+	// providerTransactions indexes transactions by provider transaction id and type
+	providerTransactions map[providerTransactionKey]string
+	rounds               map[roundKey]domain.Round
+	jackpots             map[jackpotKey]domain.Jackpot
+	providers            map[string]domain.Provider
+	exchangeRates        map[exchangeRateKey]domain.ExchangeRate
+	campaigns            map[string]domain.Campaign
+	gamingCounters       map[gamingCounterKey]domain.GamingCounter
+	// outbox holds events of wallet movements by uid, outboxSeq numbers them in the order of the movements
+	outbox    map[string]outboxEntry
+	outboxSeq int
+}
+
+func New(logger *slog.Logger) *Repo {
+	return &Repo{
+		logger:               logger,
+		users:                make(map[string]domain.User),
+		currencies:           make(map[string]domain.Currency),
+		balances:             make(map[balanceKey]domain.Balance),
+		sessions:             make(map[string]domain.Session),
+		transactions:         make(map[string]domain.Transaction),
+		providerTransactions: make(map[providerTransactionKey]string),
+		rounds:               make(map[roundKey]domain.Round),
+		jackpots:             make(map[jackpotKey]domain.Jackpot),
+		providers:            make(map[string]domain.Provider),
+		exchangeRates:        make(map[exchangeRateKey]domain.ExchangeRate),
+		campaigns:            make(map[string]domain.Campaign),
+		gamingCounters:       make(map[gamingCounterKey]domain.GamingCounter),
+		outbox:               make(map[string]outboxEntry),
+	}
+}
+
+// moneyState is the copy of the collections changed by money movement
+type moneyState struct {
+	balances             map[balanceKey]domain.Balance
+	transactions         map[string]domain.Transaction
+	providerTransactions map[providerTransactionKey]string
+	rounds               map[roundKey]domain.Round
+	jackpots             map[jackpotKey]domain.Jackpot
+	campaigns            map[string]domain.Campaign
+	gamingCounters       map[gamingCounterKey]domain.GamingCounter
+	sessions             map[string]domain.Session
+	outbox               map[string]outboxEntry
+}
+
+// snapshot copies the money collections, so the changes of failed batch can be undone, must be called under write lock
+func (mr *Repo) snapshot() moneyState {
+	return moneyState{
+		balances:             maps.Clone(mr.balances),
+		transactions:         maps.Clone(mr.transactions),
+		providerTransactions: maps.Clone(mr.providerTransactions),
+		rounds:               maps.Clone(mr.rounds),
+		jackpots:             maps.Clone(mr.jackpots),
+		campaigns:            maps.Clone(mr.campaigns),
+		gamingCounters:       maps.Clone(mr.gamingCounters),
+		sessions:             maps.Clone(mr.sessions),
+		outbox:               maps.Clone(mr.outbox),
+	}
+}
+
+// restore brings back the money collections of the snapshot, must be called under write lock
+func (mr *Repo) restore(state moneyState) {
+	mr.balances = state.balances
+	mr.transactions = state.transactions
+	mr.providerTransactions = state.providerTransactions
+	mr.rounds = state.rounds
+	mr.jackpots = state.jackpots
+	mr.campaigns = state.campaigns
+	mr.gamingCounters = state.gamingCounters
+	mr.sessions = state.sessions
+	mr.outbox = state.outbox
+}
+
+// EnsureIndexes does nothing, maps are indexed by keys
+func (mr *Repo) EnsureIndexes(_ context.Context) error {
+	return nil
+}
+
+func (mr *Repo) Close(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"log/slog"
+	"open-api-games/internal/repository/memory"
+	"open-api-games/internal/repository/repotest"
+	"os"
+	"testing"
+)
+
+func TestRepoContract(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	repotest.Run(t, memory.New(logger))
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"slices"
+	"time"
+)
+
+const (
+	// errors prefix
+	outboxErrorSource = "[repository.memory.outbox]"
+)
+
+// outboxEntry keeps the order the events were stored in
+type outboxEntry struct {
+	seq   int
+	event domain.OutboxEvent
+}
+
+// OutboxListPending returns up to limit pending events due at the moment in the order of their movements
+func (mr *Repo) OutboxListPending(_ context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	entries := make([]outboxEntry, 0)
+	for _, entry := range mr.outbox {
+		if entry.event.Status == domain.OutboxStatusPending && !entry.event.NextAttemptAt.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b outboxEntry) int { return a.seq - b.seq })
+
+	events := make([]domain.OutboxEvent, 0, min(len(entries), limit))
+	for _, entry := range entries[:min(len(entries), limit)] {
+		events = append(events, entry.event)
+	}
+	return events, nil
+}
+
+// OutboxUpdate stores the delivery state of the event
+func (mr *Repo) OutboxUpdate(_ context.Context, event *domain.OutboxEvent) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entry, ok := mr.outbox[event.UID]
+	if !ok {
+		mr.logger.Error("failed to update outbox event", "uid", event.UID, "error", errNotFound)
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	entry.event.Status = event.Status
+	entry.event.Attempts = event.Attempts
+	entry.event.LastError = event.LastError
+	entry.event.NextAttemptAt = event.NextAttemptAt
+	entry.event.DeliveredAt = event.DeliveredAt
+	mr.outbox[event.UID] = entry
+	return nil
+}
+
+// outboxAdd stores the event of the wallet movement, must be called under write lock
+func (mr *Repo) outboxAdd(txn *domain.Transaction) {
+	mr.outboxSeq++
+	event := domain.NewOutboxEvent(txn)
+	mr.outbox[event.UID] = outboxEntry{seq: mr.outboxSeq, event: *event}
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"slices"
+	"sort"
+)
+
+const (
+	// errors prefix
+	providerErrorSource = "[repository.memory.provider]"
+)
+
+func (mr *Repo) ProviderGetByUID(_ context.Context, uid string) (*domain.Provider, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	provider, ok := mr.providers[uid]
+	if !ok {
+		mr.logger.Error("failed to find provider", "uid", uid)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return providerCopy(&provider), nil
+}
+
+func (mr *Repo) ProviderCreate(_ context.Context, provider *domain.Provider) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.providers[provider.UID]; ok {
+		mr.logger.Error("failed to create provider", "uid", provider.UID, "error", errDuplicate)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.providers[provider.UID] = *providerCopy(provider)
+	return nil
+}
+
+func (mr *Repo) ProviderUpdate(_ context.Context, provider *domain.Provider) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.providers[provider.UID]; !ok {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	mr.providers[provider.UID] = *providerCopy(provider)
+	return nil
+}
+
+func (mr *Repo) ProviderDelete(_ context.Context, uid string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.providers[uid]; !ok {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	delete(mr.providers, uid)
+	return nil
+}
+
+func (mr *Repo) ProviderList(_ context.Context) ([]domain.Provider, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	providers := make([]domain.Provider, 0, len(mr.providers))
+	for _, provider := range mr.providers {
+		providers = append(providers, *providerCopy(&provider))
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].UID < providers[j].UID
+	})
+	return providers, nil
+}
+
+// providerCopy detaches slices of the stored provider from the caller
+func providerCopy(provider *domain.Provider) *domain.Provider {
+	res := *provider
+	res.Secrets = slices.Clone(provider.Secrets)
+	res.AllowedIPs = slices.Clone(provider.AllowedIPs)
+	res.Currencies = slices.Clone(provider.Currencies)
+	return &res
+}
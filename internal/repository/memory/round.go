@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"sort"
+)
+
+const (
+	// errors prefix
+	roundErrorSource = "[repository.memory.round]"
+)
+
+var (
+	// errRoundClosed signals that money movement came to already completed round
+	errRoundClosed = errors.New("round already closed")
+	// errRoundUser signals that bet id is already used by the round of another user
+	errRoundUser = errors.New("round belongs to another user")
+	// errRoundChanged signals that the round got new transactions since it was read
+	errRoundChanged = errors.New("round changed or already closed")
+)
+
+func (mr *Repo) RoundGetByProviderUID(_ context.Context, providerUID, uid string) (*domain.Round, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	round, ok := mr.rounds[roundKey{providerUID: providerUID, uid: uid}]
+	if !ok {
+		mr.logger.Error("failed to find round", "providerUid", providerUID, "uid", uid)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return mr.roundWithTransactions(&round), nil
+}
+
+// RoundClose closes the round if it wasn't changed since it was read
+func (mr *Repo) RoundClose(_ context.Context, round *domain.Round) (*domain.Round, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	key := roundKey{providerUID: round.ProviderUID, uid: round.UID}
+	existing, ok := mr.rounds[key]
+	if !ok || existing.Status != domain.RoundStatusOpen || len(mr.roundTransactions(key)) != len(round.Transactions) {
+		mr.logger.Error("failed to close round", "uid", round.UID, "error", errRoundChanged)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrRoundClose).Add(errRoundChanged)
+	}
+
+	existing.Status = domain.RoundStatusClosed
+	mr.rounds[key] = existing
+	return mr.roundWithTransactions(&existing), nil
+}
+
+// roundCheck reports whether the transaction can be added to its round, must be called under write lock
+func (mr *Repo) roundCheck(txn *domain.Transaction) error {
+	if txn.RoundUID == "" {
+		return nil
+	}
+
+	existing, ok := mr.rounds[roundKeyOf(txn)]
+	switch {
+	case !ok:
+	case existing.UserUID != txn.UserUID:
+		return errRoundUser
+	case existing.Status == domain.RoundStatusClosed && txn.Type != domain.TransactionTypeRollback:
+		// rollbacks are still accepted to refund the player
+		return errRoundClosed
+	}
+	return nil
+}
+
+// roundFind returns the round of the transaction, nil if it isn't opened yet, must be called under lock
+func (mr *Repo) roundFind(txn *domain.Transaction) *domain.Round {
+	round, ok := mr.rounds[roundKeyOf(txn)]
+	if txn.RoundUID == "" || !ok {
+		return nil
+	}
+	return &round
+}
+
+// roundApply adds transaction to its round and updates round totals, round is opened by the first transaction,
+// must be called under write lock after roundCheck
+func (mr *Repo) roundApply(txn *domain.Transaction, betDelta, bonusBetDelta, winDelta int) {
+	if txn.RoundUID == "" {
+		return
+	}
+
+	round, ok := mr.rounds[roundKeyOf(txn)]
+	if !ok {
+		round = domain.Round{
+			ProviderUID: txn.ProviderUID,
+			UID:         txn.RoundUID,
+			SessionUID:  txn.SessionUID,
+			UserUID:     txn.UserUID,
+			Currency:    txn.Currency,
+			Status:      domain.RoundStatusOpen,
+		}
+	}
+
+	round.TotalBet += betDelta
+	round.TotalBonusBet += bonusBetDelta
+	round.TotalWin += winDelta
+	mr.rounds[roundKeyOf(txn)] = round
+}
+
+func roundKeyOf(txn *domain.Transaction) roundKey {
+	return roundKey{providerUID: txn.ProviderUID, uid: txn.RoundUID}
+}
+
+// roundWithTransactions copies the round with its stored transactions, must be called under lock
+func (mr *Repo) roundWithTransactions(r *domain.Round) *domain.Round {
+	round := *r
+	round.Transactions = mr.roundTransactions(roundKey{providerUID: r.ProviderUID, uid: r.UID})
+	return &round
+}
+
+// roundTransactions lists stored transactions of the round in the order they were made, must be called under lock
+func (mr *Repo) roundTransactions(key roundKey) []domain.RoundTransaction {
+	stored := make([]domain.Transaction, 0)
+	for _, txn := range mr.transactions {
+		if roundKeyOf(&txn) == key {
+			stored = append(stored, txn)
+		}
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		if !stored[i].CreatedAt.Equal(stored[j].CreatedAt) {
+			return stored[i].CreatedAt.Before(stored[j].CreatedAt)
+		}
+		return stored[i].UID < stored[j].UID
+	})
+
+	transactions := make([]domain.RoundTransaction, 0, len(stored))
+	for _, txn := range stored {
+		transactions = append(transactions, domain.RoundTransaction{
+			UID:                  txn.UID,
+			Type:                 txn.Type,
+			Amount:               txn.Amount,
+			ParentTransactionUID: txn.ParentTransactionUID,
+		})
+	}
+	return transactions
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	sessionErrorSource = "[repository.memory.session]"
+)
+
+func (mr *Repo) SessionGetByUID(_ context.Context, uid string) (*domain.Session, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	session, ok := mr.sessions[uid]
+	if !ok {
+		mr.logger.Error("failed to find session", "uid", uid)
+		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return &session, nil
+}
+
+func (mr *Repo) SessionCreate(_ context.Context, sess *domain.Session) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.sessions[sess.UID]; ok {
+		mr.logger.Error("failed to create session", "uid", sess.UID, "error", errDuplicate)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.sessions[sess.UID] = *sess
+	return nil
+}
+
+// SessionClose closes the session unless it's already closed
+func (mr *Repo) SessionClose(_ context.Context, uid string, now time.Time) (*domain.Session, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	session, ok := mr.sessions[uid]
+	if !ok || session.Status == domain.SessionStatusClosed {
+		err := errNotFound
+		if ok {
+			err = errors.New("session already closed")
+		}
+		mr.logger.Error("failed to close session", "uid", uid, "error", err)
+		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrSessionClose).Add(err)
+	}
+
+	session.Status = domain.SessionStatusClosed
+	session.LastActivityAt = now
+	mr.sessions[uid] = session
+	return &session, nil
+}
+
+// SessionTouch registers activity in the session and moves the expiry of the active session to expiresAt,
+// the session without expiry and the one already expired keep it
+func (mr *Repo) SessionTouch(_ context.Context, uid string, now, expiresAt time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if session, ok := mr.sessions[uid]; ok {
+		if !expiresAt.IsZero() && !session.ExpiresAt.IsZero() && session.IsActive(now) {
+			session.ExpiresAt = expiresAt
+		}
+		session.LastActivityAt = now
+		mr.sessions[uid] = session
+	}
+	return nil
+}
+
+// SessionRealityChecked registers the moment the user confirmed the reality check of the session
+func (mr *Repo) SessionRealityChecked(_ context.Context, uid string, now time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	session, ok := mr.sessions[uid]
+	if !ok {
+		mr.logger.Error("failed to check reality of session", "uid", uid, "error", errNotFound)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	session.RealityCheckedAt = now
+	mr.sessions[uid] = session
+	return nil
+}
+
+// SessionExpire marks open sessions which expiry moment has passed as expired, returns number of expired sessions
+func (mr *Repo) SessionExpire(_ context.Context, now time.Time) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	expired := 0
+	for uid, session := range mr.sessions {
+		if session.Status != domain.SessionStatusOpen || session.ExpiresAt.IsZero() || session.ExpiresAt.After(now) {
+			continue
+		}
+		session.Status = domain.SessionStatusExpired
+		mr.sessions[uid] = session
+		expired++
+	}
+	return expired, nil
+}
+
+// sessionApply adds the transaction to the bets and wins of its session, rollback takes back the original transaction
+// from the session of the original, must be called under write lock
+func (mr *Repo) sessionApply(txn, original *domain.Transaction) {
+	bet, win := domain.SessionDelta(txn, original)
+	uid := txn.SessionUID
+	if original != nil {
+		uid = original.SessionUID
+	}
+	session, ok := mr.sessions[uid]
+	if !ok || (bet == 0 && win == 0) {
+		return
+	}
+	session.TotalBet += bet
+	session.TotalWin += win
+	mr.sessions[uid] = session
+}
@@ -0,0 +1,321 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	// errors prefix
+	transactionErrorSource = "[repository.memory.transaction]"
+)
+
+func (mr *Repo) TransactionGetByUID(_ context.Context, uid string) (*domain.Transaction, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	txn, ok := mr.transactions[uid]
+	if !ok {
+		mr.logger.Error("failed to find transaction", "uid", uid)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return transactionCopy(&txn), nil
+}
+
+func (mr *Repo) TransactionCreate(_ context.Context, transaction *domain.Transaction) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	_, exists := mr.transactions[transaction.UID]
+	if !exists && transaction.ProviderTransactionUID != "" {
+		_, exists = mr.transactionFindByProviderUID(transaction.ProviderUID, transaction.ProviderTransactionUID, transaction.Type)
+	}
+	if exists {
+		mr.logger.Error("failed to create transaction", "uid", transaction.UID, "error", errDuplicate)
+		return domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+
+	mr.transactionStore(transactionCopy(transaction))
+	return nil
+}
+
+// TransactionRollback compensates the transaction and marks it as rolled back atomically,
+// repeated rollback returns the rollback transaction created by the first one
+func (mr *Repo) TransactionRollback(_ context.Context, uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	rollback, err := mr.transactionRollback(uid, meta)
+	if err != nil {
+		mr.logger.Error("failed to rollback transaction", "uid", uid, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+	return rollback, nil
+}
+
+func (mr *Repo) transactionRollback(uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	original, ok := mr.transactions[uid]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	if original.Status == domain.TransactionStatusRolledBack {
+		rollback, ok := mr.transactions[original.RollbackTransactionUID]
+		if !ok {
+			return nil, errNotFound
+		}
+		return transactionCopy(&rollback), nil
+	}
+
+	key := balanceKey{userUID: original.UserUID, currency: original.Currency}
+	balance, ok := mr.balances[key]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	// rollback moves money in the opposite direction of the original transaction
+	var delta, betDelta, bonusBetDelta, winDelta int
+	switch original.Type {
+	case domain.TransactionTypeDebit:
+		delta, betDelta, bonusBetDelta = original.Amount, -original.Amount, -original.BonusAmount
+	case domain.TransactionTypeCredit:
+		if balance.Amount < original.Amount {
+			return nil, errInsufficientFunds
+		}
+		delta, winDelta = -original.Amount, -original.Amount
+	default:
+		return nil, errors.New("transaction type can't be rolled back")
+	}
+
+	// rollback is keyed by the original transaction, provider transaction id could be shared by its debit and credit
+	rollback := &domain.Transaction{
+		UID:                  domain.GenUID(),
+		ProviderUID:          original.ProviderUID,
+		UserUID:              original.UserUID,
+		SessionUID:           original.SessionUID,
+		RoundUID:             original.RoundUID,
+		Amount:               original.Amount,
+		Currency:             original.Currency,
+		Denomination:         original.Denomination,
+		Type:                 domain.TransactionTypeRollback,
+		Status:               domain.TransactionStatusCommitted,
+		ParentTransactionUID: original.UID,
+		CreatedAt:            time.Now(),
+		Meta:                 meta,
+	}
+	if err := mr.roundCheck(rollback); err != nil {
+		return nil, err
+	}
+
+	balance.Book(rollback, delta, &original, nil)
+	mr.balances[key] = balance
+	mr.transactionStore(rollback)
+	mr.roundApply(rollback, betDelta, bonusBetDelta, winDelta)
+	mr.jackpotRevert(&original)
+	mr.campaignRevert(&original)
+	mr.gamingCounterApply(rollback, &original)
+	mr.sessionApply(rollback, &original)
+	mr.outboxAdd(rollback)
+
+	original.Status = domain.TransactionStatusRolledBack
+	original.RollbackTransactionUID = rollback.UID
+	mr.transactions[original.UID] = original
+
+	return transactionCopy(rollback), nil
+}
+
+// TransactionGetByProviderUID finds the transaction of the type by the game provider transaction id within the provider
+func (mr *Repo) TransactionGetByProviderUID(_ context.Context, providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	txn, ok := mr.transactionFindByProviderUID(providerUID, transactionUID, txnType)
+	if !ok {
+		mr.logger.Error("failed to find transaction", "provider", providerUID, "providerTransactionUid", transactionUID, "type", txnType)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return txn, nil
+}
+
+// TransactionCreateTombstone records rollback of the provider transaction we have never seen,
+// so the late original transaction can't move money, repeated call returns the existing tombstone
+func (mr *Repo) TransactionCreateTombstone(_ context.Context, tombstone *domain.Transaction) (*domain.Transaction, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	txn, err := mr.transactionTombstone(tombstone)
+	if err != nil {
+		mr.logger.Error("failed to create tombstone", "providerTransactionUid", tombstone.ProviderTransactionUID, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+	return txn, nil
+}
+
+func (mr *Repo) transactionTombstone(tombstone *domain.Transaction) (*domain.Transaction, error) {
+	if existing, ok := mr.transactionFindByProviderUID(tombstone.ProviderUID, tombstone.ProviderTransactionUID, domain.TransactionTypeRollback); ok {
+		return existing, nil
+	}
+
+	balance, ok := mr.balances[balanceKey{userUID: tombstone.UserUID, currency: tombstone.Currency}]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	txn := &domain.Transaction{
+		UID:                    domain.GenUID(),
+		ProviderUID:            tombstone.ProviderUID,
+		ProviderTransactionUID: tombstone.ProviderTransactionUID,
+		UserUID:                tombstone.UserUID,
+		SessionUID:             tombstone.SessionUID,
+		RoundUID:               tombstone.RoundUID,
+		Currency:               tombstone.Currency,
+		OriginalCurrency:       tombstone.OriginalCurrency,
+		ExchangeRate:           tombstone.ExchangeRate,
+		Denomination:           balance.Denomination,
+		Type:                   domain.TransactionTypeRollback,
+		Status:                 domain.TransactionStatusTombstone,
+		BalanceBefore:          balance.Amount,
+		Balance:                balance.Amount,
+		BonusBalance:           balance.Bonus,
+		CreatedAt:              time.Now(),
+		Meta:                   tombstone.Meta,
+	}
+	if err := mr.roundCheck(txn); err != nil {
+		return nil, err
+	}
+
+	mr.transactionStore(txn)
+	mr.roundApply(txn, 0, 0, 0)
+
+	return transactionCopy(txn), nil
+}
+
+// TransactionBatch stores debits, credits, rollbacks and tombstones of the drafts atomically in their order, all or nothing,
+// replayed provider transaction returns the stored one if it matches the draft, the failed draft is reported by its index
+func (mr *Repo) TransactionBatch(_ context.Context, drafts []*domain.Transaction) ([]*domain.Transaction, int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	state := mr.snapshot()
+	results := make([]*domain.Transaction, 0, len(drafts))
+	for i, draft := range drafts {
+		txn, err := mr.transactionBatchApply(draft)
+		if err != nil {
+			mr.restore(state)
+			mr.logger.Error("failed to store batch", "index", i, "providerTransactionUid", draft.ProviderTransactionUID, "error", err)
+			return nil, i, transactionBatchError(draft, err)
+		}
+		results = append(results, txn)
+	}
+	return results, -1, nil
+}
+
+// transactionBatchApply stores the draft of the batch by its type, must be called under write lock
+func (mr *Repo) transactionBatchApply(draft *domain.Transaction) (*domain.Transaction, error) {
+	switch draft.Type {
+	case domain.TransactionTypeDebit, domain.TransactionTypeCredit:
+		delta, required := draft.Amount, 0
+		if draft.Type == domain.TransactionTypeDebit {
+			delta, required = -draft.Amount, draft.Amount
+		}
+		txn, err := mr.balanceMoveLocked(transactionCopy(draft), delta, required)
+		if err != nil {
+			return nil, err
+		}
+		if !transactionReplayMatch(txn, draft) {
+			return nil, errTransactionConflict
+		}
+		return txn, nil
+	case domain.TransactionTypeRollback:
+		if draft.Status == domain.TransactionStatusTombstone {
+			return mr.transactionTombstone(draft)
+		}
+		return mr.transactionRollback(draft.ParentTransactionUID, draft.Meta)
+	}
+	return nil, errors.New("transaction type can't be batched")
+}
+
+// transactionBatchError wraps error of the batch draft with the code of the separate movement of its type
+func transactionBatchError(draft *domain.Transaction, err error) *domain.Error {
+	switch draft.Type {
+	case domain.TransactionTypeDebit:
+		return balanceMoveError(err, domain.ErrDecrement)
+	case domain.TransactionTypeCredit:
+		return balanceMoveError(err, domain.ErrIncrement)
+	}
+	return domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+}
+
+// transactionReplayMatch reports whether the stored transaction is the same movement as the draft
+func transactionReplayMatch(stored, draft *domain.Transaction) bool {
+	return stored.UserUID == draft.UserUID &&
+		stored.RequestedAmount() == draft.RequestedAmount() &&
+		stored.RequestedCurrency() == draft.RequestedCurrency()
+}
+
+// TransactionList searches transactions by the filter, newest first
+func (mr *Repo) TransactionList(_ context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	transactions := make([]domain.Transaction, 0)
+	for _, txn := range mr.transactions {
+		if transactionMatch(&txn, &filter) {
+			transactions = append(transactions, txn)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) {
+			return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+		}
+		return transactions[i].UID > transactions[j].UID
+	})
+
+	return &domain.TransactionPage{Transactions: paginate(transactions, filter.Pagination), Total: len(transactions)}, nil
+}
+
+func transactionMatch(txn *domain.Transaction, filter *domain.TransactionFilter) bool {
+	switch {
+	case filter.UserUID != "" && txn.UserUID != filter.UserUID,
+		filter.SessionUID != "" && txn.SessionUID != filter.SessionUID,
+		filter.RoundUID != "" && txn.RoundUID != filter.RoundUID,
+		filter.ProviderTransactionUID != "" && txn.ProviderTransactionUID != filter.ProviderTransactionUID,
+		filter.Currency != "" && txn.Currency != filter.Currency,
+		filter.Type != "" && txn.Type != filter.Type,
+		filter.Status != "" && txn.Status != filter.Status,
+		!filter.From.IsZero() && txn.CreatedAt.Before(filter.From),
+		!filter.To.IsZero() && !txn.CreatedAt.Before(filter.To):
+		return false
+	}
+	return true
+}
+
+// transactionStore saves the transaction and indexes it by provider transaction id, must be called under write lock
+func (mr *Repo) transactionStore(txn *domain.Transaction) {
+	mr.transactions[txn.UID] = *txn
+	if txn.ProviderTransactionUID != "" {
+		key := providerTransactionKey{providerUID: txn.ProviderUID, transactionUID: txn.ProviderTransactionUID, txnType: txn.Type}
+		mr.providerTransactions[key] = txn.UID
+	}
+}
+
+// transactionFindByProviderUID looks up already processed transaction of the provider of the given type, must be called under lock
+func (mr *Repo) transactionFindByProviderUID(providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, bool) {
+	uid, ok := mr.providerTransactions[providerTransactionKey{providerUID: providerUID, transactionUID: transactionUID, txnType: txnType}]
+	if !ok {
+		return nil, false
+	}
+	txn, ok := mr.transactions[uid]
+	if !ok {
+		return nil, false
+	}
+	return transactionCopy(&txn), true
+}
+
+func transactionCopy(t *domain.Transaction) *domain.Transaction {
+	txn := *t
+	return &txn
+}
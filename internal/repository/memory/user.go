@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"open-api-games/internal/domain"
+	"sort"
+)
+
+const (
+	// errors prefix
+	userErrorSource = "[repository.memory.user]"
+)
+
+func (mr *Repo) UserGetByUID(_ context.Context, uid string) (*domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	user, ok := mr.users[uid]
+	if !ok {
+		mr.logger.Error("failed to find user", "uid", uid)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(errNotFound)
+	}
+	return userCopy(&user), nil
+}
+
+func (mr *Repo) UserCreate(_ context.Context, user *domain.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.users[user.UID]; ok {
+		mr.logger.Error("failed to create user", "uid", user.UID, "error", errDuplicate)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoCreate).Add(errDuplicate)
+	}
+	mr.users[user.UID] = *userCopy(user)
+	return nil
+}
+
+func (mr *Repo) UserUpdate(_ context.Context, user *domain.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.users[user.UID]; !ok {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	mr.users[user.UID] = *userCopy(user)
+	return nil
+}
+
+func (mr *Repo) UserDelete(_ context.Context, uid string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.users[uid]; !ok {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	delete(mr.users, uid)
+	return nil
+}
+
+// UserList returns the page of users ordered by uid
+func (mr *Repo) UserList(_ context.Context, page domain.Pagination) (*domain.UserPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(mr.users))
+	for _, user := range mr.users {
+		users = append(users, *userCopy(&user))
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UID < users[j].UID
+	})
+
+	return &domain.UserPage{Users: paginate(users, page), Total: len(users)}, nil
+}
+
+func userCopy(u *domain.User) *domain.User {
+	return &domain.User{
+		UID:                  u.UID,
+		Nick:                 u.Nick,
+		MaxWin:               maps.Clone(u.MaxWin),
+		Limits:               maps.Clone(u.Limits),
+		SelfExcludedUntil:    u.SelfExcludedUntil,
+		CoolOffUntil:         u.CoolOffUntil,
+		SessionLimit:         u.SessionLimit,
+		RealityCheckInterval: u.RealityCheckInterval,
+	}
+}
+
+// paginate cuts the page out of sorted records
+func paginate[T any](records []T, page domain.Pagination) []T {
+	if page.Offset >= len(records) {
+		return []T{}
+	}
+	records = records[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(records) {
+		records = records[:page.Limit]
+	}
+	return records
+}
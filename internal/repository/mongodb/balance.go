@@ -2,10 +2,12 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"open-api-games/internal/domain"
+	"time"
 )
 
 const (
@@ -16,9 +18,18 @@ const (
 	balanceErrorSource = "[repository.mongodb.balance]"
 )
 
+var (
+	// errTransactionRolledBack signals that provider transaction arrived after its rollback
+	errTransactionRolledBack = errors.New("provider transaction already rolled back")
+	// errTransactionConflict signals that replayed provider transaction doesn't match the stored one
+	errTransactionConflict = errors.New("provider transaction conflicts with the stored one")
+)
+
 type balanceDB struct {
 	UserUID      string `bson:"userUid"`
 	Amount       int    `bson:"amount"`
+	Bonus        int    `bson:"bonus"`
+	Wagering     int    `bson:"wagering"`
 	Currency     string `bson:"currency"`
 	Denomination int    `bson:"denomination"`
 }
@@ -27,12 +38,14 @@ func (mr *Repo) BalanceGetByUserUIDAndCurrency(ctx context.Context, userUID, cur
 	var result balanceDB
 	err := mr.db.Collection(balanceTable).FindOne(ctx, bson.M{"userUid": userUID, "currency": currency}).Decode(&result)
 	if err != nil {
-		mr.logger.Error("failed to find balance", userUID, currency, err)
+		mr.logger.Error("failed to find balance", "userUid", userUID, "currency", currency, "error", err)
 		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
 	}
 	return &domain.Balance{
 		UserUID:      result.UserUID,
 		Amount:       result.Amount,
+		Bonus:        result.Bonus,
+		Wagering:     result.Wagering,
 		Currency:     result.Currency,
 		Denomination: result.Denomination,
 	}, nil
@@ -42,6 +55,8 @@ func (mr *Repo) BalanceCreate(ctx context.Context, balance *domain.Balance) erro
 	balanceDb := balanceDB{
 		UserUID:      balance.UserUID,
 		Amount:       balance.Amount,
+		Bonus:        balance.Bonus,
+		Wagering:     balance.Wagering,
 		Currency:     balance.Currency,
 		Denomination: balance.Denomination,
 	}
@@ -55,129 +70,290 @@ func (mr *Repo) BalanceCreate(ctx context.Context, balance *domain.Balance) erro
 	return nil
 }
 
-func (mr *Repo) BalanceDecrementByUserUIDAndCurrency(ctx context.Context, userUID, currency string, amount int) (*domain.Transaction, error) {
+func (mr *Repo) BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error) {
+	cursor, err := mr.db.Collection(balanceTable).Find(ctx, bson.M{"userUid": userUID}, options.Find().SetSort(bson.D{{Key: "currency", Value: 1}}))
+	if err != nil {
+		mr.logger.Error("failed to list balances", "userUid", userUID, "error", err)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []balanceDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode balances", "userUid", userUID, "error", err)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	balances := make([]domain.Balance, 0, len(result))
+	for _, b := range result {
+		balances = append(balances, domain.Balance{
+			UserUID:      b.UserUID,
+			Amount:       b.Amount,
+			Bonus:        b.Bonus,
+			Wagering:     b.Wagering,
+			Currency:     b.Currency,
+			Denomination: b.Denomination,
+		})
+	}
+	return balances, nil
+}
+
+// BalanceAdjust applies manual correction of user balance, the balance can't become negative
+func (mr *Repo) BalanceAdjust(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	transactionDb := transactionToDB(txn)
+	transactionDb.Type = domain.TransactionTypeAdjustment
+
+	filter := bson.M{"userUid": txn.UserUID, "currency": txn.Currency}
+	if txn.Amount < 0 {
+		filter["amount"] = bson.M{"$gte": -txn.Amount}
+	}
+
+	transactionDb, err := mr.balanceMove(ctx, filter, txn.Amount, transactionDb)
+	if err != nil {
+		mr.logger.Error("failed to adjust balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrAdjustment)
+	}
+
+	return transactionFromDB(transactionDb), nil
+}
+
+// BalanceDecrementByUserUIDAndCurrency debits user balance, replayed provider transaction returns the original one
+func (mr *Repo) BalanceDecrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	transactionDb := transactionToDB(txn)
+	transactionDb.Type = domain.TransactionTypeDebit
+
+	filter, delta := balanceMoveFilter(transactionDb)
+	transactionDb, err := mr.balanceMove(ctx, filter, delta, transactionDb)
+	if err != nil {
+		mr.logger.Error("failed to decrement balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrDecrement)
+	}
+
+	return transactionFromDB(transactionDb), nil
+}
+
+// BalanceIncrementByUserUIDAndCurrency credits user balance, replayed provider transaction returns the original one
+func (mr *Repo) BalanceIncrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	transactionDb := transactionToDB(txn)
+	transactionDb.Type = domain.TransactionTypeCredit
+
+	filter, delta := balanceMoveFilter(transactionDb)
+	transactionDb, err := mr.balanceMove(ctx, filter, delta, transactionDb)
+	if err != nil {
+		mr.logger.Error("failed to increment balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrIncrement)
+	}
+
+	return transactionFromDB(transactionDb), nil
+}
+
+// balanceMoveError wraps error of balance movement keeping business error codes
+func balanceMoveError(err error, code string) *domain.Error {
+	switch {
+	case errors.Is(err, errTransactionRolledBack):
+		code = domain.ErrTransactionRolledBack
+	case errors.Is(err, errRoundClosed):
+		code = domain.ErrRoundClosed
+	case errors.Is(err, errRoundUser):
+		code = domain.ErrRoundUser
+	case errors.Is(err, errJackpotPayout):
+		code = domain.ErrJackpotPayout
+	case errors.Is(err, errTransactionConflict):
+		code = domain.ErrTransactionConflict
+	case errors.Is(err, errCampaignUnavailable):
+		code = domain.ErrCampaignUnavailable
+	case errors.Is(err, errGamingLimitExceeded):
+		code = domain.ErrGamingLimitExceeded
+	case errors.Is(err, errDepositLimitExceeded):
+		code = domain.ErrDepositLimitExceeded
+	}
+	return domain.NewError(balanceErrorSource).SetCode(code).Add(err)
+}
+
+// balanceMove applies delta to the balance matched by filter and stores the transaction in one db transaction,
+// if the provider transaction was already processed the stored transaction is returned without moving money
+func (mr *Repo) balanceMove(ctx context.Context, filter bson.M, delta int, transactionDb *transactionDB) (*transactionDB, error) {
+	var result *transactionDB
+	var replayed bool
+
 	// use transaction to avoid race condition
-	var transactionDb transactionDB
 	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			return err
 		}
-		var balanceDb balanceDB
-		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-		err = mr.db.Collection(balanceTable).FindOneAndUpdate(
-			sessionContext,
-			bson.M{"userUid": userUID, "currency": currency, "amount": bson.M{"$gte": amount}},
-			bson.M{"$inc": bson.M{"amount": -amount}},
-			opts,
-		).Decode(&balanceDb)
-		if err != nil {
-			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
-				return errAbort
-			}
-			return err
-		}
 
-		transactionDb = transactionDB{
-			UID:          domain.GenUID(),
-			UserUID:      balanceDb.UserUID,
-			Amount:       amount,
-			Currency:     balanceDb.Currency,
-			Denomination: balanceDb.Denomination,
-			Type:         domain.TransactionTypeDebit,
-		}
-		_, err = mr.db.Collection(transactionTable).InsertOne(sessionContext, transactionDb)
-		if err != nil {
+		result, replayed, err = mr.balanceMoveTx(sessionContext, filter, delta, transactionDb)
+		if err != nil || replayed {
 			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
 				return errAbort
 			}
 			return err
 		}
-		if err = sessionContext.CommitTransaction(sessionContext); err != nil {
-			return err
-		}
 
-		return nil
+		return sessionContext.CommitTransaction(sessionContext)
 	})
+	if err != nil && transactionDb.ProviderTransactionUID != "" {
+		// concurrent retry of the same provider transaction could win the race, return its result
+		existing, errFind := mr.transactionFindByProviderUID(ctx, transactionDb.ProviderUID, transactionDb.ProviderTransactionUID, transactionDb.Type)
+		if errFind == nil {
+			return existing, nil
+		}
+	}
 	if err != nil {
-		mr.logger.Error("failed to decrement balance", "userUid", userUID, "currency", currency, "amount", amount, "error", err)
-		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrDecrement).Add(err)
+		return nil, err
+	}
+	if replayed {
+		mr.logger.Info("provider transaction replayed", "providerTransactionUid", result.ProviderTransactionUID, "uid", result.UID)
 	}
 
-	return &domain.Transaction{
-		UID:          transactionDb.UID,
-		UserUID:      transactionDb.UserUID,
-		SessionUID:   transactionDb.SessionUID,
-		Amount:       transactionDb.Amount,
-		Currency:     transactionDb.Currency,
-		Denomination: transactionDb.Denomination,
-		Type:         transactionDb.Type,
-	}, nil
+	return result, nil
 }
 
-func (mr *Repo) BalanceIncrementByUserUIDAndCurrency(ctx context.Context, userUID, currency string, amount int) (*domain.Transaction, error) {
-	// use transaction to avoid race condition
-	var transactionDb transactionDB
-	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
-		err := sessionContext.StartTransaction()
-		if err != nil {
-			return err
+// balanceMoveTx is balanceMove within the started db transaction, reports whether the provider transaction is replayed
+func (mr *Repo) balanceMoveTx(sessionContext mongo.SessionContext, filter bson.M, delta int, transactionDb *transactionDB) (*transactionDB, bool, error) {
+	if transactionDb.ProviderTransactionUID != "" {
+		replayed, err := mr.transactionFindByProviderUID(sessionContext, transactionDb.ProviderUID, transactionDb.ProviderTransactionUID, transactionDb.Type)
+		if err == nil {
+			return replayed, true, nil
 		}
-		var balanceDb balanceDB
-		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-		err = mr.db.Collection(balanceTable).FindOneAndUpdate(
-			sessionContext,
-			bson.M{"userUid": userUID, "currency": currency},
-			bson.M{"$inc": bson.M{"amount": amount}},
-			opts,
-		).Decode(&balanceDb)
-		if err != nil {
-			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
-				return errAbort
-			}
-			return err
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, false, err
 		}
 
-		transactionDb = transactionDB{
-			UID:          domain.GenUID(),
-			UserUID:      balanceDb.UserUID,
-			Amount:       amount,
-			Currency:     balanceDb.Currency,
-			Denomination: balanceDb.Denomination,
-			Type:         domain.TransactionTypeCredit,
-		}
-		_, err = mr.db.Collection(transactionTable).InsertOne(sessionContext, transactionDb)
-		if err != nil {
-			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
-				return errAbort
-			}
-			return err
+		// rollback could come before the transaction itself, late transaction must not move money
+		_, err = mr.transactionFindByProviderUID(sessionContext, transactionDb.ProviderUID, transactionDb.ProviderTransactionUID, domain.TransactionTypeRollback)
+		if err == nil {
+			return nil, false, errTransactionRolledBack
 		}
-		if err = sessionContext.CommitTransaction(sessionContext); err != nil {
-			return err
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, false, err
 		}
+	}
 
-		return nil
-	})
+	err := mr.gamingLimitCheck(sessionContext, transactionDb)
 	if err != nil {
-		mr.logger.Error("failed to increment balance", "userUid", userUID, "currency", currency, "amount", amount, "error", err)
-		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrIncrement).Add(err)
+		return nil, false, err
 	}
 
-	return &domain.Transaction{
-		UID:          transactionDb.UID,
-		UserUID:      transactionDb.UserUID,
-		SessionUID:   transactionDb.SessionUID,
-		Amount:       transactionDb.Amount,
-		Currency:     transactionDb.Currency,
-		Denomination: transactionDb.Denomination,
-		Type:         transactionDb.Type,
-	}, nil
+	transactionDb.UID = domain.GenUID()
+	transactionDb.Status = domain.TransactionStatusCommitted
+	transactionDb.CreatedAt = time.Now()
+	err = mr.balanceBook(sessionContext, filter, delta, transactionDb, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, err = mr.db.Collection(transactionTable).InsertOne(sessionContext, transactionDb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	betDelta, bonusBetDelta, winDelta := 0, 0, 0
+	switch transactionDb.Type {
+	case domain.TransactionTypeDebit:
+		betDelta, bonusBetDelta = transactionDb.Amount, transactionDb.BonusAmount
+	case domain.TransactionTypeCredit:
+		winDelta = transactionDb.Amount
+	}
+	err = mr.roundApply(sessionContext, transactionDb, betDelta, bonusBetDelta, winDelta)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = mr.jackpotApply(sessionContext, transactionDb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = mr.campaignApply(sessionContext, transactionDb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = mr.gamingCounterApply(sessionContext, transactionDb, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = mr.sessionApply(sessionContext, transactionDb, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = mr.outboxAdd(sessionContext, transactionDb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return transactionDb, false, nil
+}
+
+// balanceBook reads the balance matched by filter, books the transaction moving delta of it and stores the moved balance,
+// original is the transaction compensated by rollback, must be called within db transaction, so concurrent movement conflicts
+func (mr *Repo) balanceBook(sessionContext mongo.SessionContext, filter bson.M, delta int, transactionDb, originalDb *transactionDB) error {
+	var balanceDb balanceDB
+	err := mr.db.Collection(balanceTable).FindOne(sessionContext, filter).Decode(&balanceDb)
+	if err != nil {
+		return err
+	}
+	round, err := mr.roundFind(sessionContext, transactionDb.ProviderUID, transactionDb.RoundUID)
+	if err != nil {
+		return err
+	}
+
+	balance := domain.Balance{
+		UserUID:      balanceDb.UserUID,
+		Amount:       balanceDb.Amount,
+		Bonus:        balanceDb.Bonus,
+		Wagering:     balanceDb.Wagering,
+		Currency:     balanceDb.Currency,
+		Denomination: balanceDb.Denomination,
+	}
+	txn := transactionFromDB(transactionDb)
+	txn.BonusOrder = transactionDb.BonusOrder
+	var original *domain.Transaction
+	if originalDb != nil {
+		original = transactionFromDB(originalDb)
+	}
+	balance.Book(txn, delta, original, round)
+
+	_, err = mr.db.Collection(balanceTable).UpdateOne(
+		sessionContext,
+		bson.M{"userUid": balance.UserUID, "currency": balance.Currency},
+		bson.M{"$set": bson.M{
+			"amount":             balance.Amount,
+			"bonus":              balance.Bonus,
+			"wagering":           balance.Wagering,
+			"lastTransactionUid": transactionDb.UID,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+
+	transactionDb.UserUID = balance.UserUID
+	transactionDb.Currency = balance.Currency
+	transactionDb.Denomination = balance.Denomination
+	transactionDb.BalanceBefore = txn.BalanceBefore
+	transactionDb.Balance = txn.Balance
+	transactionDb.BonusAmount = txn.BonusAmount
+	transactionDb.BonusBalance = txn.BonusBalance
+	return nil
+}
+
+// balanceMoveFilter returns the balance filter and delta of debit or credit, debit requires balance to cover it
+func balanceMoveFilter(transactionDb *transactionDB) (bson.M, int) {
+	filter := bson.M{"userUid": transactionDb.UserUID, "currency": transactionDb.Currency}
+	if transactionDb.Type == domain.TransactionTypeDebit {
+		filter["amount"] = bson.M{"$gte": transactionDb.Amount}
+		return filter, -transactionDb.Amount
+	}
+	return filter, transactionDb.Amount
 }
 
 func (mr *Repo) balanceEnsureIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
-		{Keys: bson.D{{"userUid", -1}, {"currency", -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userUid", Value: -1}, {Key: "currency", Value: -1}}, Options: options.Index().SetUnique(true)},
 	}
 	_, err := mr.db.Collection(balanceTable).Indexes().CreateMany(ctx, indexes)
 	if err != nil {
@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// table name in DB
+	campaignTable = "campaign"
+
+	// errors prefix
+	campaignErrorSource = "[repository.mongodb.campaign]"
+)
+
+// errCampaignUnavailable signals that the campaign can't pay the free round
+var errCampaignUnavailable = errors.New("campaign not found, not active or has no rounds left")
+
+type campaignDB struct {
+	UID                string                `bson:"uid"`
+	UserUID            string                `bson:"userUid"`
+	GameUID            string                `bson:"gameUid"`
+	Currency           string                `bson:"currency"`
+	Rounds             int                   `bson:"rounds"`
+	RoundsLeft         int                   `bson:"roundsLeft"`
+	BetValue           int                   `bson:"betValue"`
+	Win                domain.CampaignWin    `bson:"win"`
+	WageringMultiplier int                   `bson:"wageringMultiplier,omitempty"`
+	Status             domain.CampaignStatus `bson:"status"`
+	Operator           string                `bson:"operator,omitempty"`
+	CreatedAt          time.Time             `bson:"createdAt"`
+	ExpiresAt          time.Time             `bson:"expiresAt"`
+}
+
+func (mr *Repo) CampaignGetByUID(ctx context.Context, uid string) (*domain.Campaign, error) {
+	var result campaignDB
+	err := mr.db.Collection(campaignTable).FindOne(ctx, bson.M{"uid": uid}).Decode(&result)
+	if err != nil {
+		mr.logger.Error("failed to find campaign", "uid", uid, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return campaignFromDB(&result), nil
+}
+
+// CampaignList searches campaigns by the filter, newest first
+func (mr *Repo) CampaignList(ctx context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error) {
+	query := bson.M{}
+	if filter.UserUID != "" {
+		query["userUid"] = filter.UserUID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	cursor, err := mr.db.Collection(campaignTable).Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "uid", Value: -1}}),
+	)
+	if err != nil {
+		mr.logger.Error("failed to list campaigns", "filter", filter, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var results []campaignDB
+	if err = cursor.All(ctx, &results); err != nil {
+		mr.logger.Error("failed to decode campaigns", "filter", filter, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	campaigns := make([]domain.Campaign, 0, len(results))
+	for _, result := range results {
+		campaigns = append(campaigns, *campaignFromDB(&result))
+	}
+	return campaigns, nil
+}
+
+func (mr *Repo) CampaignCreate(ctx context.Context, campaign *domain.Campaign) error {
+	campaignDb := campaignToDB(campaign)
+	_, err := mr.db.Collection(campaignTable).InsertOne(ctx, campaignDb)
+	if err != nil {
+		mr.logger.Error("failed to create campaign", "document", campaignDb, "error", err)
+		return domain.NewError(campaignErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// CampaignCancel cancels the active campaign, its rounds left can't be played anymore
+func (mr *Repo) CampaignCancel(ctx context.Context, uid string) (*domain.Campaign, error) {
+	var result campaignDB
+	err := mr.db.Collection(campaignTable).FindOneAndUpdate(
+		ctx,
+		bson.M{"uid": uid, "status": domain.CampaignStatusActive},
+		bson.M{"$set": bson.M{"status": domain.CampaignStatusCancelled}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&result)
+	if err == nil {
+		return campaignFromDB(&result), nil
+	}
+
+	code := domain.ErrRepoUpdate
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// tell missing campaign from the one which isn't active anymore
+		code = domain.ErrCampaignUnavailable
+		if _, errFind := mr.CampaignGetByUID(ctx, uid); errFind != nil {
+			code = domain.ErrNotFound
+		}
+	}
+	mr.logger.Error("failed to cancel campaign", "uid", uid, "error", err)
+	return nil, domain.NewError(campaignErrorSource).SetCode(code).Add(err)
+}
+
+// campaignApply takes the round of the free round debit from its campaign, the campaign is completed without rounds left,
+// must be called within db transaction which moves the money
+func (mr *Repo) campaignApply(ctx context.Context, transactionDb *transactionDB) error {
+	if transactionDb.CampaignUID == "" || transactionDb.Type != domain.TransactionTypeDebit {
+		return nil
+	}
+
+	var result campaignDB
+	err := mr.db.Collection(campaignTable).FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"uid":        transactionDb.CampaignUID,
+			"userUid":    transactionDb.UserUID,
+			"status":     domain.CampaignStatusActive,
+			"roundsLeft": bson.M{"$gt": 0},
+			"expiresAt":  bson.M{"$gt": time.Now()},
+		},
+		bson.M{"$inc": bson.M{"roundsLeft": -1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errCampaignUnavailable
+	}
+	if err != nil || result.RoundsLeft > 0 {
+		return err
+	}
+
+	_, err = mr.db.Collection(campaignTable).UpdateOne(
+		ctx,
+		bson.M{"uid": transactionDb.CampaignUID},
+		bson.M{"$set": bson.M{"status": domain.CampaignStatusCompleted}},
+	)
+	return err
+}
+
+// campaignRevert returns the round of the rolled back free round debit to its campaign, completed campaign is active again,
+// must be called within db transaction which rolls back the money
+func (mr *Repo) campaignRevert(ctx context.Context, originalDb *transactionDB) error {
+	if originalDb.CampaignUID == "" || originalDb.Type != domain.TransactionTypeDebit {
+		return nil
+	}
+
+	_, err := mr.db.Collection(campaignTable).UpdateOne(
+		ctx,
+		bson.M{"uid": originalDb.CampaignUID},
+		bson.M{"$inc": bson.M{"roundsLeft": 1}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = mr.db.Collection(campaignTable).UpdateOne(
+		ctx,
+		bson.M{"uid": originalDb.CampaignUID, "status": domain.CampaignStatusCompleted},
+		bson.M{"$set": bson.M{"status": domain.CampaignStatusActive}},
+	)
+	return err
+}
+
+func campaignToDB(c *domain.Campaign) *campaignDB {
+	return &campaignDB{
+		UID:                c.UID,
+		UserUID:            c.UserUID,
+		GameUID:            c.GameUID,
+		Currency:           c.Currency,
+		Rounds:             c.Rounds,
+		RoundsLeft:         c.RoundsLeft,
+		BetValue:           c.BetValue,
+		Win:                c.Win,
+		WageringMultiplier: c.WageringMultiplier,
+		Status:             c.Status,
+		Operator:           c.Operator,
+		CreatedAt:          c.CreatedAt,
+		ExpiresAt:          c.ExpiresAt,
+	}
+}
+
+func campaignFromDB(c *campaignDB) *domain.Campaign {
+	return &domain.Campaign{
+		UID:                c.UID,
+		UserUID:            c.UserUID,
+		GameUID:            c.GameUID,
+		Currency:           c.Currency,
+		Rounds:             c.Rounds,
+		RoundsLeft:         c.RoundsLeft,
+		BetValue:           c.BetValue,
+		Win:                c.Win,
+		WageringMultiplier: c.WageringMultiplier,
+		Status:             c.Status,
+		Operator:           c.Operator,
+		CreatedAt:          c.CreatedAt,
+		ExpiresAt:          c.ExpiresAt,
+	}
+}
+
+func (mr *Repo) campaignEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userUid", Value: -1}, {Key: "createdAt", Value: -1}}},
+	}
+	_, err := mr.db.Collection(campaignTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(campaignErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
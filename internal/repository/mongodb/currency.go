@@ -19,6 +19,7 @@ const (
 type currencyDB struct {
 	Code         string `bson:"code"`
 	Denomination int    `bson:"denomination"`
+	MaxWin       int    `bson:"maxWin"`
 }
 
 func (mr *Repo) CurrencyGetByCode(ctx context.Context, code string) (*domain.Currency, error) {
@@ -31,6 +32,7 @@ func (mr *Repo) CurrencyGetByCode(ctx context.Context, code string) (*domain.Cur
 	return &domain.Currency{
 		Code:         result.Code,
 		Denomination: result.Denomination,
+		MaxWin:       result.MaxWin,
 	}, nil
 }
 
@@ -38,6 +40,7 @@ func (mr *Repo) CurrencyCreate(ctx context.Context, cur *domain.Currency) error
 	currencyDb := currencyDB{
 		Code:         cur.Code,
 		Denomination: cur.Denomination,
+		MaxWin:       cur.MaxWin,
 	}
 
 	_, err := mr.db.Collection(currencyTable).InsertOne(ctx, currencyDb)
@@ -48,9 +51,61 @@ func (mr *Repo) CurrencyCreate(ctx context.Context, cur *domain.Currency) error
 	return nil
 }
 
+func (mr *Repo) CurrencyUpdate(ctx context.Context, cur *domain.Currency) error {
+	res, err := mr.db.Collection(currencyTable).UpdateOne(
+		ctx,
+		bson.M{"code": cur.Code},
+		bson.M{"$set": bson.M{"denomination": cur.Denomination, "maxWin": cur.MaxWin}},
+	)
+	if err != nil {
+		mr.logger.Error("failed to update currency", "code", cur.Code, "error", err)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) CurrencyDelete(ctx context.Context, code string) error {
+	res, err := mr.db.Collection(currencyTable).DeleteOne(ctx, bson.M{"code": code})
+	if err != nil {
+		mr.logger.Error("failed to delete currency", "code", code, "error", err)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) CurrencyList(ctx context.Context) ([]domain.Currency, error) {
+	cursor, err := mr.db.Collection(currencyTable).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "code", Value: 1}}))
+	if err != nil {
+		mr.logger.Error("failed to list currencies", "error", err)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []currencyDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode currencies", "error", err)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	currencies := make([]domain.Currency, 0, len(result))
+	for _, c := range result {
+		currencies = append(currencies, domain.Currency{
+			Code:         c.Code,
+			Denomination: c.Denomination,
+			MaxWin:       c.MaxWin,
+		})
+	}
+	return currencies, nil
+}
+
 func (mr *Repo) currencyEnsureIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
-		{Keys: bson.D{{"code", -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "code", Value: -1}}, Options: options.Index().SetUnique(true)},
 	}
 	_, err := mr.db.Collection(currencyTable).Indexes().CreateMany(ctx, indexes)
 	if err != nil {
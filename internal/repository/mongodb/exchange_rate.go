@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// table name in DB
+	exchangeRateTable = "exchangeRate"
+
+	// errors prefix
+	exchangeRateErrorSource = "[repository.mongodb.exchange_rate]"
+)
+
+type exchangeRateDB struct {
+	From      string    `bson:"from"`
+	To        string    `bson:"to"`
+	Rate      float64   `bson:"rate"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func (mr *Repo) ExchangeRateGet(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	var result exchangeRateDB
+	err := mr.db.Collection(exchangeRateTable).FindOne(ctx, bson.M{"from": from, "to": to}).Decode(&result)
+	if err != nil {
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return exchangeRateFromDB(&result), nil
+}
+
+func (mr *Repo) ExchangeRateList(ctx context.Context) ([]domain.ExchangeRate, error) {
+	cursor, err := mr.db.Collection(exchangeRateTable).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}}))
+	if err != nil {
+		mr.logger.Error("failed to list exchange rates", "error", err)
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []exchangeRateDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode exchange rates", "error", err)
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	rates := make([]domain.ExchangeRate, 0, len(result))
+	for i := range result {
+		rates = append(rates, *exchangeRateFromDB(&result[i]))
+	}
+	return rates, nil
+}
+
+// ExchangeRateSave creates the rate of the currency pair or replaces the existing one
+func (mr *Repo) ExchangeRateSave(ctx context.Context, rate *domain.ExchangeRate) error {
+	_, err := mr.db.Collection(exchangeRateTable).UpdateOne(
+		ctx,
+		bson.M{"from": rate.From, "to": rate.To},
+		bson.M{"$set": bson.M{"rate": rate.Rate, "updatedAt": rate.UpdatedAt}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		mr.logger.Error("failed to save exchange rate", "from", rate.From, "to", rate.To, "error", err)
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return nil
+}
+
+func (mr *Repo) ExchangeRateDelete(ctx context.Context, from, to string) error {
+	res, err := mr.db.Collection(exchangeRateTable).DeleteOne(ctx, bson.M{"from": from, "to": to})
+	if err != nil {
+		mr.logger.Error("failed to delete exchange rate", "from", from, "to", to, "error", err)
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) exchangeRateEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+	_, err := mr.db.Collection(exchangeRateTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
+
+func exchangeRateFromDB(r *exchangeRateDB) *domain.ExchangeRate {
+	return &domain.ExchangeRate{
+		From:      r.From,
+		To:        r.To,
+		Rate:      r.Rate,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// table name in DB
+	gamingCounterTable = "gamingCounter"
+
+	// errors prefix
+	gamingCounterErrorSource = "[repository.mongodb.gaming_counter]"
+)
+
+var (
+	// errGamingLimitExceeded signals that the debit takes the user over the responsible gaming limit
+	errGamingLimitExceeded = errors.New("responsible gaming limit exceeded")
+	// errDepositLimitExceeded signals that the deposit takes the user over the deposit limit
+	errDepositLimitExceeded = errors.New("deposit limit exceeded")
+)
+
+type gamingCounterDB struct {
+	UserUID  string             `bson:"userUid"`
+	Currency string             `bson:"currency"`
+	Period   domain.LimitPeriod `bson:"period"`
+	Start    time.Time          `bson:"start"`
+	Wager    int                `bson:"wager"`
+	Loss     int                `bson:"loss"`
+	Deposit  int                `bson:"deposit"`
+}
+
+// GamingCounterList returns the counters of the user in the currency for the periods the moment belongs to,
+// the period without money movement has zero counter
+func (mr *Repo) GamingCounterList(ctx context.Context, userUID, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	counters, err := mr.gamingCounterList(ctx, userUID, currency, now)
+	if err != nil {
+		mr.logger.Error("failed to find gaming counter", "userUid", userUID, "currency", currency, "error", err)
+		return nil, domain.NewError(gamingCounterErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return counters, nil
+}
+
+func (mr *Repo) gamingCounterList(ctx context.Context, userUID, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	counters := make([]domain.GamingCounter, 0, len(domain.LimitPeriods))
+	for _, period := range domain.LimitPeriods {
+		counter := domain.GamingCounter{UserUID: userUID, Currency: currency, Period: period, Start: period.Start(now)}
+
+		var result gamingCounterDB
+		err := mr.db.Collection(gamingCounterTable).FindOne(ctx, gamingCounterFilter(userUID, currency, period, counter.Start)).Decode(&result)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		counter.Wager, counter.Loss, counter.Deposit = result.Wager, result.Loss, result.Deposit
+		counters = append(counters, counter)
+	}
+	return counters, nil
+}
+
+// gamingLimitCheck checks the debit or the deposit against the gaming limits of the draft, must be called within db transaction
+// which moves the money, concurrent movement of the same balance conflicts with it, so both can't take the user over the limit
+func (mr *Repo) gamingLimitCheck(ctx context.Context, transactionDb *transactionDB) error {
+	deposit := transactionFromDB(transactionDb).IsDeposit()
+	if transactionDb.GamingLimits == nil || (transactionDb.Type != domain.TransactionTypeDebit && !deposit) {
+		return nil
+	}
+	counters, err := mr.gamingCounterList(ctx, transactionDb.UserUID, transactionDb.Currency, time.Now())
+	if err != nil {
+		return err
+	}
+	if deposit {
+		if transactionDb.GamingLimits.DepositExceeded(counters, transactionDb.Amount) != nil {
+			return errDepositLimitExceeded
+		}
+		return nil
+	}
+	if transactionDb.GamingLimits.Exceeded(counters, transactionDb.Amount) != nil {
+		return errGamingLimitExceeded
+	}
+	return nil
+}
+
+// gamingCounterApply counts the transaction to the periods of its creation, rollback is counted to the periods
+// of the original transaction, must be called within db transaction which moves the money
+func (mr *Repo) gamingCounterApply(ctx context.Context, transactionDb, originalDb *transactionDB) error {
+	var original *domain.Transaction
+	at := transactionDb.CreatedAt
+	if originalDb != nil {
+		original, at = transactionFromDB(originalDb), originalDb.CreatedAt
+	}
+	txn := transactionFromDB(transactionDb)
+	wager, loss := domain.GamingDelta(txn, original)
+	deposit := domain.DepositDelta(txn)
+	if wager == 0 && loss == 0 && deposit == 0 {
+		return nil
+	}
+
+	for _, period := range domain.LimitPeriods {
+		_, err := mr.db.Collection(gamingCounterTable).UpdateOne(
+			ctx,
+			gamingCounterFilter(transactionDb.UserUID, transactionDb.Currency, period, period.Start(at)),
+			bson.M{"$inc": bson.M{"wager": wager, "loss": loss, "deposit": deposit}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gamingCounterFilter(userUID, currency string, period domain.LimitPeriod, start time.Time) bson.M {
+	return bson.M{"userUid": userUID, "currency": currency, "period": period, "start": start}
+}
+
+func (mr *Repo) gamingCounterEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userUid", Value: -1},
+				{Key: "currency", Value: -1},
+				{Key: "period", Value: -1},
+				{Key: "start", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	_, err := mr.db.Collection(gamingCounterTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(gamingCounterErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// table name in DB
+	jackpotTable = "jackpot"
+
+	// errors prefix
+	jackpotErrorSource = "[repository.mongodb.jackpot]"
+)
+
+// errJackpotPayout signals that jackpot pool can't cover the payout
+var errJackpotPayout = errors.New("jackpot pool not found or insufficient")
+
+type jackpotDB struct {
+	Key                 string  `bson:"key"`
+	Name                string  `bson:"name"`
+	Currency            string  `bson:"currency"`
+	GameUID             string  `bson:"gameUid"`
+	Amount              int     `bson:"amount"`
+	ContributionPercent float64 `bson:"contributionPercent"`
+	IsActive            bool    `bson:"isActive"`
+}
+
+func (mr *Repo) JackpotGetByKey(ctx context.Context, key, currency string) (*domain.Jackpot, error) {
+	var result jackpotDB
+	err := mr.db.Collection(jackpotTable).FindOne(ctx, bson.M{"key": key, "currency": currency}).Decode(&result)
+	if err != nil {
+		mr.logger.Error("failed to find jackpot", "key", key, "currency", currency, "error", err)
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return jackpotFromDB(&result), nil
+}
+
+// JackpotListActiveByGame returns active pools of the game in the currency including shared ones
+func (mr *Repo) JackpotListActiveByGame(ctx context.Context, gameUID, currency string) ([]domain.Jackpot, error) {
+	cursor, err := mr.db.Collection(jackpotTable).Find(
+		ctx,
+		bson.M{"currency": currency, "isActive": true, "gameUid": bson.M{"$in": bson.A{gameUID, ""}}},
+		options.Find().SetSort(bson.D{{Key: "gameUid", Value: -1}, {Key: "key", Value: 1}}),
+	)
+	if err != nil {
+		mr.logger.Error("failed to find jackpots", "gameUid", gameUID, "currency", currency, "error", err)
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var results []jackpotDB
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	jackpots := make([]domain.Jackpot, 0, len(results))
+	for _, result := range results {
+		jackpots = append(jackpots, *jackpotFromDB(&result))
+	}
+	return jackpots, nil
+}
+
+func (mr *Repo) JackpotCreate(ctx context.Context, jackpot *domain.Jackpot) error {
+	jackpotDb := jackpotDB{
+		Key:                 jackpot.Key,
+		Name:                jackpot.Name,
+		Currency:            jackpot.Currency,
+		GameUID:             jackpot.GameUID,
+		Amount:              jackpot.Amount,
+		ContributionPercent: jackpot.ContributionPercent,
+		IsActive:            jackpot.IsActive,
+	}
+
+	_, err := mr.db.Collection(jackpotTable).InsertOne(ctx, jackpotDb)
+	if err != nil {
+		mr.logger.Error("failed to create jackpot", "record", jackpotDb, "error", err)
+		return domain.NewError(jackpotErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// jackpotApply moves jackpot contribution of the debit into the pool or jackpot payout of the credit out of it,
+// must be called within db transaction which moves the money
+func (mr *Repo) jackpotApply(ctx context.Context, transactionDb *transactionDB) error {
+	if transactionDb.JackpotKey == "" {
+		return nil
+	}
+
+	filter := bson.M{"key": transactionDb.JackpotKey, "currency": transactionDb.Currency}
+	var delta int
+	switch transactionDb.Type {
+	case domain.TransactionTypeDebit:
+		delta = transactionDb.JackpotContribution
+	case domain.TransactionTypeCredit:
+		filter["amount"] = bson.M{"$gte": transactionDb.Amount}
+		delta = -transactionDb.Amount
+	default:
+		return nil
+	}
+
+	res, err := mr.db.Collection(jackpotTable).UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"amount": delta}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errJackpotPayout
+	}
+	return nil
+}
+
+// jackpotRevert returns jackpot contribution or payout of the rolled back transaction,
+// must be called within db transaction which rolls back the money
+func (mr *Repo) jackpotRevert(ctx context.Context, originalDb *transactionDB) error {
+	if originalDb.JackpotKey == "" {
+		return nil
+	}
+
+	var delta int
+	switch originalDb.Type {
+	case domain.TransactionTypeDebit:
+		delta = -originalDb.JackpotContribution
+	case domain.TransactionTypeCredit:
+		delta = originalDb.Amount
+	default:
+		return nil
+	}
+
+	_, err := mr.db.Collection(jackpotTable).UpdateOne(
+		ctx,
+		bson.M{"key": originalDb.JackpotKey, "currency": originalDb.Currency},
+		bson.M{"$inc": bson.M{"amount": delta}},
+	)
+	return err
+}
+
+func jackpotFromDB(j *jackpotDB) *domain.Jackpot {
+	return &domain.Jackpot{
+		Key:                 j.Key,
+		Name:                j.Name,
+		Currency:            j.Currency,
+		GameUID:             j.GameUID,
+		Amount:              j.Amount,
+		ContributionPercent: j.ContributionPercent,
+		IsActive:            j.IsActive,
+	}
+}
+
+func (mr *Repo) jackpotEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: -1}, {Key: "currency", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "gameUid", Value: -1}, {Key: "currency", Value: -1}}},
+	}
+	_, err := mr.db.Collection(jackpotTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(jackpotErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
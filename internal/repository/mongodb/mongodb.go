@@ -171,6 +171,41 @@ func (mr *Repo) EnsureIndexes(ctx context.Context) error {
 		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
 	}
 
+	err = mr.roundEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.jackpotEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.providerEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.exchangeRateEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.campaignEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.gamingCounterEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	err = mr.outboxEnsureIndexes(ctx)
+	if err != nil {
+		return domain.NewError(mongodbErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
 	// TODO: Add indexes
 
 	return nil
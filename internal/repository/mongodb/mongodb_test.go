@@ -0,0 +1,35 @@
+package mongodb_test
+
+import (
+	"context"
+	"log/slog"
+	"open-api-games/internal/repository/mongodb"
+	"open-api-games/internal/repository/repotest"
+	"os"
+	"testing"
+)
+
+// TestRepoContract needs mongodb replica set, e.g. MONGODB_TEST_URI="mongodb://127.0.0.1:27017/openapigames_test?replicaSet=rs0"
+func TestRepoContract(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+	t.Setenv("MONGODB_URI", uri)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	repo, err := mongodb.Connect(ctx, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close(ctx)
+
+	if err = repo.EnsureIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.Run(t, repo)
+}
@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// table name in DB
+	outboxTable = "outbox"
+
+	// errors prefix
+	outboxErrorSource = "[repository.mongodb.outbox]"
+)
+
+type outboxDB struct {
+	UID           string              `bson:"uid"`
+	Transaction   transactionDB       `bson:"transaction"`
+	Status        domain.OutboxStatus `bson:"status"`
+	Attempts      int                 `bson:"attempts"`
+	LastError     string              `bson:"lastError,omitempty"`
+	NextAttemptAt time.Time           `bson:"nextAttemptAt"`
+	CreatedAt     time.Time           `bson:"createdAt"`
+	DeliveredAt   time.Time           `bson:"deliveredAt,omitempty"`
+}
+
+// OutboxListPending returns up to limit pending events due at the moment in the order of their movements
+func (mr *Repo) OutboxListPending(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error) {
+	cursor, err := mr.db.Collection(outboxTable).Find(
+		ctx,
+		bson.M{"status": domain.OutboxStatusPending, "nextAttemptAt": bson.M{"$lte": now}},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		mr.logger.Error("failed to list outbox events", "error", err)
+		return nil, domain.NewError(outboxErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+
+	var results []outboxDB
+	if err = cursor.All(ctx, &results); err != nil {
+		mr.logger.Error("failed to read outbox events", "error", err)
+		return nil, domain.NewError(outboxErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+
+	events := make([]domain.OutboxEvent, 0, len(results))
+	for i := range results {
+		events = append(events, *outboxFromDB(&results[i]))
+	}
+	return events, nil
+}
+
+// OutboxUpdate stores the delivery state of the event
+func (mr *Repo) OutboxUpdate(ctx context.Context, event *domain.OutboxEvent) error {
+	res, err := mr.db.Collection(outboxTable).UpdateOne(
+		ctx,
+		bson.M{"uid": event.UID},
+		bson.M{"$set": bson.M{
+			"status":        event.Status,
+			"attempts":      event.Attempts,
+			"lastError":     event.LastError,
+			"nextAttemptAt": event.NextAttemptAt,
+			"deliveredAt":   event.DeliveredAt,
+		}},
+	)
+	if err != nil {
+		mr.logger.Error("failed to update outbox event", "uid", event.UID, "error", err)
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+// outboxAdd stores the event of the wallet movement, must be called within db transaction which moves the money
+func (mr *Repo) outboxAdd(ctx context.Context, transactionDb *transactionDB) error {
+	event := domain.NewOutboxEvent(transactionFromDB(transactionDb))
+	_, err := mr.db.Collection(outboxTable).InsertOne(ctx, outboxDB{
+		UID:           event.UID,
+		Transaction:   *transactionDb,
+		Status:        event.Status,
+		Attempts:      event.Attempts,
+		NextAttemptAt: event.NextAttemptAt,
+		CreatedAt:     event.CreatedAt,
+	})
+	return err
+}
+
+func outboxFromDB(o *outboxDB) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		UID:           o.UID,
+		Transaction:   *transactionFromDB(&o.Transaction),
+		Status:        o.Status,
+		Attempts:      o.Attempts,
+		LastError:     o.LastError,
+		NextAttemptAt: o.NextAttemptAt,
+		CreatedAt:     o.CreatedAt,
+		DeliveredAt:   o.DeliveredAt,
+	}
+}
+
+func (mr *Repo) outboxEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: -1}, {Key: "nextAttemptAt", Value: 1}}},
+	}
+	_, err := mr.db.Collection(outboxTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// table name in DB
+	providerTable = "provider"
+
+	// errors prefix
+	providerErrorSource = "[repository.mongodb.provider]"
+)
+
+type providerDB struct {
+	UID          string   `bson:"uid"`
+	Name         string   `bson:"name"`
+	Secrets      []string `bson:"secrets"`
+	AllowedIPs   []string `bson:"allowedIps"`
+	Enabled      bool     `bson:"enabled"`
+	Currencies   []string `bson:"currencies"`
+	SignScheme   string   `bson:"signScheme"`
+	AmountFormat string   `bson:"amountFormat"`
+}
+
+func (mr *Repo) ProviderGetByUID(ctx context.Context, uid string) (*domain.Provider, error) {
+	var result providerDB
+	err := mr.db.Collection(providerTable).FindOne(ctx, bson.M{"uid": uid}).Decode(&result)
+	if err != nil {
+		mr.logger.Error("failed to find provider", "uid", uid, "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return providerFromDB(result), nil
+}
+
+func (mr *Repo) ProviderCreate(ctx context.Context, provider *domain.Provider) error {
+	_, err := mr.db.Collection(providerTable).InsertOne(ctx, providerToDB(provider))
+	if err != nil {
+		mr.logger.Error("failed to create provider", "uid", provider.UID, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+func (mr *Repo) ProviderUpdate(ctx context.Context, provider *domain.Provider) error {
+	providerDb := providerToDB(provider)
+	res, err := mr.db.Collection(providerTable).UpdateOne(
+		ctx,
+		bson.M{"uid": provider.UID},
+		bson.M{"$set": bson.M{
+			"name":         providerDb.Name,
+			"secrets":      providerDb.Secrets,
+			"allowedIps":   providerDb.AllowedIPs,
+			"enabled":      providerDb.Enabled,
+			"currencies":   providerDb.Currencies,
+			"signScheme":   providerDb.SignScheme,
+			"amountFormat": providerDb.AmountFormat,
+		}},
+	)
+	if err != nil {
+		mr.logger.Error("failed to update provider", "uid", provider.UID, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) ProviderDelete(ctx context.Context, uid string) error {
+	res, err := mr.db.Collection(providerTable).DeleteOne(ctx, bson.M{"uid": uid})
+	if err != nil {
+		mr.logger.Error("failed to delete provider", "uid", uid, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) ProviderList(ctx context.Context) ([]domain.Provider, error) {
+	cursor, err := mr.db.Collection(providerTable).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "uid", Value: 1}}))
+	if err != nil {
+		mr.logger.Error("failed to list providers", "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []providerDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode providers", "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	providers := make([]domain.Provider, 0, len(result))
+	for _, p := range result {
+		providers = append(providers, *providerFromDB(p))
+	}
+	return providers, nil
+}
+
+func (mr *Repo) providerEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+	}
+	_, err := mr.db.Collection(providerTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
+
+func providerToDB(provider *domain.Provider) providerDB {
+	return providerDB{
+		UID:          provider.UID,
+		Name:         provider.Name,
+		Secrets:      provider.Secrets,
+		AllowedIPs:   provider.AllowedIPs,
+		Enabled:      provider.Enabled,
+		Currencies:   provider.Currencies,
+		SignScheme:   string(provider.SignScheme),
+		AmountFormat: string(provider.AmountFormat),
+	}
+}
+
+func providerFromDB(provider providerDB) *domain.Provider {
+	return &domain.Provider{
+		UID:          provider.UID,
+		Name:         provider.Name,
+		Secrets:      provider.Secrets,
+		AllowedIPs:   provider.AllowedIPs,
+		Enabled:      provider.Enabled,
+		Currencies:   provider.Currencies,
+		SignScheme:   domain.SignScheme(provider.SignScheme),
+		AmountFormat: domain.AmountFormat(provider.AmountFormat),
+	}
+}
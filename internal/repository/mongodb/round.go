@@ -0,0 +1,229 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// table name in DB
+	roundTable = "round"
+
+	// errors prefix
+	roundErrorSource = "[repository.mongodb.round]"
+
+	// legacyRoundIndex is the name of the bet id index made before it was scoped by provider
+	legacyRoundIndex = "uid_-1"
+)
+
+var (
+	// errRoundClosed signals that money movement came to already completed round
+	errRoundClosed = errors.New("round already closed")
+	// errRoundUser signals that bet id is already used by the round of another user
+	errRoundUser = errors.New("round belongs to another user")
+	// errRoundChanged signals that the round got new transactions since it was read
+	errRoundChanged = errors.New("round changed or already closed")
+)
+
+type roundDB struct {
+	ProviderUID string             `bson:"providerUid"`
+	UID         string             `bson:"uid"`
+	SessionUID  string             `bson:"sessionUid"`
+	UserUID     string             `bson:"userUid"`
+	Currency    string             `bson:"currency"`
+	Status      domain.RoundStatus `bson:"status"`
+	TotalBet    int                `bson:"totalBet"`
+	TotalWin    int                `bson:"totalWin"`
+	// TotalBonusBet is missing in rounds opened before bonus money
+	TotalBonusBet int `bson:"totalBonusBet"`
+}
+
+func (mr *Repo) RoundGetByProviderUID(ctx context.Context, providerUID, uid string) (*domain.Round, error) {
+	round, err := mr.roundGet(ctx, providerUID, uid)
+	if err != nil {
+		mr.logger.Error("failed to find round", "providerUid", providerUID, "uid", uid, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return round, nil
+}
+
+// RoundClose closes the round if it wasn't changed since it was read
+func (mr *Repo) RoundClose(ctx context.Context, round *domain.Round) (*domain.Round, error) {
+	// use transaction to conflict with the money movement adding transaction to the round
+	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			return err
+		}
+
+		err = mr.roundCloseTx(sessionContext, round)
+		if err != nil {
+			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
+				return errAbort
+			}
+			return err
+		}
+
+		return sessionContext.CommitTransaction(sessionContext)
+	})
+	if err != nil {
+		mr.logger.Error("failed to close round", "uid", round.UID, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrRoundClose).Add(err)
+	}
+
+	closed, err := mr.roundGet(ctx, round.ProviderUID, round.UID)
+	if err != nil {
+		mr.logger.Error("failed to find round", "uid", round.UID, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrRoundClose).Add(err)
+	}
+	return closed, nil
+}
+
+// roundCloseTx is RoundClose within the started db transaction
+func (mr *Repo) roundCloseTx(sessionContext mongo.SessionContext, round *domain.Round) error {
+	count, err := mr.db.Collection(transactionTable).CountDocuments(sessionContext, bson.M{"providerUid": round.ProviderUID, "roundUid": round.UID})
+	if err != nil {
+		return err
+	}
+	if int(count) != len(round.Transactions) {
+		return errRoundChanged
+	}
+
+	res, err := mr.db.Collection(roundTable).UpdateOne(
+		sessionContext,
+		bson.M{"providerUid": round.ProviderUID, "uid": round.UID, "status": domain.RoundStatusOpen},
+		bson.M{"$set": bson.M{"status": domain.RoundStatusClosed}},
+	)
+	if err == nil && res.ModifiedCount == 0 {
+		err = errRoundChanged
+	}
+	return err
+}
+
+// roundApply adds transaction to its round and updates round totals, round is opened by the first transaction,
+// must be called within db transaction which moves the money
+func (mr *Repo) roundApply(ctx context.Context, transactionDb *transactionDB, betDelta, bonusBetDelta, winDelta int) error {
+	if transactionDb.RoundUID == "" {
+		return nil
+	}
+
+	var existing roundDB
+	err := mr.db.Collection(roundTable).FindOne(ctx, roundQuery(transactionDb.ProviderUID, transactionDb.RoundUID)).Decode(&existing)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+	case err != nil:
+		return err
+	case existing.UserUID != transactionDb.UserUID:
+		return errRoundUser
+	case existing.Status == domain.RoundStatusClosed && transactionDb.Type != domain.TransactionTypeRollback:
+		// rollbacks are still accepted to refund the player
+		return errRoundClosed
+	}
+
+	_, err = mr.db.Collection(roundTable).UpdateOne(
+		ctx,
+		roundQuery(transactionDb.ProviderUID, transactionDb.RoundUID),
+		bson.M{
+			"$setOnInsert": bson.M{
+				"sessionUid": transactionDb.SessionUID,
+				"userUid":    transactionDb.UserUID,
+				"currency":   transactionDb.Currency,
+				"status":     domain.RoundStatusOpen,
+			},
+			"$inc": bson.M{"totalBet": betDelta, "totalBonusBet": bonusBetDelta, "totalWin": winDelta},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// roundFind reads the round the win is split by, nil is returned for the round which isn't opened yet
+func (mr *Repo) roundFind(ctx context.Context, providerUID, uid string) (*domain.Round, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	var result roundDB
+	err := mr.db.Collection(roundTable).FindOne(ctx, roundQuery(providerUID, uid)).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return roundFromDB(&result), nil
+}
+
+// roundGet reads the round with its stored transactions in the order they were made
+func (mr *Repo) roundGet(ctx context.Context, providerUID, uid string) (*domain.Round, error) {
+	var result roundDB
+	err := mr.db.Collection(roundTable).FindOne(ctx, roundQuery(providerUID, uid)).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	round := roundFromDB(&result)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := mr.db.Collection(transactionTable).Find(ctx, bson.M{"providerUid": providerUID, "roundUid": uid}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var transactions []transactionDB
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	round.Transactions = make([]domain.RoundTransaction, 0, len(transactions))
+	for _, txn := range transactions {
+		round.Transactions = append(round.Transactions, domain.RoundTransaction{
+			UID:                  txn.UID,
+			Type:                 txn.Type,
+			Amount:               txn.Amount,
+			ParentTransactionUID: txn.ParentTransactionUID,
+		})
+	}
+	return round, nil
+}
+
+// roundQuery matches the round by bet id of the provider
+func roundQuery(providerUID, uid string) bson.M {
+	return bson.M{"providerUid": providerUID, "uid": uid}
+}
+
+func roundFromDB(r *roundDB) *domain.Round {
+	return &domain.Round{
+		ProviderUID:   r.ProviderUID,
+		UID:           r.UID,
+		SessionUID:    r.SessionUID,
+		UserUID:       r.UserUID,
+		Currency:      r.Currency,
+		Status:        r.Status,
+		TotalBet:      r.TotalBet,
+		TotalWin:      r.TotalWin,
+		TotalBonusBet: r.TotalBonusBet,
+	}
+}
+
+func (mr *Repo) roundEnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		// bet id is unique per provider
+		{Keys: bson.D{{Key: "providerUid", Value: -1}, {Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "sessionUid", Value: -1}}},
+	}
+	_, err := mr.db.Collection(roundTable).Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return domain.NewError(roundErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	// the index unique across providers is replaced by the one above
+	_, err = mr.db.Collection(roundTable).Indexes().DropOne(ctx, legacyRoundIndex)
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return domain.NewError(roundErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"open-api-games/internal/domain"
+	"time"
 )
 
 const (
@@ -17,27 +18,48 @@ const (
 )
 
 type sessionDB struct {
-	UID     string `bson:"uid"`
-	UserUID string `bson:"userUid"`
+	UID              string               `bson:"uid"`
+	UserUID          string               `bson:"userUid"`
+	GameUID          string               `bson:"gameUid"`
+	ProviderUID      string               `bson:"providerUid"`
+	Currency         string               `bson:"currency"`
+	MaxWin           int                  `bson:"maxWin"`
+	Status           domain.SessionStatus `bson:"status"`
+	CreatedAt        time.Time            `bson:"createdAt"`
+	LastActivityAt   time.Time            `bson:"lastActivityAt"`
+	ExpiresAt        time.Time            `bson:"expiresAt"`
+	TotalBet         int                  `bson:"totalBet"`
+	TotalWin         int                  `bson:"totalWin"`
+	RealityCheckedAt time.Time            `bson:"realityCheckedAt,omitempty"`
+	WalletCurrency   string               `bson:"walletCurrency,omitempty"`
 }
 
 func (mr *Repo) SessionGetByUID(ctx context.Context, uid string) (*domain.Session, error) {
 	var result sessionDB
 	err := mr.db.Collection(sessionTable).FindOne(ctx, bson.M{"uid": uid}).Decode(&result)
 	if err != nil {
-		mr.logger.Error("failed to find session", uid, err)
+		mr.logger.Error("failed to find session", "uid", uid, "error", err)
 		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(err)
 	}
-	return &domain.Session{
-		UID:     result.UID,
-		UserUID: result.UserUID,
-	}, nil
+	return sessionFromDB(&result), nil
 }
 
 func (mr *Repo) SessionCreate(ctx context.Context, sess *domain.Session) error {
 	sessionDb := sessionDB{
-		UID:     sess.UID,
-		UserUID: sess.UserUID,
+		UID:              sess.UID,
+		UserUID:          sess.UserUID,
+		GameUID:          sess.GameUID,
+		ProviderUID:      sess.ProviderUID,
+		Currency:         sess.Currency,
+		MaxWin:           sess.MaxWin,
+		Status:           sess.Status,
+		CreatedAt:        sess.CreatedAt,
+		LastActivityAt:   sess.LastActivityAt,
+		ExpiresAt:        sess.ExpiresAt,
+		TotalBet:         sess.TotalBet,
+		TotalWin:         sess.TotalWin,
+		RealityCheckedAt: sess.RealityCheckedAt,
+		WalletCurrency:   sess.WalletCurrency,
 	}
 
 	_, err := mr.db.Collection(sessionTable).InsertOne(ctx, sessionDb)
@@ -48,9 +70,110 @@ func (mr *Repo) SessionCreate(ctx context.Context, sess *domain.Session) error {
 	return nil
 }
 
+// SessionClose closes the session unless it's already closed
+func (mr *Repo) SessionClose(ctx context.Context, uid string, now time.Time) (*domain.Session, error) {
+	var result sessionDB
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := mr.db.Collection(sessionTable).FindOneAndUpdate(
+		ctx,
+		bson.M{"uid": uid, "status": bson.M{"$ne": domain.SessionStatusClosed}},
+		bson.M{"$set": bson.M{"status": domain.SessionStatusClosed, "lastActivityAt": now}},
+		opts,
+	).Decode(&result)
+	if err != nil {
+		mr.logger.Error("failed to close session", "uid", uid, "error", err)
+		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrSessionClose).Add(err)
+	}
+	return sessionFromDB(&result), nil
+}
+
+// SessionTouch registers activity in the session and moves the expiry of the active session to expiresAt,
+// the session without expiry and the one already expired keep it
+func (mr *Repo) SessionTouch(ctx context.Context, uid string, now, expiresAt time.Time) error {
+	_, err := mr.db.Collection(sessionTable).UpdateOne(ctx, bson.M{"uid": uid}, bson.M{"$set": bson.M{"lastActivityAt": now}})
+	if err == nil && !expiresAt.IsZero() {
+		_, err = mr.db.Collection(sessionTable).UpdateOne(ctx,
+			bson.M{"uid": uid, "status": domain.SessionStatusOpen, "expiresAt": bson.M{"$gt": now}},
+			bson.M{"$set": bson.M{"expiresAt": expiresAt}},
+		)
+	}
+	if err != nil {
+		mr.logger.Error("failed to touch session", "uid", uid, "error", err)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return nil
+}
+
+// SessionRealityChecked registers the moment the user confirmed the reality check of the session
+func (mr *Repo) SessionRealityChecked(ctx context.Context, uid string, now time.Time) error {
+	res, err := mr.db.Collection(sessionTable).UpdateOne(ctx, bson.M{"uid": uid}, bson.M{"$set": bson.M{"realityCheckedAt": now}})
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		mr.logger.Error("failed to check reality of session", "uid", uid, "error", err)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return nil
+}
+
+// SessionExpire marks open sessions which expiry moment has passed as expired, returns number of expired sessions
+func (mr *Repo) SessionExpire(ctx context.Context, now time.Time) (int, error) {
+	res, err := mr.db.Collection(sessionTable).UpdateMany(
+		ctx,
+		bson.M{
+			"status":    domain.SessionStatusOpen,
+			"expiresAt": bson.M{"$lte": now, "$gt": time.Time{}},
+		},
+		bson.M{"$set": bson.M{"status": domain.SessionStatusExpired}},
+	)
+	if err != nil {
+		mr.logger.Error("failed to expire sessions", "error", err)
+		return 0, domain.NewError(sessionErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return int(res.ModifiedCount), nil
+}
+
+// sessionApply adds the transaction to the bets and wins of its session, rollback takes back the original transaction
+// from the session of the original, must be called within db transaction which moves the money
+func (mr *Repo) sessionApply(ctx context.Context, transactionDb, originalDb *transactionDB) error {
+	var original *domain.Transaction
+	uid := transactionDb.SessionUID
+	if originalDb != nil {
+		original, uid = transactionFromDB(originalDb), originalDb.SessionUID
+	}
+	bet, win := domain.SessionDelta(transactionFromDB(transactionDb), original)
+	if bet == 0 && win == 0 {
+		return nil
+	}
+
+	_, err := mr.db.Collection(sessionTable).UpdateOne(ctx, bson.M{"uid": uid}, bson.M{"$inc": bson.M{"totalBet": bet, "totalWin": win}})
+	return err
+}
+
+func sessionFromDB(s *sessionDB) *domain.Session {
+	return &domain.Session{
+		UID:              s.UID,
+		UserUID:          s.UserUID,
+		GameUID:          s.GameUID,
+		ProviderUID:      s.ProviderUID,
+		Currency:         s.Currency,
+		MaxWin:           s.MaxWin,
+		Status:           s.Status,
+		CreatedAt:        s.CreatedAt,
+		LastActivityAt:   s.LastActivityAt,
+		ExpiresAt:        s.ExpiresAt,
+		TotalBet:         s.TotalBet,
+		TotalWin:         s.TotalWin,
+		RealityCheckedAt: s.RealityCheckedAt,
+		WalletCurrency:   s.WalletCurrency,
+	}
+}
+
 func (mr *Repo) sessionEnsureIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
-		{Keys: bson.D{{"uid", -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: -1}, {Key: "expiresAt", Value: -1}}},
 	}
 	_, err := mr.db.Collection(sessionTable).Indexes().CreateMany(ctx, indexes)
 	if err != nil {
@@ -2,10 +2,12 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"open-api-games/internal/domain"
+	"time"
 )
 
 const (
@@ -14,46 +16,68 @@ const (
 
 	// errors prefix
 	transactionErrorSource = "[repository.mongodb.transaction]"
+
+	// legacyProviderTransactionIndex is the name of the provider transaction id index made before it was scoped by provider
+	legacyProviderTransactionIndex = "providerTransactionUid_-1_type_-1"
+	// indexNotFoundCode is the server error code of dropping the index which doesn't exist
+	indexNotFoundCode = 27
 )
 
 type transactionDB struct {
-	UID          string                 `bson:"uid"`
-	UserUID      string                 `bson:"userUid"`
-	SessionUID   string                 `bson:"sessionUid"`
-	Amount       int                    `bson:"amount"`
-	Currency     string                 `bson:"currency"`
-	Denomination int                    `bson:"denomination"`
-	Type         domain.TransactionType `bson:"type"`
+	UID                    string                   `bson:"uid"`
+	ProviderUID            string                   `bson:"providerUid"`
+	ProviderTransactionUID string                   `bson:"providerTransactionUid,omitempty"`
+	UserUID                string                   `bson:"userUid"`
+	SessionUID             string                   `bson:"sessionUid"`
+	RoundUID               string                   `bson:"roundUid,omitempty"`
+	Amount                 int                      `bson:"amount"`
+	CappedAmount           int                      `bson:"cappedAmount,omitempty"`
+	Currency               string                   `bson:"currency"`
+	Denomination           int                      `bson:"denomination"`
+	Type                   domain.TransactionType   `bson:"type"`
+	Status                 domain.TransactionStatus `bson:"status"`
+	ParentTransactionUID   string                   `bson:"parentTransactionUid,omitempty"`
+	RollbackTransactionUID string                   `bson:"rollbackTransactionUid,omitempty"`
+	OriginalAmount         int                      `bson:"originalAmount,omitempty"`
+	OriginalCurrency       string                   `bson:"originalCurrency,omitempty"`
+	ExchangeRate           float64                  `bson:"exchangeRate,omitempty"`
+	JackpotKey             string                   `bson:"jackpotKey,omitempty"`
+	JackpotContribution    int                      `bson:"jackpotContribution,omitempty"`
+	CampaignUID            string                   `bson:"campaignUid,omitempty"`
+	BalanceBefore          int                      `bson:"balanceBefore"`
+	Balance                int                      `bson:"balance"`
+	BonusAmount            int                      `bson:"bonusAmount,omitempty"`
+	BonusBalance           int                      `bson:"bonusBalance,omitempty"`
+	Wagering               int                      `bson:"wagering,omitempty"`
+	// BonusOrder and GamingLimits of the debit and deposit draft aren't stored
+	BonusOrder   domain.BonusOrder    `bson:"-"`
+	GamingLimits *domain.GamingLimits `bson:"-"`
+	Operator     string               `bson:"operator,omitempty"`
+	Reason       string               `bson:"reason,omitempty"`
+	CreatedAt    time.Time            `bson:"createdAt"`
+	Meta         transactionMetaDB    `bson:"meta"`
+}
+
+type transactionMetaDB struct {
+	SpinMeta     string `bson:"spinMeta,omitempty"`
+	BetMeta      string `bson:"betMeta,omitempty"`
+	JpKey        string `bson:"jpKey,omitempty"`
+	Denomination int    `bson:"denomination,omitempty"`
+	MaxWin       int    `bson:"maxWin,omitempty"`
 }
 
 func (mr *Repo) TransactionGetByUID(ctx context.Context, uid string) (*domain.Transaction, error) {
 	var result transactionDB
 	err := mr.db.Collection(transactionTable).FindOne(ctx, bson.M{"uid": uid}).Decode(&result)
 	if err != nil {
-		mr.logger.Error("failed to find transaction", uid, err)
+		mr.logger.Error("failed to find transaction", "uid", uid, "error", err)
 		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
 	}
-	return &domain.Transaction{
-		UID:          result.UID,
-		UserUID:      result.UserUID,
-		SessionUID:   result.SessionUID,
-		Amount:       result.Amount,
-		Currency:     result.Currency,
-		Denomination: result.Denomination,
-		Type:         result.Type,
-	}, nil
+	return transactionFromDB(&result), nil
 }
 
 func (mr *Repo) TransactionCreate(ctx context.Context, transaction *domain.Transaction) error {
-	transactionDb := transactionDB{
-		UID:          transaction.UID,
-		UserUID:      transaction.UserUID,
-		SessionUID:   transaction.SessionUID,
-		Amount:       transaction.Amount,
-		Currency:     transaction.Currency,
-		Denomination: transaction.Denomination,
-		Type:         transaction.Type,
-	}
+	transactionDb := transactionToDB(transaction)
 
 	_, err := mr.db.Collection(transactionTable).InsertOne(ctx, transactionDb)
 	if err != nil {
@@ -63,13 +87,524 @@ func (mr *Repo) TransactionCreate(ctx context.Context, transaction *domain.Trans
 	return nil
 }
 
+// TransactionRollback compensates the transaction and marks it as rolled back in one db transaction,
+// repeated rollback returns the rollback transaction created by the first one
+func (mr *Repo) TransactionRollback(ctx context.Context, uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	var rollbackDb *transactionDB
+
+	// use transaction to avoid race condition
+	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			return err
+		}
+
+		rollbackDb, err = mr.transactionRollbackTx(sessionContext, uid, meta)
+		if err != nil {
+			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
+				return errAbort
+			}
+			return err
+		}
+
+		return sessionContext.CommitTransaction(sessionContext)
+	})
+	if err != nil {
+		mr.logger.Error("failed to rollback transaction", "uid", uid, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+
+	return transactionFromDB(rollbackDb), nil
+}
+
+// transactionRollbackTx is TransactionRollback within the started db transaction
+func (mr *Repo) transactionRollbackTx(sessionContext mongo.SessionContext, uid string, meta domain.TransactionMeta) (*transactionDB, error) {
+	var originalDb transactionDB
+	err := mr.db.Collection(transactionTable).FindOne(sessionContext, bson.M{"uid": uid}).Decode(&originalDb)
+	if err != nil {
+		return nil, err
+	}
+
+	if originalDb.Status == domain.TransactionStatusRolledBack {
+		var rollbackDb transactionDB
+		err = mr.db.Collection(transactionTable).FindOne(sessionContext, bson.M{"uid": originalDb.RollbackTransactionUID}).Decode(&rollbackDb)
+		if err != nil {
+			return nil, err
+		}
+		return &rollbackDb, nil
+	}
+
+	// rollback moves money in the opposite direction of the original transaction
+	var filter bson.M
+	var delta int
+	switch originalDb.Type {
+	case domain.TransactionTypeDebit:
+		filter = bson.M{"userUid": originalDb.UserUID, "currency": originalDb.Currency}
+		delta = originalDb.Amount
+	case domain.TransactionTypeCredit:
+		filter = bson.M{"userUid": originalDb.UserUID, "currency": originalDb.Currency, "amount": bson.M{"$gte": originalDb.Amount}}
+		delta = -originalDb.Amount
+	default:
+		return nil, errors.New("transaction type can't be rolled back")
+	}
+
+	// rollback is keyed by the original transaction, provider transaction id could be shared by its debit and credit
+	rollbackDb := &transactionDB{
+		UID:                  domain.GenUID(),
+		ProviderUID:          originalDb.ProviderUID,
+		UserUID:              originalDb.UserUID,
+		SessionUID:           originalDb.SessionUID,
+		RoundUID:             originalDb.RoundUID,
+		Amount:               originalDb.Amount,
+		Currency:             originalDb.Currency,
+		Denomination:         originalDb.Denomination,
+		Type:                 domain.TransactionTypeRollback,
+		Status:               domain.TransactionStatusCommitted,
+		ParentTransactionUID: originalDb.UID,
+		CreatedAt:            time.Now(),
+		Meta:                 transactionMetaToDB(meta),
+	}
+	err = mr.balanceBook(sessionContext, filter, delta, rollbackDb, &originalDb)
+	if err != nil {
+		return nil, err
+	}
+	_, err = mr.db.Collection(transactionTable).InsertOne(sessionContext, rollbackDb)
+	if err != nil {
+		return nil, err
+	}
+
+	betDelta, bonusBetDelta, winDelta := 0, 0, 0
+	if originalDb.Type == domain.TransactionTypeDebit {
+		betDelta, bonusBetDelta = -originalDb.Amount, -originalDb.BonusAmount
+	} else {
+		winDelta = -originalDb.Amount
+	}
+	err = mr.roundApply(sessionContext, rollbackDb, betDelta, bonusBetDelta, winDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.jackpotRevert(sessionContext, &originalDb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.campaignRevert(sessionContext, &originalDb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.gamingCounterApply(sessionContext, rollbackDb, &originalDb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.sessionApply(sessionContext, rollbackDb, &originalDb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.outboxAdd(sessionContext, rollbackDb)
+	if err != nil {
+		return nil, err
+	}
+
+	// status condition protects from concurrent rollback of the same transaction
+	res, err := mr.db.Collection(transactionTable).UpdateOne(
+		sessionContext,
+		bson.M{"uid": originalDb.UID, "status": bson.M{"$ne": domain.TransactionStatusRolledBack}},
+		bson.M{"$set": bson.M{"status": domain.TransactionStatusRolledBack, "rollbackTransactionUid": rollbackDb.UID}},
+	)
+	if err == nil && res.ModifiedCount == 0 {
+		err = errors.New("transaction already rolled back")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rollbackDb, nil
+}
+
+// TransactionGetByProviderUID finds the transaction of the type by the game provider transaction id within the provider
+func (mr *Repo) TransactionGetByProviderUID(ctx context.Context, providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error) {
+	result, err := mr.transactionFindByProviderUID(ctx, providerUID, transactionUID, txnType)
+	if err != nil {
+		mr.logger.Error("failed to find transaction", "provider", providerUID, "providerTransactionUid", transactionUID, "type", txnType, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return transactionFromDB(result), nil
+}
+
+// TransactionCreateTombstone records rollback of the provider transaction we have never seen,
+// so the late original transaction can't move money, repeated call returns the existing tombstone
+func (mr *Repo) TransactionCreateTombstone(ctx context.Context, tombstone *domain.Transaction) (*domain.Transaction, error) {
+	var tombstoneDb *transactionDB
+
+	// use transaction to avoid race condition with the late original transaction
+	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			return err
+		}
+
+		tombstoneDb, err = mr.transactionTombstoneTx(sessionContext, tombstone)
+		if err != nil {
+			if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
+				return errAbort
+			}
+			return err
+		}
+
+		return sessionContext.CommitTransaction(sessionContext)
+	})
+	if err != nil {
+		mr.logger.Error("failed to create tombstone", "providerTransactionUid", tombstone.ProviderTransactionUID, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+
+	return transactionFromDB(tombstoneDb), nil
+}
+
+// transactionTombstoneTx is TransactionCreateTombstone within the started db transaction
+func (mr *Repo) transactionTombstoneTx(sessionContext mongo.SessionContext, tombstone *domain.Transaction) (*transactionDB, error) {
+	existing, err := mr.transactionFindByProviderUID(sessionContext, tombstone.ProviderUID, tombstone.ProviderTransactionUID, domain.TransactionTypeRollback)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	tombstoneDb := &transactionDB{
+		UID:                    domain.GenUID(),
+		ProviderUID:            tombstone.ProviderUID,
+		ProviderTransactionUID: tombstone.ProviderTransactionUID,
+		UserUID:                tombstone.UserUID,
+		SessionUID:             tombstone.SessionUID,
+		RoundUID:               tombstone.RoundUID,
+		Currency:               tombstone.Currency,
+		OriginalCurrency:       tombstone.OriginalCurrency,
+		ExchangeRate:           tombstone.ExchangeRate,
+		Type:                   domain.TransactionTypeRollback,
+		Status:                 domain.TransactionStatusTombstone,
+		CreatedAt:              time.Now(),
+		Meta:                   transactionMetaToDB(tombstone.Meta),
+	}
+
+	// balance is touched to conflict with concurrent transaction of the same user
+	var balanceDb balanceDB
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = mr.db.Collection(balanceTable).FindOneAndUpdate(
+		sessionContext,
+		bson.M{"userUid": tombstone.UserUID, "currency": tombstone.Currency},
+		bson.M{"$set": bson.M{"lastTransactionUid": tombstoneDb.UID}},
+		opts,
+	).Decode(&balanceDb)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstoneDb.Denomination = balanceDb.Denomination
+	tombstoneDb.BalanceBefore = balanceDb.Amount
+	tombstoneDb.Balance = balanceDb.Amount
+	tombstoneDb.BonusBalance = balanceDb.Bonus
+	_, err = mr.db.Collection(transactionTable).InsertOne(sessionContext, tombstoneDb)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.roundApply(sessionContext, tombstoneDb, 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return tombstoneDb, nil
+}
+
+// TransactionBatch stores debits, credits, rollbacks and tombstones of the drafts in one db transaction in their order, all or nothing,
+// replayed provider transaction returns the stored one if it matches the draft, the failed draft is reported by its index
+func (mr *Repo) TransactionBatch(ctx context.Context, drafts []*domain.Transaction) ([]*domain.Transaction, int, error) {
+	var results []*domain.Transaction
+	failed := -1
+
+	// use transaction to store all the drafts or none of them
+	err := mr.db.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			return err
+		}
+
+		results = make([]*domain.Transaction, 0, len(drafts))
+		for i, draft := range drafts {
+			transactionDb, err := mr.transactionBatchApply(sessionContext, draft)
+			if err != nil {
+				failed = i
+				if errAbort := sessionContext.AbortTransaction(sessionContext); errAbort != nil {
+					return errAbort
+				}
+				return err
+			}
+			results = append(results, transactionFromDB(transactionDb))
+		}
+
+		return sessionContext.CommitTransaction(sessionContext)
+	})
+	if err != nil && failed >= 0 {
+		mr.logger.Error("failed to store batch", "index", failed, "providerTransactionUid", drafts[failed].ProviderTransactionUID, "error", err)
+		return nil, failed, transactionBatchError(drafts[failed], err)
+	}
+	if err != nil {
+		mr.logger.Error("failed to store batch", "error", err)
+		return nil, failed, domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+
+	return results, failed, nil
+}
+
+// transactionBatchApply stores the draft of the batch by its type within the started db transaction
+func (mr *Repo) transactionBatchApply(sessionContext mongo.SessionContext, draft *domain.Transaction) (*transactionDB, error) {
+	switch draft.Type {
+	case domain.TransactionTypeDebit, domain.TransactionTypeCredit:
+		transactionDb := transactionToDB(draft)
+		filter, delta := balanceMoveFilter(transactionDb)
+		transactionDb, _, err := mr.balanceMoveTx(sessionContext, filter, delta, transactionDb)
+		if err != nil {
+			return nil, err
+		}
+		if !transactionReplayMatch(transactionDb, draft) {
+			return nil, errTransactionConflict
+		}
+		return transactionDb, nil
+	case domain.TransactionTypeRollback:
+		if draft.Status == domain.TransactionStatusTombstone {
+			return mr.transactionTombstoneTx(sessionContext, draft)
+		}
+		return mr.transactionRollbackTx(sessionContext, draft.ParentTransactionUID, draft.Meta)
+	}
+	return nil, errors.New("transaction type can't be batched")
+}
+
+// transactionBatchError wraps error of the batch draft with the code of the separate movement of its type
+func transactionBatchError(draft *domain.Transaction, err error) *domain.Error {
+	switch draft.Type {
+	case domain.TransactionTypeDebit:
+		return balanceMoveError(err, domain.ErrDecrement)
+	case domain.TransactionTypeCredit:
+		return balanceMoveError(err, domain.ErrIncrement)
+	}
+	return domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+}
+
+// transactionReplayMatch checks the stored provider transaction is the same movement as the draft
+func transactionReplayMatch(stored *transactionDB, draft *domain.Transaction) bool {
+	txn := transactionFromDB(stored)
+	return txn.UserUID == draft.UserUID &&
+		txn.RequestedAmount() == draft.RequestedAmount() &&
+		txn.RequestedCurrency() == draft.RequestedCurrency()
+}
+
+// TransactionList searches transactions by the filter, newest first
+func (mr *Repo) TransactionList(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	query := transactionFilterToDB(filter)
+
+	total, err := mr.db.Collection(transactionTable).CountDocuments(ctx, query)
+	if err != nil {
+		mr.logger.Error("failed to count transactions", "filter", query, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "uid", Value: -1}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := mr.db.Collection(transactionTable).Find(ctx, query, opts)
+	if err != nil {
+		mr.logger.Error("failed to list transactions", "filter", query, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []transactionDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode transactions", "filter", query, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	transactions := make([]domain.Transaction, 0, len(result))
+	for i := range result {
+		transactions = append(transactions, *transactionFromDB(&result[i]))
+	}
+	return &domain.TransactionPage{Transactions: transactions, Total: int(total)}, nil
+}
+
+func transactionFilterToDB(filter domain.TransactionFilter) bson.M {
+	query := bson.M{}
+	if filter.UserUID != "" {
+		query["userUid"] = filter.UserUID
+	}
+	if filter.SessionUID != "" {
+		query["sessionUid"] = filter.SessionUID
+	}
+	if filter.RoundUID != "" {
+		query["roundUid"] = filter.RoundUID
+	}
+	if filter.ProviderTransactionUID != "" {
+		query["providerTransactionUid"] = filter.ProviderTransactionUID
+	}
+	if filter.Currency != "" {
+		query["currency"] = filter.Currency
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+	return query
+}
+
+// transactionFindByProviderUID looks up already processed transaction of the provider of the given type
+func (mr *Repo) transactionFindByProviderUID(ctx context.Context, providerUID, transactionUID string, txnType domain.TransactionType) (*transactionDB, error) {
+	var result transactionDB
+	query := bson.M{"providerUid": providerUID, "providerTransactionUid": transactionUID, "type": txnType}
+	err := mr.db.Collection(transactionTable).FindOne(ctx, query).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func transactionToDB(t *domain.Transaction) *transactionDB {
+	return &transactionDB{
+		UID:                    t.UID,
+		ProviderUID:            t.ProviderUID,
+		ProviderTransactionUID: t.ProviderTransactionUID,
+		UserUID:                t.UserUID,
+		SessionUID:             t.SessionUID,
+		RoundUID:               t.RoundUID,
+		Amount:                 t.Amount,
+		CappedAmount:           t.CappedAmount,
+		Currency:               t.Currency,
+		Denomination:           t.Denomination,
+		Type:                   t.Type,
+		Status:                 t.Status,
+		ParentTransactionUID:   t.ParentTransactionUID,
+		RollbackTransactionUID: t.RollbackTransactionUID,
+		OriginalAmount:         t.OriginalAmount,
+		OriginalCurrency:       t.OriginalCurrency,
+		ExchangeRate:           t.ExchangeRate,
+		JackpotKey:             t.JackpotKey,
+		JackpotContribution:    t.JackpotContribution,
+		CampaignUID:            t.CampaignUID,
+		BalanceBefore:          t.BalanceBefore,
+		Balance:                t.Balance,
+		BonusAmount:            t.BonusAmount,
+		BonusBalance:           t.BonusBalance,
+		Wagering:               t.Wagering,
+		BonusOrder:             t.BonusOrder,
+		GamingLimits:           t.GamingLimits,
+		Operator:               t.Operator,
+		Reason:                 t.Reason,
+		CreatedAt:              t.CreatedAt,
+		Meta:                   transactionMetaToDB(t.Meta),
+	}
+}
+
+func transactionFromDB(t *transactionDB) *domain.Transaction {
+	return &domain.Transaction{
+		UID:                    t.UID,
+		ProviderUID:            t.ProviderUID,
+		ProviderTransactionUID: t.ProviderTransactionUID,
+		UserUID:                t.UserUID,
+		SessionUID:             t.SessionUID,
+		RoundUID:               t.RoundUID,
+		Amount:                 t.Amount,
+		CappedAmount:           t.CappedAmount,
+		Currency:               t.Currency,
+		Denomination:           t.Denomination,
+		Type:                   t.Type,
+		Status:                 t.Status,
+		ParentTransactionUID:   t.ParentTransactionUID,
+		RollbackTransactionUID: t.RollbackTransactionUID,
+		OriginalAmount:         t.OriginalAmount,
+		OriginalCurrency:       t.OriginalCurrency,
+		ExchangeRate:           t.ExchangeRate,
+		JackpotKey:             t.JackpotKey,
+		JackpotContribution:    t.JackpotContribution,
+		CampaignUID:            t.CampaignUID,
+		BalanceBefore:          t.BalanceBefore,
+		Balance:                t.Balance,
+		BonusAmount:            t.BonusAmount,
+		BonusBalance:           t.BonusBalance,
+		Wagering:               t.Wagering,
+		Operator:               t.Operator,
+		Reason:                 t.Reason,
+		CreatedAt:              t.CreatedAt,
+		Meta:                   transactionMetaFromDB(t.Meta),
+	}
+}
+
+func transactionMetaToDB(meta domain.TransactionMeta) transactionMetaDB {
+	return transactionMetaDB{
+		SpinMeta:     meta.SpinMeta,
+		BetMeta:      meta.BetMeta,
+		JpKey:        meta.JpKey,
+		Denomination: meta.Denomination,
+		MaxWin:       meta.MaxWin,
+	}
+}
+
+func transactionMetaFromDB(meta transactionMetaDB) domain.TransactionMeta {
+	return domain.TransactionMeta{
+		SpinMeta:     meta.SpinMeta,
+		BetMeta:      meta.BetMeta,
+		JpKey:        meta.JpKey,
+		Denomination: meta.Denomination,
+		MaxWin:       meta.MaxWin,
+	}
+}
+
 func (mr *Repo) transactionEnsureIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
-		{Keys: bson.D{{"uid", -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "sessionUid", Value: -1}}},
+		{Keys: bson.D{{Key: "roundUid", Value: -1}}},
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "userUid", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{
+			// provider transaction id is unique per provider and transaction type, so retried requests can't move money twice
+			Keys: bson.D{{Key: "providerUid", Value: -1}, {Key: "providerTransactionUid", Value: -1}, {Key: "type", Value: -1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"providerTransactionUid": bson.M{"$exists": true}}),
+		},
+		{
+			// transaction is compensated by one rollback at most
+			Keys: bson.D{{Key: "parentTransactionUid", Value: -1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"parentTransactionUid": bson.M{"$exists": true}}),
+		},
 	}
 	_, err := mr.db.Collection(transactionTable).Indexes().CreateMany(ctx, indexes)
 	if err != nil {
 		return domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoInit).Add(err)
 	}
+
+	// the index unique across providers is replaced by the one above
+	_, err = mr.db.Collection(transactionTable).Indexes().DropOne(ctx, legacyProviderTransactionIndex)
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+		return domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
 	return nil
 }
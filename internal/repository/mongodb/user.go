@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"open-api-games/internal/domain"
+	"time"
 )
 
 const (
@@ -17,28 +18,29 @@ const (
 )
 
 type userDB struct {
-	UID  string `bson:"uid"`
-	Nick string `bson:"nick"`
+	UID               string                         `bson:"uid"`
+	Nick              string                         `bson:"nick"`
+	MaxWin            map[string]int                 `bson:"maxWin,omitempty"`
+	Limits            map[string]domain.GamingLimits `bson:"limits,omitempty"`
+	SelfExcludedUntil time.Time                      `bson:"selfExcludedUntil,omitempty"`
+	CoolOffUntil      time.Time                      `bson:"coolOffUntil,omitempty"`
+	// SessionLimit and RealityCheckInterval are stored as nanoseconds
+	SessionLimit         time.Duration `bson:"sessionLimit,omitempty"`
+	RealityCheckInterval time.Duration `bson:"realityCheckInterval,omitempty"`
 }
 
 func (mr *Repo) UserGetByUID(ctx context.Context, uid string) (*domain.User, error) {
 	var result userDB
 	err := mr.db.Collection(userTable).FindOne(ctx, bson.M{"uid": uid}).Decode(&result)
 	if err != nil {
-		mr.logger.Error("failed to find user", uid, err)
+		mr.logger.Error("failed to find user", "uid", uid, "error", err)
 		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
 	}
-	return &domain.User{
-		UID:  result.UID,
-		Nick: result.Nick,
-	}, nil
+	return userFromDB(&result), nil
 }
 
 func (mr *Repo) UserCreate(ctx context.Context, user *domain.User) error {
-	userDb := userDB{
-		UID:  user.UID,
-		Nick: user.Nick,
-	}
+	userDb := userToDB(user)
 
 	_, err := mr.db.Collection(userTable).InsertOne(ctx, userDb)
 	if err != nil {
@@ -48,9 +50,102 @@ func (mr *Repo) UserCreate(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+func (mr *Repo) UserUpdate(ctx context.Context, user *domain.User) error {
+	res, err := mr.db.Collection(userTable).UpdateOne(
+		ctx,
+		bson.M{"uid": user.UID},
+		bson.M{"$set": bson.M{
+			"nick":                 user.Nick,
+			"maxWin":               user.MaxWin,
+			"limits":               user.Limits,
+			"selfExcludedUntil":    user.SelfExcludedUntil,
+			"coolOffUntil":         user.CoolOffUntil,
+			"sessionLimit":         user.SessionLimit,
+			"realityCheckInterval": user.RealityCheckInterval,
+		}},
+	)
+	if err != nil {
+		mr.logger.Error("failed to update user", "uid", user.UID, "error", err)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (mr *Repo) UserDelete(ctx context.Context, uid string) error {
+	res, err := mr.db.Collection(userTable).DeleteOne(ctx, bson.M{"uid": uid})
+	if err != nil {
+		mr.logger.Error("failed to delete user", "uid", uid, "error", err)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+// UserList returns the page of users ordered by uid
+func (mr *Repo) UserList(ctx context.Context, page domain.Pagination) (*domain.UserPage, error) {
+	total, err := mr.db.Collection(userTable).CountDocuments(ctx, bson.M{})
+	if err != nil {
+		mr.logger.Error("failed to count users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "uid", Value: 1}}).
+		SetSkip(int64(page.Offset)).
+		SetLimit(int64(page.Limit))
+	cursor, err := mr.db.Collection(userTable).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		mr.logger.Error("failed to list users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	var result []userDB
+	if err = cursor.All(ctx, &result); err != nil {
+		mr.logger.Error("failed to decode users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	users := make([]domain.User, 0, len(result))
+	for _, u := range result {
+		users = append(users, *userFromDB(&u))
+	}
+	return &domain.UserPage{Users: users, Total: int(total)}, nil
+}
+
+func userToDB(u *domain.User) *userDB {
+	return &userDB{
+		UID:                  u.UID,
+		Nick:                 u.Nick,
+		MaxWin:               u.MaxWin,
+		Limits:               u.Limits,
+		SelfExcludedUntil:    u.SelfExcludedUntil,
+		CoolOffUntil:         u.CoolOffUntil,
+		SessionLimit:         u.SessionLimit,
+		RealityCheckInterval: u.RealityCheckInterval,
+	}
+}
+
+func userFromDB(u *userDB) *domain.User {
+	return &domain.User{
+		UID:                  u.UID,
+		Nick:                 u.Nick,
+		MaxWin:               u.MaxWin,
+		Limits:               u.Limits,
+		SelfExcludedUntil:    u.SelfExcludedUntil,
+		CoolOffUntil:         u.CoolOffUntil,
+		SessionLimit:         u.SessionLimit,
+		RealityCheckInterval: u.RealityCheckInterval,
+	}
+}
+
 func (mr *Repo) userEnsureIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
-		{Keys: bson.D{{"uid", -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "uid", Value: -1}}, Options: options.Index().SetUnique(true)},
 	}
 	_, err := mr.db.Collection(userTable).Indexes().CreateMany(ctx, indexes)
 	if err != nil {
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	balanceErrorSource = "[repository.postgres.balance]"
+
+	balanceColumns = "user_uid, amount, bonus, wagering, currency, denomination"
+)
+
+var (
+	// errTransactionRolledBack signals that provider transaction arrived after its rollback
+	errTransactionRolledBack = errors.New("provider transaction already rolled back")
+	// errTransactionConflict signals that replayed provider transaction doesn't match the stored one
+	errTransactionConflict = errors.New("provider transaction conflicts with the stored one")
+)
+
+func (pr *Repo) BalanceGetByUserUIDAndCurrency(ctx context.Context, userUID, currency string) (*domain.Balance, error) {
+	var balance domain.Balance
+	err := pr.pool.QueryRow(ctx,
+		"SELECT "+balanceColumns+" FROM balances WHERE user_uid = $1 AND currency = $2",
+		userUID, currency,
+	).Scan(balanceFields(&balance)...)
+	if err != nil {
+		pr.logger.Error("failed to find balance", "userUid", userUID, "currency", currency, "error", err)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return &balance, nil
+}
+
+func (pr *Repo) BalanceCreate(ctx context.Context, balance *domain.Balance) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO balances ("+balanceColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		balance.UserUID, balance.Amount, balance.Bonus, balance.Wagering, balance.Currency, balance.Denomination,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create balance", "userUid", balance.UserUID, "currency", balance.Currency, "error", err)
+		return domain.NewError(balanceErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+func (pr *Repo) BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error) {
+	rows, err := pr.pool.Query(ctx,
+		"SELECT "+balanceColumns+" FROM balances WHERE user_uid = $1 ORDER BY currency",
+		userUID,
+	)
+	if err != nil {
+		pr.logger.Error("failed to list balances", "userUid", userUID, "error", err)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	balances := make([]domain.Balance, 0)
+	for rows.Next() {
+		var balance domain.Balance
+		if err = rows.Scan(balanceFields(&balance)...); err != nil {
+			return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		balances = append(balances, balance)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read balances", "userUid", userUID, "error", err)
+		return nil, domain.NewError(balanceErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return balances, nil
+}
+
+// BalanceAdjust applies manual correction of user balance, the balance can't become negative
+func (pr *Repo) BalanceAdjust(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeAdjustment
+
+	required := 0
+	if txn.Amount < 0 {
+		required = -txn.Amount
+	}
+
+	result, err := pr.balanceMove(ctx, &draft, txn.Amount, required)
+	if err != nil {
+		pr.logger.Error("failed to adjust balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrAdjustment)
+	}
+	return result, nil
+}
+
+// BalanceDecrementByUserUIDAndCurrency debits user balance, replayed provider transaction returns the original one
+func (pr *Repo) BalanceDecrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeDebit
+
+	result, err := pr.balanceMove(ctx, &draft, -txn.Amount, txn.Amount)
+	if err != nil {
+		pr.logger.Error("failed to decrement balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrDecrement)
+	}
+	return result, nil
+}
+
+// BalanceIncrementByUserUIDAndCurrency credits user balance, replayed provider transaction returns the original one
+func (pr *Repo) BalanceIncrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	draft := *txn
+	draft.Type = domain.TransactionTypeCredit
+
+	result, err := pr.balanceMove(ctx, &draft, txn.Amount, 0)
+	if err != nil {
+		pr.logger.Error("failed to increment balance", "userUid", txn.UserUID, "currency", txn.Currency, "amount", txn.Amount, "error", err)
+		return nil, balanceMoveError(err, domain.ErrIncrement)
+	}
+	return result, nil
+}
+
+// balanceMoveError wraps error of balance movement keeping business error codes
+func balanceMoveError(err error, code string) *domain.Error {
+	switch {
+	case errors.Is(err, errTransactionRolledBack):
+		code = domain.ErrTransactionRolledBack
+	case errors.Is(err, errRoundClosed):
+		code = domain.ErrRoundClosed
+	case errors.Is(err, errRoundUser):
+		code = domain.ErrRoundUser
+	case errors.Is(err, errJackpotPayout):
+		code = domain.ErrJackpotPayout
+	case errors.Is(err, errTransactionConflict):
+		code = domain.ErrTransactionConflict
+	case errors.Is(err, errCampaignUnavailable):
+		code = domain.ErrCampaignUnavailable
+	case errors.Is(err, errGamingLimitExceeded):
+		code = domain.ErrGamingLimitExceeded
+	case errors.Is(err, errDepositLimitExceeded):
+		code = domain.ErrDepositLimitExceeded
+	}
+	return domain.NewError(balanceErrorSource).SetCode(code).Add(err)
+}
+
+// balanceMove applies delta to the balance holding at least required amount and stores the transaction in one db transaction,
+// the balance row is locked with SELECT ... FOR UPDATE, so concurrent movements of the same balance are serialized,
+// if the provider transaction was already processed the stored transaction is returned without moving money
+func (pr *Repo) balanceMove(ctx context.Context, txn *domain.Transaction, delta, required int) (*domain.Transaction, error) {
+	var result *domain.Transaction
+
+	err := pgx.BeginFunc(ctx, pr.pool, func(tx pgx.Tx) error {
+		var err error
+		result, err = balanceMoveTx(ctx, tx, txn, delta, required)
+		return err
+	})
+	if err != nil && txn.ProviderTransactionUID != "" && isUniqueViolation(err) {
+		// concurrent retry of the same provider transaction could win the race, return its result
+		existing, errFind := transactionFindByProviderUID(ctx, pr.pool, txn.ProviderUID, txn.ProviderTransactionUID, txn.Type)
+		if errFind == nil {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// balanceMoveTx is balanceMove within the given db transaction
+func balanceMoveTx(ctx context.Context, q querier, txn *domain.Transaction, delta, required int) (*domain.Transaction, error) {
+	if txn.ProviderTransactionUID != "" {
+		replayed, err := transactionFindByProviderUID(ctx, q, txn.ProviderUID, txn.ProviderTransactionUID, txn.Type)
+		if err == nil {
+			return replayed, nil
+		}
+		if !isNoRows(err) {
+			return nil, err
+		}
+
+		// rollback could come before the transaction itself, late transaction must not move money
+		_, err = transactionFindByProviderUID(ctx, q, txn.ProviderUID, txn.ProviderTransactionUID, domain.TransactionTypeRollback)
+		if err == nil {
+			return nil, errTransactionRolledBack
+		}
+		if !isNoRows(err) {
+			return nil, err
+		}
+	}
+
+	balance, err := balanceLock(ctx, q, txn.UserUID, txn.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Amount < required {
+		return nil, errInsufficientFunds
+	}
+	if err = gamingLimitCheck(ctx, q, txn); err != nil {
+		return nil, err
+	}
+	round, err := roundFind(ctx, q, txn.ProviderUID, txn.RoundUID)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.UID = domain.GenUID()
+	txn.Denomination = balance.Denomination
+	txn.Status = domain.TransactionStatusCommitted
+	txn.CreatedAt = time.Now()
+	balance.Book(txn, delta, nil, round)
+
+	if err = balanceUpdate(ctx, q, balance, txn.UID); err != nil {
+		return nil, err
+	}
+
+	if err = transactionInsert(ctx, q, txn); err != nil {
+		return nil, err
+	}
+
+	betDelta, bonusBetDelta, winDelta := 0, 0, 0
+	switch txn.Type {
+	case domain.TransactionTypeDebit:
+		betDelta, bonusBetDelta = txn.Amount, txn.BonusAmount
+	case domain.TransactionTypeCredit:
+		winDelta = txn.Amount
+	}
+	if err = roundApply(ctx, q, txn, betDelta, bonusBetDelta, winDelta); err != nil {
+		return nil, err
+	}
+
+	if err = jackpotApply(ctx, q, txn); err != nil {
+		return nil, err
+	}
+
+	if err = campaignApply(ctx, q, txn); err != nil {
+		return nil, err
+	}
+
+	if err = gamingCounterApply(ctx, q, txn, nil); err != nil {
+		return nil, err
+	}
+
+	if err = sessionApply(ctx, q, txn, nil); err != nil {
+		return nil, err
+	}
+
+	if err = outboxAdd(ctx, q, txn); err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+// balanceLock reads the balance locking its row till the end of the db transaction
+func balanceLock(ctx context.Context, q querier, userUID, currency string) (*domain.Balance, error) {
+	var balance domain.Balance
+	err := q.QueryRow(ctx,
+		"SELECT "+balanceColumns+" FROM balances WHERE user_uid = $1 AND currency = $2 FOR UPDATE",
+		userUID, currency,
+	).Scan(balanceFields(&balance)...)
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// balanceUpdate stores the moved balance and the transaction which moved it
+func balanceUpdate(ctx context.Context, q querier, balance *domain.Balance, txnUID string) error {
+	_, err := q.Exec(ctx,
+		"UPDATE balances SET amount = $3, bonus = $4, wagering = $5, last_transaction_uid = $6 WHERE user_uid = $1 AND currency = $2",
+		balance.UserUID, balance.Currency, balance.Amount, balance.Bonus, balance.Wagering, txnUID,
+	)
+	return err
+}
+
+// balanceFields returns the scan destinations of balanceColumns
+func balanceFields(b *domain.Balance) []any {
+	return []any{&b.UserUID, &b.Amount, &b.Bonus, &b.Wagering, &b.Currency, &b.Denomination}
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"strings"
+	"time"
+)
+
+const (
+	// errors prefix
+	campaignErrorSource = "[repository.postgres.campaign]"
+
+	campaignColumns = "uid, user_uid, game_uid, currency, rounds, rounds_left, bet_value, win, wagering_multiplier, " +
+		"status, operator, created_at, expires_at"
+)
+
+// errCampaignUnavailable signals that the campaign can't pay the free round
+var errCampaignUnavailable = errors.New("campaign not found, not active or has no rounds left")
+
+func (pr *Repo) CampaignGetByUID(ctx context.Context, uid string) (*domain.Campaign, error) {
+	campaign, err := campaignScan(pr.pool.QueryRow(ctx, "SELECT "+campaignColumns+" FROM campaigns WHERE uid = $1", uid))
+	if err != nil {
+		pr.logger.Error("failed to find campaign", "uid", uid, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return campaign, nil
+}
+
+// CampaignList searches campaigns by the filter, newest first
+func (pr *Repo) CampaignList(ctx context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error) {
+	where, args := campaignFilterToSQL(filter)
+	rows, err := pr.pool.Query(ctx,
+		"SELECT "+campaignColumns+" FROM campaigns"+where+" ORDER BY created_at DESC, uid DESC",
+		args...,
+	)
+	if err != nil {
+		pr.logger.Error("failed to list campaigns", "filter", filter, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	campaigns := make([]domain.Campaign, 0)
+	for rows.Next() {
+		campaign, err := campaignScan(rows)
+		if err != nil {
+			return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		campaigns = append(campaigns, *campaign)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read campaigns", "filter", filter, "error", err)
+		return nil, domain.NewError(campaignErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return campaigns, nil
+}
+
+func (pr *Repo) CampaignCreate(ctx context.Context, campaign *domain.Campaign) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO campaigns ("+campaignColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		campaign.UID, campaign.UserUID, campaign.GameUID, campaign.Currency, campaign.Rounds, campaign.RoundsLeft, campaign.BetValue,
+		campaign.Win, campaign.WageringMultiplier, campaign.Status, campaign.Operator, campaign.CreatedAt, campaign.ExpiresAt,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create campaign", "uid", campaign.UID, "error", err)
+		return domain.NewError(campaignErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// CampaignCancel cancels the active campaign, its rounds left can't be played anymore
+func (pr *Repo) CampaignCancel(ctx context.Context, uid string) (*domain.Campaign, error) {
+	campaign, err := campaignScan(pr.pool.QueryRow(ctx,
+		"UPDATE campaigns SET status = $2 WHERE uid = $1 AND status = $3 RETURNING "+campaignColumns,
+		uid, domain.CampaignStatusCancelled, domain.CampaignStatusActive,
+	))
+	if err == nil {
+		return campaign, nil
+	}
+
+	code := domain.ErrRepoUpdate
+	if isNoRows(err) {
+		// tell missing campaign from the one which isn't active anymore
+		code = domain.ErrCampaignUnavailable
+		if _, errFind := pr.CampaignGetByUID(ctx, uid); errFind != nil {
+			code = domain.ErrNotFound
+		}
+	}
+	pr.logger.Error("failed to cancel campaign", "uid", uid, "error", err)
+	return nil, domain.NewError(campaignErrorSource).SetCode(code).Add(err)
+}
+
+// campaignApply takes the round of the free round debit from its campaign, the campaign is completed without rounds left,
+// must be called within db transaction which moves the money
+func campaignApply(ctx context.Context, q querier, txn *domain.Transaction) error {
+	if txn.CampaignUID == "" || txn.Type != domain.TransactionTypeDebit {
+		return nil
+	}
+
+	tag, err := q.Exec(ctx,
+		`UPDATE campaigns SET rounds_left = rounds_left - 1, status = CASE WHEN rounds_left = 1 THEN $4 ELSE status END
+		WHERE uid = $1 AND user_uid = $2 AND status = $3 AND rounds_left > 0 AND expires_at > $5`,
+		txn.CampaignUID, txn.UserUID, domain.CampaignStatusActive, domain.CampaignStatusCompleted, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errCampaignUnavailable
+	}
+	return nil
+}
+
+// campaignRevert returns the round of the rolled back free round debit to its campaign, completed campaign is active again,
+// must be called within db transaction which rolls back the money
+func campaignRevert(ctx context.Context, q querier, original *domain.Transaction) error {
+	if original.CampaignUID == "" || original.Type != domain.TransactionTypeDebit {
+		return nil
+	}
+
+	_, err := q.Exec(ctx,
+		`UPDATE campaigns SET rounds_left = rounds_left + 1, status = CASE WHEN status = $3 THEN $2 ELSE status END
+		WHERE uid = $1`,
+		original.CampaignUID, domain.CampaignStatusActive, domain.CampaignStatusCompleted,
+	)
+	return err
+}
+
+func campaignFilterToSQL(filter domain.CampaignFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserUID != "" {
+		add("user_uid = $%d", filter.UserUID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func campaignScan(row pgx.Row) (*domain.Campaign, error) {
+	var c domain.Campaign
+	err := row.Scan(
+		&c.UID, &c.UserUID, &c.GameUID, &c.Currency, &c.Rounds, &c.RoundsLeft, &c.BetValue, &c.Win, &c.WageringMultiplier,
+		&c.Status, &c.Operator, &c.CreatedAt, &c.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// errors prefix
+	currencyErrorSource = "[repository.postgres.currency]"
+)
+
+func (pr *Repo) CurrencyGetByCode(ctx context.Context, code string) (*domain.Currency, error) {
+	var cur domain.Currency
+	err := pr.pool.QueryRow(ctx, "SELECT code, denomination, max_win FROM currencies WHERE code = $1", code).
+		Scan(&cur.Code, &cur.Denomination, &cur.MaxWin)
+	if err != nil {
+		pr.logger.Error("failed to find currency", "code", code, "error", err)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return &cur, nil
+}
+
+func (pr *Repo) CurrencyCreate(ctx context.Context, cur *domain.Currency) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO currencies (code, denomination, max_win) VALUES ($1, $2, $3)",
+		cur.Code, cur.Denomination, cur.MaxWin,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create currency", "code", cur.Code, "error", err)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+func (pr *Repo) CurrencyUpdate(ctx context.Context, cur *domain.Currency) error {
+	tag, err := pr.pool.Exec(ctx,
+		"UPDATE currencies SET denomination = $2, max_win = $3 WHERE code = $1",
+		cur.Code, cur.Denomination, cur.MaxWin,
+	)
+	if err != nil {
+		pr.logger.Error("failed to update currency", "code", cur.Code, "error", err)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (pr *Repo) CurrencyDelete(ctx context.Context, code string) error {
+	tag, err := pr.pool.Exec(ctx, "DELETE FROM currencies WHERE code = $1", code)
+	if err != nil {
+		pr.logger.Error("failed to delete currency", "code", code, "error", err)
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (pr *Repo) CurrencyList(ctx context.Context) ([]domain.Currency, error) {
+	rows, err := pr.pool.Query(ctx, "SELECT code, denomination, max_win FROM currencies ORDER BY code")
+	if err != nil {
+		pr.logger.Error("failed to list currencies", "error", err)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	currencies := make([]domain.Currency, 0)
+	for rows.Next() {
+		var cur domain.Currency
+		if err = rows.Scan(&cur.Code, &cur.Denomination, &cur.MaxWin); err != nil {
+			return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		currencies = append(currencies, cur)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read currencies", "error", err)
+		return nil, domain.NewError(currencyErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return currencies, nil
+}
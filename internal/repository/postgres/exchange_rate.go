@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// errors prefix
+	exchangeRateErrorSource = "[repository.postgres.exchange_rate]"
+)
+
+func (pr *Repo) ExchangeRateGet(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := pr.pool.QueryRow(ctx,
+		"SELECT from_currency, to_currency, rate, updated_at FROM exchange_rates WHERE from_currency = $1 AND to_currency = $2",
+		from, to,
+	).Scan(&rate.From, &rate.To, &rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return &rate, nil
+}
+
+func (pr *Repo) ExchangeRateList(ctx context.Context) ([]domain.ExchangeRate, error) {
+	rows, err := pr.pool.Query(ctx, "SELECT from_currency, to_currency, rate, updated_at FROM exchange_rates ORDER BY from_currency, to_currency")
+	if err != nil {
+		pr.logger.Error("failed to list exchange rates", "error", err)
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	rates := make([]domain.ExchangeRate, 0)
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err = rows.Scan(&rate.From, &rate.To, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		rates = append(rates, rate)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read exchange rates", "error", err)
+		return nil, domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return rates, nil
+}
+
+// ExchangeRateSave creates the rate of the currency pair or replaces the existing one
+func (pr *Repo) ExchangeRateSave(ctx context.Context, rate *domain.ExchangeRate) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO exchange_rates (from_currency, to_currency, rate, updated_at) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (from_currency, to_currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at",
+		rate.From, rate.To, rate.Rate, rate.UpdatedAt,
+	)
+	if err != nil {
+		pr.logger.Error("failed to save exchange rate", "from", rate.From, "to", rate.To, "error", err)
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return nil
+}
+
+func (pr *Repo) ExchangeRateDelete(ctx context.Context, from, to string) error {
+	tag, err := pr.pool.Exec(ctx, "DELETE FROM exchange_rates WHERE from_currency = $1 AND to_currency = $2", from, to)
+	if err != nil {
+		pr.logger.Error("failed to delete exchange rate", "from", from, "to", to, "error", err)
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(exchangeRateErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	gamingCounterErrorSource = "[repository.postgres.gaming_counter]"
+)
+
+var (
+	// errGamingLimitExceeded signals that the debit takes the user over the responsible gaming limit
+	errGamingLimitExceeded = errors.New("responsible gaming limit exceeded")
+	// errDepositLimitExceeded signals that the deposit takes the user over the deposit limit
+	errDepositLimitExceeded = errors.New("deposit limit exceeded")
+)
+
+// GamingCounterList returns the counters of the user in the currency for the periods the moment belongs to,
+// the period without money movement has zero counter
+func (pr *Repo) GamingCounterList(ctx context.Context, userUID, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	counters, err := gamingCounterList(ctx, pr.pool, userUID, currency, now)
+	if err != nil {
+		pr.logger.Error("failed to find gaming counter", "userUid", userUID, "currency", currency, "error", err)
+		return nil, domain.NewError(gamingCounterErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return counters, nil
+}
+
+func gamingCounterList(ctx context.Context, q querier, userUID, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	counters := make([]domain.GamingCounter, 0, len(domain.LimitPeriods))
+	for _, period := range domain.LimitPeriods {
+		counter := domain.GamingCounter{UserUID: userUID, Currency: currency, Period: period, Start: period.Start(now)}
+		err := q.QueryRow(ctx,
+			"SELECT COALESCE(SUM(wager), 0), COALESCE(SUM(loss), 0), COALESCE(SUM(deposit), 0) FROM gaming_counters "+
+				"WHERE user_uid = $1 AND currency = $2 AND period = $3 AND period_start = $4",
+			userUID, currency, period, counter.Start,
+		).Scan(&counter.Wager, &counter.Loss, &counter.Deposit)
+		if err != nil {
+			return nil, err
+		}
+		counters = append(counters, counter)
+	}
+	return counters, nil
+}
+
+// gamingLimitCheck checks the debit or the deposit against the gaming limits of the draft, must be called within db transaction
+// holding the lock of the balance, so concurrent debits or deposits of the user in the currency can't take it over the limit together
+func gamingLimitCheck(ctx context.Context, q querier, txn *domain.Transaction) error {
+	if txn.GamingLimits == nil || (txn.Type != domain.TransactionTypeDebit && !txn.IsDeposit()) {
+		return nil
+	}
+	counters, err := gamingCounterList(ctx, q, txn.UserUID, txn.Currency, time.Now())
+	if err != nil {
+		return err
+	}
+	if txn.IsDeposit() {
+		if txn.GamingLimits.DepositExceeded(counters, txn.Amount) != nil {
+			return errDepositLimitExceeded
+		}
+		return nil
+	}
+	if txn.GamingLimits.Exceeded(counters, txn.Amount) != nil {
+		return errGamingLimitExceeded
+	}
+	return nil
+}
+
+// gamingCounterApply counts the transaction to the periods of its creation, rollback is counted to the periods
+// of the original transaction, must be called within db transaction which moves the money
+func gamingCounterApply(ctx context.Context, q querier, txn, original *domain.Transaction) error {
+	wager, loss := domain.GamingDelta(txn, original)
+	deposit := domain.DepositDelta(txn)
+	if wager == 0 && loss == 0 && deposit == 0 {
+		return nil
+	}
+
+	at := txn.CreatedAt
+	if original != nil {
+		at = original.CreatedAt
+	}
+	for _, period := range domain.LimitPeriods {
+		_, err := q.Exec(ctx,
+			`INSERT INTO gaming_counters (user_uid, currency, period, period_start, wager, loss, deposit) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_uid, currency, period, period_start)
+			DO UPDATE SET wager = gaming_counters.wager + EXCLUDED.wager, loss = gaming_counters.loss + EXCLUDED.loss,
+				deposit = gaming_counters.deposit + EXCLUDED.deposit`,
+			txn.UserUID, txn.Currency, period, period.Start(at), wager, loss, deposit,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
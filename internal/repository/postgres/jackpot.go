@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// errors prefix
+	jackpotErrorSource = "[repository.postgres.jackpot]"
+
+	jackpotColumns = "key, currency, name, game_uid, amount, contribution_percent, is_active"
+)
+
+// errJackpotPayout signals that jackpot pool can't cover the payout
+var errJackpotPayout = errors.New("jackpot pool not found or insufficient")
+
+func (pr *Repo) JackpotGetByKey(ctx context.Context, key, currency string) (*domain.Jackpot, error) {
+	jackpot, err := jackpotScan(pr.pool.QueryRow(ctx,
+		"SELECT "+jackpotColumns+" FROM jackpots WHERE key = $1 AND currency = $2",
+		key, currency,
+	))
+	if err != nil {
+		pr.logger.Error("failed to find jackpot", "key", key, "currency", currency, "error", err)
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return jackpot, nil
+}
+
+// JackpotListActiveByGame returns active pools of the game in the currency including shared ones
+func (pr *Repo) JackpotListActiveByGame(ctx context.Context, gameUID, currency string) ([]domain.Jackpot, error) {
+	rows, err := pr.pool.Query(ctx,
+		`SELECT `+jackpotColumns+` FROM jackpots
+		WHERE currency = $1 AND is_active AND game_uid IN ($2, '')
+		ORDER BY game_uid DESC, key`,
+		currency, gameUID,
+	)
+	if err != nil {
+		pr.logger.Error("failed to find jackpots", "gameUid", gameUID, "currency", currency, "error", err)
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	jackpots := make([]domain.Jackpot, 0)
+	for rows.Next() {
+		jackpot, err := jackpotScan(rows)
+		if err != nil {
+			return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		jackpots = append(jackpots, *jackpot)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, domain.NewError(jackpotErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return jackpots, nil
+}
+
+func (pr *Repo) JackpotCreate(ctx context.Context, jackpot *domain.Jackpot) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO jackpots ("+jackpotColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		jackpot.Key, jackpot.Currency, jackpot.Name, jackpot.GameUID, jackpot.Amount, jackpot.ContributionPercent, jackpot.IsActive,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create jackpot", "key", jackpot.Key, "currency", jackpot.Currency, "error", err)
+		return domain.NewError(jackpotErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// jackpotApply moves jackpot contribution of the debit into the pool or jackpot payout of the credit out of it,
+// must be called within db transaction which moves the money
+func jackpotApply(ctx context.Context, q querier, txn *domain.Transaction) error {
+	if txn.JackpotKey == "" {
+		return nil
+	}
+
+	var sql string
+	var delta int
+	switch txn.Type {
+	case domain.TransactionTypeDebit:
+		sql = "UPDATE jackpots SET amount = amount + $3 WHERE key = $1 AND currency = $2"
+		delta = txn.JackpotContribution
+	case domain.TransactionTypeCredit:
+		sql = "UPDATE jackpots SET amount = amount - $3 WHERE key = $1 AND currency = $2 AND amount >= $3"
+		delta = txn.Amount
+	default:
+		return nil
+	}
+
+	tag, err := q.Exec(ctx, sql, txn.JackpotKey, txn.Currency, delta)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errJackpotPayout
+	}
+	return nil
+}
+
+// jackpotRevert returns jackpot contribution or payout of the rolled back transaction,
+// must be called within db transaction which rolls back the money
+func jackpotRevert(ctx context.Context, q querier, original *domain.Transaction) error {
+	if original.JackpotKey == "" {
+		return nil
+	}
+
+	var delta int
+	switch original.Type {
+	case domain.TransactionTypeDebit:
+		delta = -original.JackpotContribution
+	case domain.TransactionTypeCredit:
+		delta = original.Amount
+	default:
+		return nil
+	}
+
+	_, err := q.Exec(ctx,
+		"UPDATE jackpots SET amount = amount + $3 WHERE key = $1 AND currency = $2",
+		original.JackpotKey, original.Currency, delta,
+	)
+	return err
+}
+
+func jackpotScan(row pgx.Row) (*domain.Jackpot, error) {
+	var jackpot domain.Jackpot
+	err := row.Scan(
+		&jackpot.Key, &jackpot.Currency, &jackpot.Name, &jackpot.GameUID, &jackpot.Amount,
+		&jackpot.ContributionPercent, &jackpot.IsActive,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &jackpot, nil
+}
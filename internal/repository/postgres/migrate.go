@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"io/fs"
+	"open-api-games/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// migrationsLockID is the advisory lock serializing migrations of concurrently started instances
+	migrationsLockID = 7461930215
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Migrate applies embedded migrations which weren't applied yet, each one in its own db transaction
+func (pr *Repo) Migrate(ctx context.Context) error {
+	list, err := migrationList()
+	if err != nil {
+		return domain.NewError(postgresErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	conn, err := pr.pool.Acquire(ctx)
+	if err != nil {
+		return domain.NewError(postgresErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID)
+	if err != nil {
+		return domain.NewError(postgresErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationsLockID)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return domain.NewError(postgresErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+	}
+
+	for _, m := range list {
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			var applied bool
+			err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version).Scan(&applied)
+			if err != nil || applied {
+				return err
+			}
+
+			if _, err = tx.Exec(ctx, m.sql); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.version, m.name)
+			if err != nil {
+				return err
+			}
+
+			pr.logger.Info("migration applied", "version", m.version, "name", m.name)
+			return nil
+		})
+		if err != nil {
+			pr.logger.Error("failed to apply migration", "version", m.version, "name", m.name, "error", err)
+			return domain.NewError(postgresErrorSource).SetCode(domain.ErrRepoInit).Add(err)
+		}
+	}
+
+	return nil
+}
+
+// migrationList reads embedded migrations ordered by version, file name is "<version>_<name>.sql"
+func migrationList() ([]migration, error) {
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]migration, 0, len(files))
+	for _, file := range files {
+		base := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+		version, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name %q", file)
+		}
+		v, err := strconv.Atoi(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", file, err)
+		}
+		sql, err := migrations.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, migration{version: v, name: name, sql: string(sql)})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].version < list[j].version
+	})
+	return list, nil
+}
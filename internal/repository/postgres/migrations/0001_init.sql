@@ -0,0 +1,93 @@
+CREATE TABLE users (
+    uid     TEXT PRIMARY KEY,
+    nick    TEXT NOT NULL,
+    max_win JSONB
+);
+
+CREATE TABLE currencies (
+    code         TEXT PRIMARY KEY,
+    denomination INTEGER NOT NULL DEFAULT 0,
+    max_win      BIGINT  NOT NULL DEFAULT 0
+);
+
+CREATE TABLE balances (
+    user_uid             TEXT    NOT NULL,
+    currency             TEXT    NOT NULL,
+    amount               BIGINT  NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    denomination         INTEGER NOT NULL DEFAULT 0,
+    last_transaction_uid TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (user_uid, currency)
+);
+
+CREATE TABLE sessions (
+    uid              TEXT PRIMARY KEY,
+    user_uid         TEXT        NOT NULL,
+    game_uid         TEXT        NOT NULL DEFAULT '',
+    provider_uid     TEXT        NOT NULL DEFAULT '',
+    currency         TEXT        NOT NULL DEFAULT '',
+    max_win          BIGINT      NOT NULL DEFAULT 0,
+    status           TEXT        NOT NULL,
+    created_at       TIMESTAMPTZ,
+    last_activity_at TIMESTAMPTZ,
+    expires_at       TIMESTAMPTZ
+);
+
+CREATE INDEX sessions_status_expires_at_idx ON sessions (status, expires_at);
+
+CREATE TABLE transactions (
+    seq                      BIGSERIAL,
+    uid                      TEXT PRIMARY KEY,
+    provider_transaction_uid TEXT        NOT NULL DEFAULT '',
+    user_uid                 TEXT        NOT NULL,
+    session_uid              TEXT        NOT NULL DEFAULT '',
+    round_uid                TEXT        NOT NULL DEFAULT '',
+    amount                   BIGINT      NOT NULL,
+    capped_amount            BIGINT      NOT NULL DEFAULT 0,
+    currency                 TEXT        NOT NULL,
+    denomination             INTEGER     NOT NULL DEFAULT 0,
+    type                     TEXT        NOT NULL,
+    status                   TEXT        NOT NULL,
+    parent_transaction_uid   TEXT        NOT NULL DEFAULT '',
+    rollback_transaction_uid TEXT        NOT NULL DEFAULT '',
+    jackpot_key              TEXT        NOT NULL DEFAULT '',
+    jackpot_contribution     BIGINT      NOT NULL DEFAULT 0,
+    balance_before           BIGINT      NOT NULL DEFAULT 0,
+    balance                  BIGINT      NOT NULL DEFAULT 0,
+    operator                 TEXT        NOT NULL DEFAULT '',
+    reason                   TEXT        NOT NULL DEFAULT '',
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    meta                     JSONB       NOT NULL DEFAULT '{}'
+);
+
+-- provider transaction id is unique per transaction type, so retried requests can't move money twice
+CREATE UNIQUE INDEX transactions_provider_uid_type_idx ON transactions (provider_transaction_uid, type)
+    WHERE provider_transaction_uid <> '';
+CREATE INDEX transactions_session_uid_idx ON transactions (session_uid);
+CREATE INDEX transactions_round_uid_idx ON transactions (round_uid, seq);
+CREATE INDEX transactions_created_at_idx ON transactions (created_at DESC);
+CREATE INDEX transactions_user_uid_created_at_idx ON transactions (user_uid, created_at DESC);
+
+CREATE TABLE rounds (
+    uid         TEXT PRIMARY KEY,
+    session_uid TEXT   NOT NULL DEFAULT '',
+    user_uid    TEXT   NOT NULL,
+    currency    TEXT   NOT NULL,
+    status      TEXT   NOT NULL,
+    total_bet   BIGINT NOT NULL DEFAULT 0,
+    total_win   BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX rounds_session_uid_idx ON rounds (session_uid);
+
+CREATE TABLE jackpots (
+    key                  TEXT             NOT NULL,
+    currency             TEXT             NOT NULL,
+    name                 TEXT             NOT NULL DEFAULT '',
+    game_uid             TEXT             NOT NULL DEFAULT '',
+    amount               BIGINT           NOT NULL DEFAULT 0,
+    contribution_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    is_active            BOOLEAN          NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (key, currency)
+);
+
+CREATE INDEX jackpots_game_uid_currency_idx ON jackpots (game_uid, currency);
@@ -0,0 +1,8 @@
+CREATE TABLE providers (
+    uid         TEXT PRIMARY KEY,
+    name        TEXT    NOT NULL DEFAULT '',
+    secrets     TEXT[]  NOT NULL DEFAULT '{}',
+    allowed_ips TEXT[]  NOT NULL DEFAULT '{}',
+    enabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    currencies  TEXT[]  NOT NULL DEFAULT '{}'
+);
@@ -0,0 +1 @@
+ALTER TABLE providers ADD COLUMN sign_scheme TEXT NOT NULL DEFAULT '';
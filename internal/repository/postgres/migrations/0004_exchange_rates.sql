@@ -0,0 +1,11 @@
+CREATE TABLE exchange_rates (
+    from_currency TEXT             NOT NULL,
+    to_currency   TEXT             NOT NULL,
+    rate          DOUBLE PRECISION NOT NULL,
+    updated_at    TIMESTAMPTZ      NOT NULL DEFAULT now(),
+    PRIMARY KEY (from_currency, to_currency)
+);
+
+ALTER TABLE transactions ADD COLUMN original_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN original_currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN exchange_rate DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE providers ADD COLUMN amount_format TEXT NOT NULL DEFAULT '';
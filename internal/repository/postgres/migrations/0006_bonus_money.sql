@@ -0,0 +1,8 @@
+ALTER TABLE balances ADD COLUMN bonus BIGINT NOT NULL DEFAULT 0 CHECK (bonus >= 0 AND bonus <= amount);
+ALTER TABLE balances ADD COLUMN wagering BIGINT NOT NULL DEFAULT 0 CHECK (wagering >= 0);
+
+ALTER TABLE transactions ADD COLUMN bonus_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN bonus_balance BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN wagering BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE rounds ADD COLUMN total_bonus_bet BIGINT NOT NULL DEFAULT 0;
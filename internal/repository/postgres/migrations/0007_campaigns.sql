@@ -0,0 +1,19 @@
+CREATE TABLE campaigns (
+    uid                 TEXT PRIMARY KEY,
+    user_uid            TEXT        NOT NULL,
+    game_uid            TEXT        NOT NULL,
+    currency            TEXT        NOT NULL,
+    rounds              INTEGER     NOT NULL,
+    rounds_left         INTEGER     NOT NULL CHECK (rounds_left >= 0),
+    bet_value           BIGINT      NOT NULL,
+    win                 TEXT        NOT NULL,
+    wagering_multiplier INTEGER     NOT NULL DEFAULT 0,
+    status              TEXT        NOT NULL,
+    operator            TEXT        NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX campaigns_user_uid_created_at_idx ON campaigns (user_uid, created_at DESC);
+
+ALTER TABLE transactions ADD COLUMN campaign_uid TEXT NOT NULL DEFAULT '';
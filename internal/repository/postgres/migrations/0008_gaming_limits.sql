@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN limits JSONB;
+ALTER TABLE users ADD COLUMN self_excluded_until TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN cool_off_until TIMESTAMPTZ;
+
+CREATE TABLE gaming_counters (
+    user_uid     TEXT        NOT NULL,
+    currency     TEXT        NOT NULL,
+    period       TEXT        NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    wager        BIGINT      NOT NULL DEFAULT 0,
+    loss         BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_uid, currency, period, period_start)
+);
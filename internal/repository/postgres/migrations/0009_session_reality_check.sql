@@ -0,0 +1,6 @@
+ALTER TABLE sessions ADD COLUMN total_bet BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN total_win BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN reality_checked_at TIMESTAMPTZ;
+
+ALTER TABLE users ADD COLUMN session_limit_seconds BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN reality_check_interval_seconds BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+CREATE TABLE outbox (
+    seq             BIGSERIAL,
+    uid             TEXT PRIMARY KEY,
+    transaction     JSONB       NOT NULL,
+    status          TEXT        NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_status_next_attempt_at_idx ON outbox (status, next_attempt_at);
@@ -0,0 +1,7 @@
+-- rollbacks are keyed by the rolled back transaction, the provider transaction id stays with tombstones only,
+-- so the debit and the credit sharing it can be rolled back both
+UPDATE transactions SET provider_transaction_uid = ''
+WHERE type = 'rollback' AND status <> 'tombstone';
+
+CREATE UNIQUE INDEX transactions_parent_uid_idx ON transactions (parent_transaction_uid)
+    WHERE parent_transaction_uid <> '';
@@ -0,0 +1,11 @@
+-- provider transaction ids are unique within the provider only, transactions keep the provider they came from,
+-- the ones stored before are assigned the provider of their session
+ALTER TABLE transactions ADD COLUMN provider_uid TEXT NOT NULL DEFAULT '';
+
+UPDATE transactions t SET provider_uid = s.provider_uid
+FROM sessions s
+WHERE t.session_uid = s.uid AND t.session_uid <> '';
+
+DROP INDEX transactions_provider_uid_type_idx;
+CREATE UNIQUE INDEX transactions_provider_uid_type_idx ON transactions (provider_uid, provider_transaction_uid, type)
+    WHERE provider_transaction_uid <> '';
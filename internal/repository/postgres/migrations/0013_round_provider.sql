@@ -0,0 +1,13 @@
+-- bet ids are unique within the provider only, rounds keep the provider they came from,
+-- the ones opened before are assigned the provider of their session
+ALTER TABLE rounds ADD COLUMN provider_uid TEXT NOT NULL DEFAULT '';
+
+UPDATE rounds r SET provider_uid = s.provider_uid
+FROM sessions s
+WHERE r.session_uid = s.uid AND r.session_uid <> '';
+
+ALTER TABLE rounds DROP CONSTRAINT rounds_pkey;
+ALTER TABLE rounds ADD PRIMARY KEY (provider_uid, uid);
+
+DROP INDEX transactions_round_uid_idx;
+CREATE INDEX transactions_round_uid_idx ON transactions (provider_uid, round_uid, seq);
@@ -0,0 +1,5 @@
+-- sessions keep the currency of the balance they play from along with the game currency,
+-- the ones opened before played from the balance in the game currency
+ALTER TABLE sessions ADD COLUMN wallet_currency TEXT NOT NULL DEFAULT '';
+
+UPDATE sessions SET wallet_currency = currency;
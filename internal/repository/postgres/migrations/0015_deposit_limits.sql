@@ -0,0 +1,2 @@
+-- deposit limits cap the real money added by adjustments, the counters keep the deposits along with the bets
+ALTER TABLE gaming_counters ADD COLUMN deposit BIGINT NOT NULL DEFAULT 0;
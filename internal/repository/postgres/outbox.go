@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	outboxErrorSource = "[repository.postgres.outbox]"
+
+	outboxColumns = "uid, transaction, status, attempts, last_error, next_attempt_at, created_at, delivered_at"
+)
+
+// OutboxListPending returns up to limit pending events due at the moment in the order of their movements
+func (pr *Repo) OutboxListPending(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error) {
+	rows, err := pr.pool.Query(ctx,
+		"SELECT "+outboxColumns+" FROM outbox WHERE status = $1 AND next_attempt_at <= $2 ORDER BY seq LIMIT $3",
+		domain.OutboxStatusPending, now, limit,
+	)
+	if err != nil {
+		pr.logger.Error("failed to list outbox events", "error", err)
+		return nil, domain.NewError(outboxErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.OutboxEvent, 0)
+	for rows.Next() {
+		event, err := outboxScan(rows)
+		if err != nil {
+			return nil, domain.NewError(outboxErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+		}
+		events = append(events, *event)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read outbox events", "error", err)
+		return nil, domain.NewError(outboxErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return events, nil
+}
+
+// OutboxUpdate stores the delivery state of the event
+func (pr *Repo) OutboxUpdate(ctx context.Context, event *domain.OutboxEvent) error {
+	tag, err := pr.pool.Exec(ctx,
+		"UPDATE outbox SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, delivered_at = $6 WHERE uid = $1",
+		event.UID, event.Status, event.Attempts, event.LastError, event.NextAttemptAt, nullTime(event.DeliveredAt),
+	)
+	if err != nil {
+		pr.logger.Error("failed to update outbox event", "uid", event.UID, "error", err)
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(outboxErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+// outboxAdd stores the event of the wallet movement, must be called within db transaction which moves the money
+func outboxAdd(ctx context.Context, q querier, txn *domain.Transaction) error {
+	event := domain.NewOutboxEvent(txn)
+	_, err := q.Exec(ctx,
+		"INSERT INTO outbox ("+outboxColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		event.UID, event.Transaction, event.Status, event.Attempts, event.LastError, event.NextAttemptAt, event.CreatedAt,
+		nullTime(event.DeliveredAt),
+	)
+	return err
+}
+
+func outboxScan(row pgx.Row) (*domain.OutboxEvent, error) {
+	var event domain.OutboxEvent
+	var deliveredAt *time.Time
+	err := row.Scan(
+		&event.UID, &event.Transaction, &event.Status, &event.Attempts, &event.LastError, &event.NextAttemptAt,
+		&event.CreatedAt, &deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	event.DeliveredAt = timeOrZero(deliveredAt)
+	return &event, nil
+}
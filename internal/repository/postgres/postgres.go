@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"open-api-games/internal/config"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	postgresErrorSource = "[repository.postgres]"
+
+	// pgUniqueViolation is the postgres error code of unique constraint violation
+	pgUniqueViolation = "23505"
+)
+
+var (
+	// errInsufficientFunds signals that balance can't cover the movement
+	errInsufficientFunds = errors.New("insufficient funds")
+)
+
+// querier is implemented by both pool and db transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type Repo struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+func Connect(ctx context.Context, logger *slog.Logger) (*Repo, error) {
+	cfg := config.Get(logger)
+
+	if cfg.PostgresURI == "" {
+		logger.Error("missing postgres uri")
+		return nil, domain.NewError(postgresErrorSource).SetCode(domain.ErrConfig)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.PostgresURI)
+	if err != nil {
+		logger.Error("failed to parse postgres uri", "error", err)
+		return nil, domain.NewError(postgresErrorSource).SetCode(domain.ErrConfig).Add(err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		logger.Error("failed to connect to postgres", "error", err)
+		return nil, domain.NewError(postgresErrorSource).SetCode(domain.ErrConnect).Add(err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err = pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		logger.Error("failed to ping postgres", "error", err)
+		return nil, domain.NewError(postgresErrorSource).SetCode(domain.ErrConnect).Add(err)
+	}
+
+	return &Repo{
+		pool:   pool,
+		logger: logger,
+	}, nil
+}
+
+func (pr *Repo) Close(_ context.Context) error {
+	pr.pool.Close()
+	return nil
+}
+
+// EnsureIndexes applies schema migrations, indexes are part of them
+func (pr *Repo) EnsureIndexes(ctx context.Context) error {
+	return pr.Migrate(ctx)
+}
+
+// isUniqueViolation reports whether the error is caused by duplicate key
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// isNoRows reports whether the query found nothing
+func isNoRows(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows)
+}
+
+// nullTime stores zero time as NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// timeOrZero reads NULL time as zero time
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// seconds stores duration as whole seconds
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+// textArray stores nil slice as empty array
+func textArray(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// errors prefix
+	providerErrorSource = "[repository.postgres.provider]"
+
+	providerColumns = "uid, name, secrets, allowed_ips, enabled, currencies, sign_scheme, amount_format"
+)
+
+func (pr *Repo) ProviderGetByUID(ctx context.Context, uid string) (*domain.Provider, error) {
+	provider, err := providerScan(pr.pool.QueryRow(ctx, "SELECT "+providerColumns+" FROM providers WHERE uid = $1", uid))
+	if err != nil {
+		pr.logger.Error("failed to find provider", "uid", uid, "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return provider, nil
+}
+
+func (pr *Repo) ProviderCreate(ctx context.Context, provider *domain.Provider) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO providers ("+providerColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		provider.UID, provider.Name, textArray(provider.Secrets), textArray(provider.AllowedIPs), provider.Enabled, textArray(provider.Currencies), provider.SignScheme, provider.AmountFormat,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create provider", "uid", provider.UID, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+func (pr *Repo) ProviderUpdate(ctx context.Context, provider *domain.Provider) error {
+	tag, err := pr.pool.Exec(ctx,
+		"UPDATE providers SET name = $2, secrets = $3, allowed_ips = $4, enabled = $5, currencies = $6, sign_scheme = $7, amount_format = $8 WHERE uid = $1",
+		provider.UID, provider.Name, textArray(provider.Secrets), textArray(provider.AllowedIPs), provider.Enabled, textArray(provider.Currencies), provider.SignScheme, provider.AmountFormat,
+	)
+	if err != nil {
+		pr.logger.Error("failed to update provider", "uid", provider.UID, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (pr *Repo) ProviderDelete(ctx context.Context, uid string) error {
+	tag, err := pr.pool.Exec(ctx, "DELETE FROM providers WHERE uid = $1", uid)
+	if err != nil {
+		pr.logger.Error("failed to delete provider", "uid", uid, "error", err)
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (pr *Repo) ProviderList(ctx context.Context) ([]domain.Provider, error) {
+	rows, err := pr.pool.Query(ctx, "SELECT "+providerColumns+" FROM providers ORDER BY uid")
+	if err != nil {
+		pr.logger.Error("failed to list providers", "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	providers := make([]domain.Provider, 0)
+	for rows.Next() {
+		provider, err := providerScan(rows)
+		if err != nil {
+			return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		providers = append(providers, *provider)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read providers", "error", err)
+		return nil, domain.NewError(providerErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return providers, nil
+}
+
+func providerScan(row pgx.Row) (*domain.Provider, error) {
+	var provider domain.Provider
+	err := row.Scan(&provider.UID, &provider.Name, &provider.Secrets, &provider.AllowedIPs, &provider.Enabled, &provider.Currencies, &provider.SignScheme, &provider.AmountFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
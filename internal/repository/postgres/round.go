@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"open-api-games/internal/domain"
+)
+
+const (
+	// errors prefix
+	roundErrorSource = "[repository.postgres.round]"
+)
+
+var (
+	// errRoundClosed signals that money movement came to already completed round
+	errRoundClosed = errors.New("round already closed")
+	// errRoundUser signals that bet id is already used by the round of another user
+	errRoundUser = errors.New("round belongs to another user")
+	// errRoundChanged signals that the round got new transactions since it was read
+	errRoundChanged = errors.New("round changed or already closed")
+)
+
+func (pr *Repo) RoundGetByProviderUID(ctx context.Context, providerUID, uid string) (*domain.Round, error) {
+	round, err := roundGet(ctx, pr.pool, providerUID, uid)
+	if err != nil {
+		pr.logger.Error("failed to find round", "providerUid", providerUID, "uid", uid, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return round, nil
+}
+
+// RoundClose closes the round if it wasn't changed since it was read
+func (pr *Repo) RoundClose(ctx context.Context, round *domain.Round) (*domain.Round, error) {
+	tag, err := pr.pool.Exec(ctx,
+		`UPDATE rounds SET status = $3
+		WHERE provider_uid = $1 AND uid = $2 AND status = $4
+		AND (SELECT count(*) FROM transactions WHERE provider_uid = $1 AND round_uid = $2) = $5`,
+		round.ProviderUID, round.UID, domain.RoundStatusClosed, domain.RoundStatusOpen, len(round.Transactions),
+	)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = errRoundChanged
+	}
+	if err != nil {
+		pr.logger.Error("failed to close round", "uid", round.UID, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrRoundClose).Add(err)
+	}
+
+	closed, err := roundGet(ctx, pr.pool, round.ProviderUID, round.UID)
+	if err != nil {
+		pr.logger.Error("failed to find round", "uid", round.UID, "error", err)
+		return nil, domain.NewError(roundErrorSource).SetCode(domain.ErrRoundClose).Add(err)
+	}
+	return closed, nil
+}
+
+// roundApply adds transaction to its round and updates round totals, round is opened by the first transaction,
+// must be called within db transaction which moves the money after the transaction is stored
+func roundApply(ctx context.Context, q querier, txn *domain.Transaction, betDelta, bonusBetDelta, winDelta int) error {
+	if txn.RoundUID == "" {
+		return nil
+	}
+
+	_, err := q.Exec(ctx,
+		`INSERT INTO rounds (provider_uid, uid, session_uid, user_uid, currency, status) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (provider_uid, uid) DO NOTHING`,
+		txn.ProviderUID, txn.RoundUID, txn.SessionUID, txn.UserUID, txn.Currency, domain.RoundStatusOpen,
+	)
+	if err != nil {
+		return err
+	}
+
+	var userUID string
+	var status domain.RoundStatus
+	err = q.QueryRow(ctx,
+		"SELECT user_uid, status FROM rounds WHERE provider_uid = $1 AND uid = $2 FOR UPDATE",
+		txn.ProviderUID, txn.RoundUID,
+	).Scan(&userUID, &status)
+	switch {
+	case err != nil:
+		return err
+	case userUID != txn.UserUID:
+		return errRoundUser
+	case status == domain.RoundStatusClosed && txn.Type != domain.TransactionTypeRollback:
+		// rollbacks are still accepted to refund the player
+		return errRoundClosed
+	}
+
+	_, err = q.Exec(ctx,
+		`UPDATE rounds SET total_bet = total_bet + $3, total_bonus_bet = total_bonus_bet + $4, total_win = total_win + $5
+		WHERE provider_uid = $1 AND uid = $2`,
+		txn.ProviderUID, txn.RoundUID, betDelta, bonusBetDelta, winDelta,
+	)
+	return err
+}
+
+// roundFind reads the round totals the win is split by, nil is returned for the round which isn't opened yet
+func roundFind(ctx context.Context, q querier, providerUID, uid string) (*domain.Round, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	var round domain.Round
+	err := q.QueryRow(ctx,
+		"SELECT provider_uid, uid, total_bet, total_bonus_bet FROM rounds WHERE provider_uid = $1 AND uid = $2",
+		providerUID, uid,
+	).Scan(&round.ProviderUID, &round.UID, &round.TotalBet, &round.TotalBonusBet)
+	if isNoRows(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
+
+// roundGet reads the round with its transactions in the order they were made
+func roundGet(ctx context.Context, q querier, providerUID, uid string) (*domain.Round, error) {
+	var round domain.Round
+	err := q.QueryRow(ctx,
+		`SELECT provider_uid, uid, session_uid, user_uid, currency, status, total_bet, total_bonus_bet, total_win
+		FROM rounds WHERE provider_uid = $1 AND uid = $2`,
+		providerUID, uid,
+	).Scan(&round.ProviderUID, &round.UID, &round.SessionUID, &round.UserUID, &round.Currency, &round.Status, &round.TotalBet, &round.TotalBonusBet, &round.TotalWin)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx,
+		"SELECT uid, type, amount, parent_transaction_uid FROM transactions WHERE provider_uid = $1 AND round_uid = $2 ORDER BY seq",
+		providerUID, uid,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	round.Transactions = make([]domain.RoundTransaction, 0)
+	for rows.Next() {
+		var txn domain.RoundTransaction
+		if err = rows.Scan(&txn.UID, &txn.Type, &txn.Amount, &txn.ParentTransactionUID); err != nil {
+			return nil, err
+		}
+		round.Transactions = append(round.Transactions, txn)
+	}
+	return &round, rows.Err()
+}
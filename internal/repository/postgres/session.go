@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	sessionErrorSource = "[repository.postgres.session]"
+
+	sessionColumns = "uid, user_uid, game_uid, provider_uid, currency, max_win, status, created_at, last_activity_at, expires_at, " +
+		"total_bet, total_win, reality_checked_at, wallet_currency"
+)
+
+func (pr *Repo) SessionGetByUID(ctx context.Context, uid string) (*domain.Session, error) {
+	session, err := sessionScan(pr.pool.QueryRow(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE uid = $1", uid))
+	if err != nil {
+		pr.logger.Error("failed to find session", "uid", uid, "error", err)
+		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return session, nil
+}
+
+func (pr *Repo) SessionCreate(ctx context.Context, sess *domain.Session) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO sessions ("+sessionColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)",
+		sess.UID, sess.UserUID, sess.GameUID, sess.ProviderUID, sess.Currency, sess.MaxWin, sess.Status,
+		nullTime(sess.CreatedAt), nullTime(sess.LastActivityAt), nullTime(sess.ExpiresAt),
+		sess.TotalBet, sess.TotalWin, nullTime(sess.RealityCheckedAt), sess.WalletCurrency,
+	)
+	if err != nil {
+		pr.logger.Error("failed to create session", "uid", sess.UID, "error", err)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// SessionClose closes the session unless it's already closed
+func (pr *Repo) SessionClose(ctx context.Context, uid string, now time.Time) (*domain.Session, error) {
+	session, err := sessionScan(pr.pool.QueryRow(ctx,
+		"UPDATE sessions SET status = $2, last_activity_at = $3 WHERE uid = $1 AND status <> $2 RETURNING "+sessionColumns,
+		uid, domain.SessionStatusClosed, now,
+	))
+	if err != nil {
+		pr.logger.Error("failed to close session", "uid", uid, "error", err)
+		return nil, domain.NewError(sessionErrorSource).SetCode(domain.ErrSessionClose).Add(err)
+	}
+	return session, nil
+}
+
+// SessionTouch registers activity in the session and moves the expiry of the active session to expiresAt,
+// the session without expiry and the one already expired keep it
+func (pr *Repo) SessionTouch(ctx context.Context, uid string, now, expiresAt time.Time) error {
+	_, err := pr.pool.Exec(ctx,
+		"UPDATE sessions SET last_activity_at = $2, "+
+			"expires_at = CASE WHEN $3::timestamptz IS NOT NULL AND status = $4 AND expires_at > $2 THEN $3 ELSE expires_at END "+
+			"WHERE uid = $1",
+		uid, now, nullTime(expiresAt), domain.SessionStatusOpen,
+	)
+	if err != nil {
+		pr.logger.Error("failed to touch session", "uid", uid, "error", err)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return nil
+}
+
+// SessionRealityChecked registers the moment the user confirmed the reality check of the session
+func (pr *Repo) SessionRealityChecked(ctx context.Context, uid string, now time.Time) error {
+	tag, err := pr.pool.Exec(ctx, "UPDATE sessions SET reality_checked_at = $2 WHERE uid = $1", uid, now)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		pr.logger.Error("failed to check reality of session", "uid", uid, "error", err)
+		return domain.NewError(sessionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return nil
+}
+
+// SessionExpire marks open sessions which expiry moment has passed as expired, returns number of expired sessions
+func (pr *Repo) SessionExpire(ctx context.Context, now time.Time) (int, error) {
+	tag, err := pr.pool.Exec(ctx,
+		"UPDATE sessions SET status = $1 WHERE status = $2 AND expires_at <= $3",
+		domain.SessionStatusExpired, domain.SessionStatusOpen, now,
+	)
+	if err != nil {
+		pr.logger.Error("failed to expire sessions", "error", err)
+		return 0, domain.NewError(sessionErrorSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func sessionScan(row pgx.Row) (*domain.Session, error) {
+	var session domain.Session
+	var createdAt, lastActivityAt, expiresAt, realityCheckedAt *time.Time
+	err := row.Scan(
+		&session.UID, &session.UserUID, &session.GameUID, &session.ProviderUID, &session.Currency, &session.MaxWin,
+		&session.Status, &createdAt, &lastActivityAt, &expiresAt, &session.TotalBet, &session.TotalWin, &realityCheckedAt,
+		&session.WalletCurrency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	session.CreatedAt = timeOrZero(createdAt)
+	session.LastActivityAt = timeOrZero(lastActivityAt)
+	session.ExpiresAt = timeOrZero(expiresAt)
+	session.RealityCheckedAt = timeOrZero(realityCheckedAt)
+	return &session, nil
+}
+
+// sessionApply adds the transaction to the bets and wins of its session, rollback takes back the original transaction
+// from the session of the original, must be called within db transaction which moves the money
+func sessionApply(ctx context.Context, q querier, txn, original *domain.Transaction) error {
+	bet, win := domain.SessionDelta(txn, original)
+	if bet == 0 && win == 0 {
+		return nil
+	}
+
+	uid := txn.SessionUID
+	if original != nil {
+		uid = original.SessionUID
+	}
+	_, err := q.Exec(ctx, "UPDATE sessions SET total_bet = total_bet + $2, total_win = total_win + $3 WHERE uid = $1", uid, bet, win)
+	return err
+}
@@ -0,0 +1,454 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"strings"
+	"time"
+)
+
+const (
+	// errors prefix
+	transactionErrorSource = "[repository.postgres.transaction]"
+
+	transactionColumns = "uid, provider_transaction_uid, user_uid, session_uid, round_uid, amount, capped_amount, currency, " +
+		"denomination, type, status, parent_transaction_uid, rollback_transaction_uid, jackpot_key, jackpot_contribution, " +
+		"balance_before, balance, operator, reason, created_at, meta, original_amount, original_currency, exchange_rate, " +
+		"bonus_amount, bonus_balance, wagering, campaign_uid, provider_uid"
+)
+
+// transactionMetaDB is the raw bet context stored as jsonb
+type transactionMetaDB struct {
+	SpinMeta     string `json:"spinMeta,omitempty"`
+	BetMeta      string `json:"betMeta,omitempty"`
+	JpKey        string `json:"jpKey,omitempty"`
+	Denomination int    `json:"denomination,omitempty"`
+	MaxWin       int    `json:"maxWin,omitempty"`
+}
+
+func (pr *Repo) TransactionGetByUID(ctx context.Context, uid string) (*domain.Transaction, error) {
+	txn, err := transactionScan(pr.pool.QueryRow(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE uid = $1", uid))
+	if err != nil {
+		pr.logger.Error("failed to find transaction", "uid", uid, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return txn, nil
+}
+
+func (pr *Repo) TransactionCreate(ctx context.Context, transaction *domain.Transaction) error {
+	err := transactionInsert(ctx, pr.pool, transaction)
+	if err != nil {
+		pr.logger.Error("failed to create transaction", "uid", transaction.UID, "error", err)
+		return domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+// TransactionRollback compensates the transaction and marks it as rolled back in one db transaction,
+// repeated rollback returns the rollback transaction created by the first one
+func (pr *Repo) TransactionRollback(ctx context.Context, uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	var rollback *domain.Transaction
+
+	err := pgx.BeginFunc(ctx, pr.pool, func(tx pgx.Tx) error {
+		var err error
+		rollback, err = transactionRollbackTx(ctx, tx, uid, meta)
+		return err
+	})
+	if err != nil {
+		pr.logger.Error("failed to rollback transaction", "uid", uid, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+
+	return rollback, nil
+}
+
+// transactionRollbackTx is TransactionRollback within the given db transaction
+func transactionRollbackTx(ctx context.Context, q querier, uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	// row lock protects from concurrent rollback of the same transaction
+	original, err := transactionScan(q.QueryRow(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE uid = $1 FOR UPDATE", uid))
+	if err != nil {
+		return nil, err
+	}
+
+	if original.IsRolledBack() {
+		return transactionScan(q.QueryRow(ctx,
+			"SELECT "+transactionColumns+" FROM transactions WHERE uid = $1",
+			original.RollbackTransactionUID,
+		))
+	}
+
+	balance, err := balanceLock(ctx, q, original.UserUID, original.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// rollback moves money in the opposite direction of the original transaction
+	var delta, betDelta, bonusBetDelta, winDelta int
+	switch original.Type {
+	case domain.TransactionTypeDebit:
+		delta, betDelta, bonusBetDelta = original.Amount, -original.Amount, -original.BonusAmount
+	case domain.TransactionTypeCredit:
+		if balance.Amount < original.Amount {
+			return nil, errInsufficientFunds
+		}
+		delta, winDelta = -original.Amount, -original.Amount
+	default:
+		return nil, errors.New("transaction type can't be rolled back")
+	}
+
+	// rollback is keyed by the original transaction, provider transaction id could be shared by its debit and credit
+	rollback := &domain.Transaction{
+		UID:                  domain.GenUID(),
+		ProviderUID:          original.ProviderUID,
+		UserUID:              original.UserUID,
+		SessionUID:           original.SessionUID,
+		RoundUID:             original.RoundUID,
+		Amount:               original.Amount,
+		Currency:             original.Currency,
+		Denomination:         original.Denomination,
+		Type:                 domain.TransactionTypeRollback,
+		Status:               domain.TransactionStatusCommitted,
+		ParentTransactionUID: original.UID,
+		CreatedAt:            time.Now(),
+		Meta:                 meta,
+	}
+	balance.Book(rollback, delta, original, nil)
+
+	if err = balanceUpdate(ctx, q, balance, rollback.UID); err != nil {
+		return nil, err
+	}
+
+	if err = transactionInsert(ctx, q, rollback); err != nil {
+		return nil, err
+	}
+
+	if err = roundApply(ctx, q, rollback, betDelta, bonusBetDelta, winDelta); err != nil {
+		return nil, err
+	}
+
+	if err = jackpotRevert(ctx, q, original); err != nil {
+		return nil, err
+	}
+
+	if err = campaignRevert(ctx, q, original); err != nil {
+		return nil, err
+	}
+
+	if err = gamingCounterApply(ctx, q, rollback, original); err != nil {
+		return nil, err
+	}
+
+	if err = sessionApply(ctx, q, rollback, original); err != nil {
+		return nil, err
+	}
+
+	if err = outboxAdd(ctx, q, rollback); err != nil {
+		return nil, err
+	}
+
+	_, err = q.Exec(ctx,
+		"UPDATE transactions SET status = $2, rollback_transaction_uid = $3 WHERE uid = $1",
+		original.UID, domain.TransactionStatusRolledBack, rollback.UID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rollback, nil
+}
+
+// TransactionGetByProviderUID finds the transaction of the type by the game provider transaction id within the provider
+func (pr *Repo) TransactionGetByProviderUID(ctx context.Context, providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error) {
+	txn, err := transactionFindByProviderUID(ctx, pr.pool, providerUID, transactionUID, txnType)
+	if err != nil {
+		pr.logger.Error("failed to find transaction", "provider", providerUID, "providerTransactionUid", transactionUID, "type", txnType, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return txn, nil
+}
+
+// TransactionCreateTombstone records rollback of the provider transaction we have never seen,
+// so the late original transaction can't move money, repeated call returns the existing tombstone
+func (pr *Repo) TransactionCreateTombstone(ctx context.Context, tombstone *domain.Transaction) (*domain.Transaction, error) {
+	var result *domain.Transaction
+
+	err := pgx.BeginFunc(ctx, pr.pool, func(tx pgx.Tx) error {
+		var err error
+		result, err = transactionTombstoneTx(ctx, tx, tombstone)
+		return err
+	})
+	if err != nil && isUniqueViolation(err) {
+		existing, errFind := transactionFindByProviderUID(ctx, pr.pool, tombstone.ProviderUID, tombstone.ProviderTransactionUID, domain.TransactionTypeRollback)
+		if errFind == nil {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		pr.logger.Error("failed to create tombstone", "providerTransactionUid", tombstone.ProviderTransactionUID, "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+	}
+
+	return result, nil
+}
+
+// transactionTombstoneTx is TransactionCreateTombstone within the given db transaction
+func transactionTombstoneTx(ctx context.Context, q querier, tombstone *domain.Transaction) (*domain.Transaction, error) {
+	existing, err := transactionFindByProviderUID(ctx, q, tombstone.ProviderUID, tombstone.ProviderTransactionUID, domain.TransactionTypeRollback)
+	if err == nil {
+		return existing, nil
+	}
+	if !isNoRows(err) {
+		return nil, err
+	}
+
+	// balance is locked to serialize with concurrent transaction of the same user
+	balance, err := balanceLock(ctx, q, tombstone.UserUID, tombstone.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.Transaction{
+		UID:                    domain.GenUID(),
+		ProviderUID:            tombstone.ProviderUID,
+		ProviderTransactionUID: tombstone.ProviderTransactionUID,
+		UserUID:                tombstone.UserUID,
+		SessionUID:             tombstone.SessionUID,
+		RoundUID:               tombstone.RoundUID,
+		Currency:               tombstone.Currency,
+		OriginalCurrency:       tombstone.OriginalCurrency,
+		ExchangeRate:           tombstone.ExchangeRate,
+		Denomination:           balance.Denomination,
+		Type:                   domain.TransactionTypeRollback,
+		Status:                 domain.TransactionStatusTombstone,
+		BalanceBefore:          balance.Amount,
+		Balance:                balance.Amount,
+		BonusBalance:           balance.Bonus,
+		CreatedAt:              time.Now(),
+		Meta:                   tombstone.Meta,
+	}
+	if err = transactionInsert(ctx, q, result); err != nil {
+		return nil, err
+	}
+
+	if err = roundApply(ctx, q, result, 0, 0, 0); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TransactionBatch stores debits, credits, rollbacks and tombstones of the drafts in one db transaction in their order, all or nothing,
+// replayed provider transaction returns the stored one if it matches the draft, the failed draft is reported by its index
+func (pr *Repo) TransactionBatch(ctx context.Context, drafts []*domain.Transaction) ([]*domain.Transaction, int, error) {
+	results := make([]*domain.Transaction, 0, len(drafts))
+	failed := -1
+
+	err := pgx.BeginFunc(ctx, pr.pool, func(tx pgx.Tx) error {
+		for i, draft := range drafts {
+			txn, err := transactionBatchApply(ctx, tx, draft)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results = append(results, txn)
+		}
+		return nil
+	})
+	if err != nil && failed >= 0 {
+		pr.logger.Error("failed to store batch", "index", failed, "providerTransactionUid", drafts[failed].ProviderTransactionUID, "error", err)
+		return nil, failed, transactionBatchError(drafts[failed], err)
+	}
+	if err != nil {
+		pr.logger.Error("failed to store batch", "error", err)
+		return nil, failed, domain.NewError(transactionErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+
+	return results, failed, nil
+}
+
+// transactionBatchApply stores the draft of the batch by its type within the given db transaction
+func transactionBatchApply(ctx context.Context, q querier, draft *domain.Transaction) (*domain.Transaction, error) {
+	switch draft.Type {
+	case domain.TransactionTypeDebit, domain.TransactionTypeCredit:
+		delta, required := draft.Amount, 0
+		if draft.Type == domain.TransactionTypeDebit {
+			delta, required = -draft.Amount, draft.Amount
+		}
+		txn := *draft
+		result, err := balanceMoveTx(ctx, q, &txn, delta, required)
+		if err != nil {
+			return nil, err
+		}
+		if !transactionReplayMatch(result, draft) {
+			return nil, errTransactionConflict
+		}
+		return result, nil
+	case domain.TransactionTypeRollback:
+		if draft.Status == domain.TransactionStatusTombstone {
+			return transactionTombstoneTx(ctx, q, draft)
+		}
+		return transactionRollbackTx(ctx, q, draft.ParentTransactionUID, draft.Meta)
+	}
+	return nil, errors.New("transaction type can't be batched")
+}
+
+// transactionBatchError wraps error of the batch draft with the code of the separate movement of its type
+func transactionBatchError(draft *domain.Transaction, err error) *domain.Error {
+	switch draft.Type {
+	case domain.TransactionTypeDebit:
+		return balanceMoveError(err, domain.ErrDecrement)
+	case domain.TransactionTypeCredit:
+		return balanceMoveError(err, domain.ErrIncrement)
+	}
+	return domain.NewError(transactionErrorSource).SetCode(domain.ErrRollback).Add(err)
+}
+
+// transactionReplayMatch reports whether the stored transaction is the same movement as the draft
+func transactionReplayMatch(stored, draft *domain.Transaction) bool {
+	return stored.UserUID == draft.UserUID &&
+		stored.RequestedAmount() == draft.RequestedAmount() &&
+		stored.RequestedCurrency() == draft.RequestedCurrency()
+}
+
+// TransactionList searches transactions by the filter, newest first
+func (pr *Repo) TransactionList(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	where, args := transactionFilterToSQL(filter)
+
+	var total int
+	err := pr.pool.QueryRow(ctx, "SELECT count(*) FROM transactions"+where, args...).Scan(&total)
+	if err != nil {
+		pr.logger.Error("failed to count transactions", "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := pr.pool.Query(ctx,
+		fmt.Sprintf("SELECT %s FROM transactions%s ORDER BY created_at DESC, uid DESC LIMIT NULLIF($%d, 0) OFFSET $%d",
+			transactionColumns, where, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		pr.logger.Error("failed to list transactions", "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	transactions := make([]domain.Transaction, 0)
+	for rows.Next() {
+		txn, err := transactionScan(rows)
+		if err != nil {
+			return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		transactions = append(transactions, *txn)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read transactions", "error", err)
+		return nil, domain.NewError(transactionErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	return &domain.TransactionPage{Transactions: transactions, Total: total}, nil
+}
+
+func transactionFilterToSQL(filter domain.TransactionFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserUID != "" {
+		add("user_uid = $%d", filter.UserUID)
+	}
+	if filter.SessionUID != "" {
+		add("session_uid = $%d", filter.SessionUID)
+	}
+	if filter.RoundUID != "" {
+		add("round_uid = $%d", filter.RoundUID)
+	}
+	if filter.ProviderTransactionUID != "" {
+		add("provider_transaction_uid = $%d", filter.ProviderTransactionUID)
+	}
+	if filter.Currency != "" {
+		add("currency = $%d", filter.Currency)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// transactionFindByProviderUID looks up already processed transaction of the provider of the given type
+func transactionFindByProviderUID(ctx context.Context, q querier, providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error) {
+	return transactionScan(q.QueryRow(ctx,
+		"SELECT "+transactionColumns+" FROM transactions WHERE provider_uid = $1 AND provider_transaction_uid = $2 AND type = $3",
+		providerUID, transactionUID, txnType,
+	))
+}
+
+func transactionInsert(ctx context.Context, q querier, t *domain.Transaction) error {
+	createdAt := t.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err := q.Exec(ctx,
+		"INSERT INTO transactions ("+transactionColumns+") "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)",
+		t.UID, t.ProviderTransactionUID, t.UserUID, t.SessionUID, t.RoundUID, t.Amount, t.CappedAmount, t.Currency,
+		t.Denomination, t.Type, t.Status, t.ParentTransactionUID, t.RollbackTransactionUID, t.JackpotKey, t.JackpotContribution,
+		t.BalanceBefore, t.Balance, t.Operator, t.Reason, createdAt, transactionMetaToDB(t.Meta), t.OriginalAmount, t.OriginalCurrency, t.ExchangeRate,
+		t.BonusAmount, t.BonusBalance, t.Wagering, t.CampaignUID, t.ProviderUID,
+	)
+	return err
+}
+
+func transactionScan(row pgx.Row) (*domain.Transaction, error) {
+	var t domain.Transaction
+	var meta transactionMetaDB
+	err := row.Scan(
+		&t.UID, &t.ProviderTransactionUID, &t.UserUID, &t.SessionUID, &t.RoundUID, &t.Amount, &t.CappedAmount, &t.Currency,
+		&t.Denomination, &t.Type, &t.Status, &t.ParentTransactionUID, &t.RollbackTransactionUID, &t.JackpotKey, &t.JackpotContribution,
+		&t.BalanceBefore, &t.Balance, &t.Operator, &t.Reason, &t.CreatedAt, &meta, &t.OriginalAmount, &t.OriginalCurrency, &t.ExchangeRate,
+		&t.BonusAmount, &t.BonusBalance, &t.Wagering, &t.CampaignUID, &t.ProviderUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.Meta = transactionMetaFromDB(meta)
+	return &t, nil
+}
+
+func transactionMetaToDB(meta domain.TransactionMeta) transactionMetaDB {
+	return transactionMetaDB{
+		SpinMeta:     meta.SpinMeta,
+		BetMeta:      meta.BetMeta,
+		JpKey:        meta.JpKey,
+		Denomination: meta.Denomination,
+		MaxWin:       meta.MaxWin,
+	}
+}
+
+func transactionMetaFromDB(meta transactionMetaDB) domain.TransactionMeta {
+	return domain.TransactionMeta{
+		SpinMeta:     meta.SpinMeta,
+		BetMeta:      meta.BetMeta,
+		JpKey:        meta.JpKey,
+		Denomination: meta.Denomination,
+		MaxWin:       meta.MaxWin,
+	}
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	// errors prefix
+	userErrorSource = "[repository.postgres.user]"
+
+	userColumns = "uid, nick, max_win, limits, self_excluded_until, cool_off_until, session_limit_seconds, reality_check_interval_seconds"
+)
+
+func (pr *Repo) UserGetByUID(ctx context.Context, uid string) (*domain.User, error) {
+	user, err := userScan(pr.pool.QueryRow(ctx, "SELECT "+userColumns+" FROM users WHERE uid = $1", uid))
+	if err != nil {
+		pr.logger.Error("failed to find user", "uid", uid, "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	return user, nil
+}
+
+func (pr *Repo) UserCreate(ctx context.Context, user *domain.User) error {
+	_, err := pr.pool.Exec(ctx,
+		"INSERT INTO users ("+userColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		user.UID, user.Nick, user.MaxWin, user.Limits, nullTime(user.SelfExcludedUntil), nullTime(user.CoolOffUntil),
+		seconds(user.SessionLimit), seconds(user.RealityCheckInterval),
+	)
+	if err != nil {
+		pr.logger.Error("failed to create user", "uid", user.UID, "error", err)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return nil
+}
+
+func (pr *Repo) UserUpdate(ctx context.Context, user *domain.User) error {
+	tag, err := pr.pool.Exec(ctx,
+		"UPDATE users SET nick = $2, max_win = $3, limits = $4, self_excluded_until = $5, cool_off_until = $6, "+
+			"session_limit_seconds = $7, reality_check_interval_seconds = $8 WHERE uid = $1",
+		user.UID, user.Nick, user.MaxWin, user.Limits, nullTime(user.SelfExcludedUntil), nullTime(user.CoolOffUntil),
+		seconds(user.SessionLimit), seconds(user.RealityCheckInterval),
+	)
+	if err != nil {
+		pr.logger.Error("failed to update user", "uid", user.UID, "error", err)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (pr *Repo) UserDelete(ctx context.Context, uid string) error {
+	tag, err := pr.pool.Exec(ctx, "DELETE FROM users WHERE uid = $1", uid)
+	if err != nil {
+		pr.logger.Error("failed to delete user", "uid", uid, "error", err)
+		return domain.NewError(userErrorSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(userErrorSource).SetCode(domain.ErrNotFound)
+	}
+	return nil
+}
+
+// UserList returns the page of users ordered by uid
+func (pr *Repo) UserList(ctx context.Context, page domain.Pagination) (*domain.UserPage, error) {
+	var total int
+	err := pr.pool.QueryRow(ctx, "SELECT count(*) FROM users").Scan(&total)
+	if err != nil {
+		pr.logger.Error("failed to count users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	rows, err := pr.pool.Query(ctx, "SELECT "+userColumns+" FROM users ORDER BY uid LIMIT NULLIF($1, 0) OFFSET $2", page.Limit, page.Offset)
+	if err != nil {
+		pr.logger.Error("failed to list users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		user, err := userScan(rows)
+		if err != nil {
+			return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		users = append(users, *user)
+	}
+	if err = rows.Err(); err != nil {
+		pr.logger.Error("failed to read users", "error", err)
+		return nil, domain.NewError(userErrorSource).SetCode(domain.ErrNotFound).Add(err)
+	}
+
+	return &domain.UserPage{Users: users, Total: total}, nil
+}
+
+func userScan(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	var selfExcludedUntil, coolOffUntil *time.Time
+	var sessionLimit, realityCheckInterval int64
+	err := row.Scan(
+		&user.UID, &user.Nick, &user.MaxWin, &user.Limits, &selfExcludedUntil, &coolOffUntil,
+		&sessionLimit, &realityCheckInterval,
+	)
+	if err != nil {
+		return nil, err
+	}
+	user.SessionLimit = time.Duration(sessionLimit) * time.Second
+	user.RealityCheckInterval = time.Duration(realityCheckInterval) * time.Second
+	user.SelfExcludedUntil = timeOrZero(selfExcludedUntil)
+	user.CoolOffUntil = timeOrZero(coolOffUntil)
+	return &user, nil
+}
@@ -3,22 +3,70 @@ package repository
 import (
 	"context"
 	"log/slog"
+	"open-api-games/internal/config"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/repository/memory"
+	"open-api-games/internal/repository/mongodb"
+	"open-api-games/internal/repository/postgres"
+	"open-api-games/internal/service/admin"
 	"open-api-games/internal/service/game_processor"
+	"open-api-games/internal/service/outbox"
+	"open-api-games/internal/service/provider"
+	"open-api-games/internal/service/seed"
+	"open-api-games/internal/service/session"
+)
 
-	"open-api-games/internal/repository/mongodb"
+const (
+	repositoryErrorSource = "[repository]"
+
+	// backends selectable by config
+	BackendMongoDB  = "mongodb"
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
 )
 
+// Repo is the storage used by all the services
+type Repo interface {
+	game_processor.Repository
+	session.Repository
+	admin.Repository
+	provider.Repository
+	seed.Repository
+	outbox.Repository
+	EnsureIndexes(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
 var (
 	// test services interfaces
-	_ game_processor.Repository = (*mongodb.Repo)(nil)
+	_ Repo = (*mongodb.Repo)(nil)
+	_ Repo = (*postgres.Repo)(nil)
+	_ Repo = (*memory.Repo)(nil)
 )
 
-func NewRepo(ctx context.Context, logger *slog.Logger) (*mongodb.Repo, error) {
-	// connect and initialize db
-	dbr, err := mongodb.Connect(ctx, logger)
-	if err != nil {
-		return nil, err
-	}
+// NewRepo connects to the storage backend chosen by config
+func NewRepo(ctx context.Context, logger *slog.Logger) (Repo, error) {
+	cfg := config.Get(logger)
 
-	return dbr, nil
+	switch cfg.Repository {
+	case BackendMongoDB:
+		// connect and initialize db
+		dbr, err := mongodb.Connect(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		return dbr, nil
+	case BackendPostgres:
+		dbr, err := postgres.Connect(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		return dbr, nil
+	case BackendMemory:
+		logger.Warn("in-memory repository is used, data will be lost on restart")
+		return memory.New(logger), nil
+	default:
+		logger.Error("unknown repository backend", "repository", cfg.Repository)
+		return nil, domain.NewError(repositoryErrorSource).SetCode(domain.ErrConfig)
+	}
 }
@@ -0,0 +1,1276 @@
+// Package repotest is the contract test suite every repository backend must pass
+package repotest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/repository"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run checks repository semantics the services rely on, records use unique ids,
+// so the suite can run against shared database
+func Run(t *testing.T, repo repository.Repo) {
+	ctx := context.Background()
+
+	t.Run("user crud", func(t *testing.T) { testUser(ctx, t, repo) })
+	t.Run("currency crud", func(t *testing.T) { testCurrency(ctx, t, repo) })
+	t.Run("provider crud", func(t *testing.T) { testProvider(ctx, t, repo) })
+	t.Run("exchange rate", func(t *testing.T) { testExchangeRate(ctx, t, repo) })
+	t.Run("balance floor", func(t *testing.T) { testBalanceFloor(ctx, t, repo) })
+	t.Run("balance idempotency", func(t *testing.T) { testBalanceIdempotency(ctx, t, repo) })
+	t.Run("balance concurrency", func(t *testing.T) { testBalanceConcurrency(ctx, t, repo) })
+	t.Run("balance adjustment", func(t *testing.T) { testBalanceAdjustment(ctx, t, repo) })
+	t.Run("bonus money", func(t *testing.T) { testBonus(ctx, t, repo) })
+	t.Run("rollback", func(t *testing.T) { testRollback(ctx, t, repo) })
+	t.Run("tombstone", func(t *testing.T) { testTombstone(ctx, t, repo) })
+	t.Run("round", func(t *testing.T) { testRound(ctx, t, repo) })
+	t.Run("round provider", func(t *testing.T) { testRoundProvider(ctx, t, repo) })
+	t.Run("jackpot", func(t *testing.T) { testJackpot(ctx, t, repo) })
+	t.Run("campaign", func(t *testing.T) { testCampaign(ctx, t, repo) })
+	t.Run("gaming counter", func(t *testing.T) { testGamingCounter(ctx, t, repo) })
+	t.Run("gaming limit", func(t *testing.T) { testGamingLimit(ctx, t, repo) })
+	t.Run("deposit limit", func(t *testing.T) { testDepositLimit(ctx, t, repo) })
+	t.Run("session", func(t *testing.T) { testSession(ctx, t, repo) })
+	t.Run("session totals", func(t *testing.T) { testSessionTotals(ctx, t, repo) })
+	t.Run("outbox", func(t *testing.T) { testOutbox(ctx, t, repo) })
+	t.Run("transaction list", func(t *testing.T) { testTransactionList(ctx, t, repo) })
+	t.Run("transaction batch", func(t *testing.T) { testTransactionBatch(ctx, t, repo) })
+	t.Run("transaction batch debit credit", func(t *testing.T) { testTransactionBatchDebitCredit(ctx, t, repo) })
+	t.Run("transaction exchange", func(t *testing.T) { testTransactionExchange(ctx, t, repo) })
+}
+
+// player creates user with balance in fresh currency
+func player(ctx context.Context, t *testing.T, repo repository.Repo, amount int) (*domain.User, *domain.Currency) {
+	t.Helper()
+
+	cur := &domain.Currency{Code: "C" + domain.GenUID(), Denomination: 2}
+	require.NoError(t, repo.CurrencyCreate(ctx, cur))
+
+	user := &domain.User{UID: domain.GenUID(), Nick: "player"}
+	require.NoError(t, repo.UserCreate(ctx, user))
+
+	require.NoError(t, repo.BalanceCreate(ctx, &domain.Balance{
+		UserUID:      user.UID,
+		Amount:       amount,
+		Currency:     cur.Code,
+		Denomination: cur.Denomination,
+	}))
+	return user, cur
+}
+
+func balanceAmount(ctx context.Context, t *testing.T, repo repository.Repo, userUID, currency string) int {
+	t.Helper()
+
+	balance, err := repo.BalanceGetByUserUIDAndCurrency(ctx, userUID, currency)
+	require.NoError(t, err)
+	return balance.Amount
+}
+
+func testUser(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user := &domain.User{UID: domain.GenUID(), Nick: "test", MaxWin: map[string]int{"USD": 100}}
+	require.NoError(t, repo.UserCreate(ctx, user))
+
+	err := repo.UserCreate(ctx, user)
+	assert.Equal(t, domain.ErrRepoCreate, domain.AsError(err).Code)
+
+	res, err := repo.UserGetByUID(ctx, user.UID)
+	require.NoError(t, err)
+	assert.Equal(t, user, res)
+
+	user.Nick = "renamed"
+	user.Limits = map[string]domain.GamingLimits{"USD": {Loss: domain.PeriodLimits{Day: 500}, Wager: domain.PeriodLimits{Month: 9000}}}
+	user.CoolOffUntil = time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	user.SessionLimit = 2 * time.Hour
+	user.RealityCheckInterval = 30 * time.Minute
+	require.NoError(t, repo.UserUpdate(ctx, user))
+	res, err = repo.UserGetByUID(ctx, user.UID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", res.Nick)
+	assert.Equal(t, user.Limits, res.Limits)
+	assert.True(t, user.CoolOffUntil.Equal(res.CoolOffUntil))
+	assert.True(t, res.SelfExcludedUntil.IsZero())
+	assert.Equal(t, 2*time.Hour, res.SessionLimit)
+	assert.Equal(t, 30*time.Minute, res.RealityCheckInterval)
+
+	err = repo.UserUpdate(ctx, &domain.User{UID: domain.GenUID(), Nick: "unknown"})
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+
+	page, err := repo.UserList(ctx, domain.Pagination{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, page.Users, 1)
+	assert.GreaterOrEqual(t, page.Total, 1)
+
+	require.NoError(t, repo.UserDelete(ctx, user.UID))
+	_, err = repo.UserGetByUID(ctx, user.UID)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+
+	err = repo.UserDelete(ctx, user.UID)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+}
+
+func testCurrency(ctx context.Context, t *testing.T, repo repository.Repo) {
+	cur := &domain.Currency{Code: "C" + domain.GenUID(), Denomination: 2, MaxWin: 1000}
+	require.NoError(t, repo.CurrencyCreate(ctx, cur))
+
+	err := repo.CurrencyCreate(ctx, cur)
+	assert.Equal(t, domain.ErrRepoCreate, domain.AsError(err).Code)
+
+	cur.MaxWin = 500
+	require.NoError(t, repo.CurrencyUpdate(ctx, cur))
+	res, err := repo.CurrencyGetByCode(ctx, cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, cur, res)
+
+	currencies, err := repo.CurrencyList(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, currencies, *cur)
+
+	require.NoError(t, repo.CurrencyDelete(ctx, cur.Code))
+	_, err = repo.CurrencyGetByCode(ctx, cur.Code)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+}
+
+func testExchangeRate(ctx context.Context, t *testing.T, repo repository.Repo) {
+	from, to := "C"+domain.GenUID(), "C"+domain.GenUID()
+	rate := &domain.ExchangeRate{From: from, To: to, Rate: 1.25, UpdatedAt: time.Now()}
+	require.NoError(t, repo.ExchangeRateSave(ctx, rate))
+
+	res, err := repo.ExchangeRateGet(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1.25, res.Rate)
+
+	_, err = repo.ExchangeRateGet(ctx, to, from)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+
+	rate.Rate = 1.5
+	require.NoError(t, repo.ExchangeRateSave(ctx, rate))
+	res, err = repo.ExchangeRateGet(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, res.Rate)
+
+	rates, err := repo.ExchangeRateList(ctx)
+	require.NoError(t, err)
+	found := 0
+	for _, r := range rates {
+		if r.From == from && r.To == to {
+			found++
+		}
+	}
+	assert.Equal(t, 1, found)
+
+	require.NoError(t, repo.ExchangeRateDelete(ctx, from, to))
+	err = repo.ExchangeRateDelete(ctx, from, to)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+}
+
+func testProvider(ctx context.Context, t *testing.T, repo repository.Repo) {
+	provider := &domain.Provider{
+		UID:          domain.GenUID(),
+		Name:         "provider",
+		Secrets:      []string{"secret"},
+		AllowedIPs:   []string{"10.0.0.1", "192.168.0.0/16"},
+		Enabled:      true,
+		Currencies:   []string{"USD", "EUR"},
+		SignScheme:   domain.SignSchemeMD5,
+		AmountFormat: domain.AmountFormatDecimal,
+	}
+	require.NoError(t, repo.ProviderCreate(ctx, provider))
+
+	err := repo.ProviderCreate(ctx, provider)
+	assert.Equal(t, domain.ErrRepoCreate, domain.AsError(err).Code)
+
+	res, err := repo.ProviderGetByUID(ctx, provider.UID)
+	require.NoError(t, err)
+	assert.Equal(t, provider, res)
+
+	provider.RotateSecret("next")
+	provider.Enabled = false
+	require.NoError(t, repo.ProviderUpdate(ctx, provider))
+	res, err = repo.ProviderGetByUID(ctx, provider.UID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"next", "secret"}, res.Secrets)
+	assert.False(t, res.Enabled)
+
+	err = repo.ProviderUpdate(ctx, &domain.Provider{UID: domain.GenUID(), Name: "unknown"})
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+
+	providers, err := repo.ProviderList(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, providers, *provider)
+
+	require.NoError(t, repo.ProviderDelete(ctx, provider.UID))
+	_, err = repo.ProviderGetByUID(ctx, provider.UID)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+
+	err = repo.ProviderDelete(ctx, provider.UID)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+}
+
+func testBalanceFloor(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 101,
+		Currency:               cur.Code,
+	})
+	assert.Equal(t, domain.ErrDecrement, domain.AsError(err).Code)
+	assert.Equal(t, 100, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	txn, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 100,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, txn.UID)
+	assert.Equal(t, domain.TransactionTypeDebit, txn.Type)
+	assert.Equal(t, domain.TransactionStatusCommitted, txn.Status)
+	assert.Equal(t, cur.Denomination, txn.Denomination)
+	assert.Equal(t, 100, txn.BalanceBefore)
+	assert.Equal(t, 0, txn.Balance)
+	assert.Equal(t, 0, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	stored, err := repo.TransactionGetByUID(ctx, txn.UID)
+	require.NoError(t, err)
+	assert.Equal(t, txn.ProviderTransactionUID, stored.ProviderTransactionUID)
+	assert.Equal(t, 100, stored.Amount)
+}
+
+func testBalanceIdempotency(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	draft := &domain.Transaction{
+		ProviderUID:            "provider-a",
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 30,
+		Currency:               cur.Code,
+	}
+
+	first, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, draft)
+	require.NoError(t, err)
+	second, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, draft)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.UID, second.UID)
+	assert.Equal(t, "provider-a", first.ProviderUID)
+	assert.Equal(t, 130, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	found, err := repo.TransactionGetByProviderUID(ctx, "provider-a", draft.ProviderTransactionUID, domain.TransactionTypeCredit)
+	require.NoError(t, err)
+	assert.Equal(t, first.UID, found.UID)
+
+	// transaction ids of different providers don't clash
+	_, err = repo.TransactionGetByProviderUID(ctx, "provider-b", draft.ProviderTransactionUID, domain.TransactionTypeCredit)
+	assert.Error(t, err)
+	other := *draft
+	other.ProviderUID = "provider-b"
+	third, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, &other)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.UID, third.UID)
+	assert.Equal(t, 160, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testBalanceConcurrency(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+				ProviderTransactionUID: domain.GenUID(),
+				UserUID:                user.UID,
+				Amount:                 10,
+				Currency:               cur.Code,
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// concurrent debits never overdraw and every successful one is accounted
+	assert.LessOrEqual(t, succeeded, 10)
+	assert.Equal(t, 100-10*succeeded, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testBalanceAdjustment(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	txn, err := repo.BalanceAdjust(ctx, &domain.Transaction{
+		UserUID:  user.UID,
+		Amount:   -40,
+		Currency: cur.Code,
+		Operator: "admin",
+		Reason:   "correction",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionTypeAdjustment, txn.Type)
+	assert.Equal(t, "admin", txn.Operator)
+	assert.Equal(t, "correction", txn.Reason)
+	assert.Equal(t, 60, txn.Balance)
+
+	_, err = repo.BalanceAdjust(ctx, &domain.Transaction{
+		UserUID:  user.UID,
+		Amount:   -61,
+		Currency: cur.Code,
+		Operator: "admin",
+		Reason:   "correction",
+	})
+	assert.Equal(t, domain.ErrAdjustment, domain.AsError(err).Code)
+	assert.Equal(t, 60, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	balances, err := repo.BalanceListByUserUID(ctx, user.UID)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	assert.Equal(t, 60, balances[0].Amount)
+}
+
+func testBonus(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	roundUID := domain.GenUID()
+
+	grant, err := repo.BalanceAdjust(ctx, &domain.Transaction{
+		UserUID:     user.UID,
+		Amount:      100,
+		BonusAmount: 100,
+		Wagering:    260,
+		Currency:    cur.Code,
+		Operator:    "admin",
+		Reason:      "welcome bonus",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 200, grant.Balance)
+	assert.Equal(t, 100, grant.BonusBalance)
+
+	bonusBet, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 40,
+		Currency:               cur.Code,
+		BonusOrder:             domain.BonusOrderBonusFirst,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 40, bonusBet.BonusAmount)
+	assert.Equal(t, 60, bonusBet.BonusBalance)
+
+	realBet, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 40,
+		Currency:               cur.Code,
+		BonusOrder:             domain.BonusOrderRealFirst,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, realBet.BonusAmount)
+
+	// half of the round bets was bonus money, so is the win
+	win, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 80,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 40, win.BonusAmount)
+	assert.Equal(t, 200, win.Balance)
+	assert.Equal(t, 100, win.BonusBalance)
+
+	round, err := repo.RoundGetByProviderUID(ctx, "", roundUID)
+	require.NoError(t, err)
+	assert.Equal(t, 80, round.TotalBet)
+	assert.Equal(t, 40, round.TotalBonusBet)
+
+	rollback, err := repo.TransactionRollback(ctx, bonusBet.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+	assert.Equal(t, 40, rollback.BonusAmount)
+	assert.Equal(t, 140, rollback.BonusBalance)
+
+	balance, err := repo.BalanceGetByUserUIDAndCurrency(ctx, user.UID, cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 240, balance.Amount)
+	assert.Equal(t, 140, balance.Bonus)
+	// the rolled back bet doesn't count to the wagering requirement
+	assert.Equal(t, 220, balance.Wagering)
+
+	// wagering requirement met turns the rest of bonus money into real money
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 220,
+		Currency:               cur.Code,
+		BonusOrder:             domain.BonusOrderRealFirst,
+	})
+	require.NoError(t, err)
+
+	balance, err = repo.BalanceGetByUserUIDAndCurrency(ctx, user.UID, cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 20, balance.Amount)
+	assert.Equal(t, 0, balance.Bonus)
+	assert.Equal(t, 0, balance.Wagering)
+}
+
+func testRollback(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	debit, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 40,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	rollback, err := repo.TransactionRollback(ctx, debit.UID, domain.TransactionMeta{BetMeta: "bet"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionTypeRollback, rollback.Type)
+	assert.Equal(t, debit.UID, rollback.ParentTransactionUID)
+	assert.Equal(t, 60, rollback.BalanceBefore)
+	assert.Equal(t, 100, rollback.Balance)
+	assert.Equal(t, "bet", rollback.Meta.BetMeta)
+
+	repeated, err := repo.TransactionRollback(ctx, debit.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+	assert.Equal(t, rollback.UID, repeated.UID)
+	assert.Equal(t, 100, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	original, err := repo.TransactionGetByUID(ctx, debit.UID)
+	require.NoError(t, err)
+	assert.True(t, original.IsRolledBack())
+	assert.Equal(t, rollback.UID, original.RollbackTransactionUID)
+
+	_, err = repo.TransactionRollback(ctx, domain.GenUID(), domain.TransactionMeta{})
+	assert.Equal(t, domain.ErrRollback, domain.AsError(err).Code)
+
+	// debit and credit sharing provider transaction id are rolled back both
+	providerUID := domain.GenUID()
+	bet, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: providerUID,
+		UserUID:                user.UID,
+		Amount:                 10,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+	win, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: providerUID,
+		UserUID:                user.UID,
+		Amount:                 30,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	found, err := repo.TransactionGetByProviderUID(ctx, "", providerUID, domain.TransactionTypeDebit)
+	require.NoError(t, err)
+	assert.Equal(t, bet.UID, found.UID)
+	found, err = repo.TransactionGetByProviderUID(ctx, "", providerUID, domain.TransactionTypeCredit)
+	require.NoError(t, err)
+	assert.Equal(t, win.UID, found.UID)
+
+	_, err = repo.TransactionRollback(ctx, bet.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+	_, err = repo.TransactionRollback(ctx, win.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+	assert.Equal(t, 100, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testTombstone(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	providerUID := domain.GenUID()
+
+	tombstone, err := repo.TransactionCreateTombstone(ctx, &domain.Transaction{
+		ProviderTransactionUID: providerUID,
+		UserUID:                user.UID,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionStatusTombstone, tombstone.Status)
+
+	repeated, err := repo.TransactionCreateTombstone(ctx, &domain.Transaction{
+		ProviderTransactionUID: providerUID,
+		UserUID:                user.UID,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tombstone.UID, repeated.UID)
+
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: providerUID,
+		UserUID:                user.UID,
+		Amount:                 10,
+		Currency:               cur.Code,
+	})
+	assert.Equal(t, domain.ErrTransactionRolledBack, domain.AsError(err).Code)
+	assert.Equal(t, 100, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	// tombstone of the request in another currency is booked to the converted balance
+	converted, err := repo.TransactionCreateTombstone(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Currency:               cur.Code,
+		OriginalCurrency:       "EUR",
+		ExchangeRate:           1.1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cur.Code, converted.Currency)
+	assert.Equal(t, "EUR", converted.OriginalCurrency)
+	assert.Equal(t, 1.1, converted.ExchangeRate)
+	assert.Equal(t, 100, converted.Balance)
+}
+
+func testRound(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	roundUID := domain.GenUID()
+
+	_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 30,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	round, err := repo.RoundGetByProviderUID(ctx, "", roundUID)
+	require.NoError(t, err)
+
+	_, err = repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 50,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	// stale round can't be closed
+	_, err = repo.RoundClose(ctx, round)
+	assert.Equal(t, domain.ErrRoundClose, domain.AsError(err).Code)
+
+	round, err = repo.RoundGetByProviderUID(ctx, "", roundUID)
+	require.NoError(t, err)
+	assert.Equal(t, 30, round.TotalBet)
+	assert.Equal(t, 50, round.TotalWin)
+	assert.Len(t, round.Transactions, 2)
+	assert.True(t, round.Reconcile())
+
+	closed, err := repo.RoundClose(ctx, round)
+	require.NoError(t, err)
+	assert.True(t, closed.IsClosed())
+
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		RoundUID:               roundUID,
+		Amount:                 10,
+		Currency:               cur.Code,
+	})
+	assert.Equal(t, domain.ErrRoundClosed, domain.AsError(err).Code)
+	assert.Equal(t, 120, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testRoundProvider(ctx context.Context, t *testing.T, repo repository.Repo) {
+	first, cur := player(ctx, t, repo, 100)
+	second := &domain.User{UID: domain.GenUID(), Nick: "player"}
+	require.NoError(t, repo.UserCreate(ctx, second))
+	require.NoError(t, repo.BalanceCreate(ctx, &domain.Balance{UserUID: second.UID, Amount: 100, Currency: cur.Code, Denomination: cur.Denomination}))
+	roundUID := domain.GenUID()
+
+	bet := func(providerUID, userUID string, amount int) error {
+		_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+			ProviderUID:            providerUID,
+			ProviderTransactionUID: domain.GenUID(),
+			UserUID:                userUID,
+			RoundUID:               roundUID,
+			Amount:                 amount,
+			Currency:               cur.Code,
+		})
+		return err
+	}
+
+	// the same bet id of another provider is another round
+	require.NoError(t, bet("provider-a", first.UID, 30))
+	require.NoError(t, bet("provider-b", second.UID, 20))
+
+	err := bet("provider-a", second.UID, 10)
+	assert.Equal(t, domain.ErrRoundUser, domain.AsError(err).Code)
+	assert.Equal(t, 80, balanceAmount(ctx, t, repo, second.UID, cur.Code))
+
+	firstRound, err := repo.RoundGetByProviderUID(ctx, "provider-a", roundUID)
+	require.NoError(t, err)
+	assert.Equal(t, first.UID, firstRound.UserUID)
+	assert.Equal(t, 30, firstRound.TotalBet)
+	assert.Len(t, firstRound.Transactions, 1)
+
+	secondRound, err := repo.RoundGetByProviderUID(ctx, "provider-b", roundUID)
+	require.NoError(t, err)
+	assert.Equal(t, second.UID, secondRound.UserUID)
+	assert.Equal(t, 20, secondRound.TotalBet)
+	assert.Len(t, secondRound.Transactions, 1)
+
+	closed, err := repo.RoundClose(ctx, firstRound)
+	require.NoError(t, err)
+	assert.True(t, closed.IsClosed())
+
+	// closing the round doesn't close the one of another provider
+	require.NoError(t, bet("provider-b", second.UID, 5))
+	assert.Equal(t, 75, balanceAmount(ctx, t, repo, second.UID, cur.Code))
+}
+
+func testJackpot(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+	gameUID := domain.GenUID()
+
+	require.NoError(t, repo.JackpotCreate(ctx, &domain.Jackpot{
+		Key:                 "game",
+		Currency:            cur.Code,
+		GameUID:             gameUID,
+		Amount:              100,
+		ContributionPercent: 10,
+		IsActive:            true,
+	}))
+	require.NoError(t, repo.JackpotCreate(ctx, &domain.Jackpot{Key: "shared", Currency: cur.Code, IsActive: true}))
+	require.NoError(t, repo.JackpotCreate(ctx, &domain.Jackpot{Key: "other", Currency: cur.Code, GameUID: domain.GenUID(), IsActive: true}))
+
+	jackpots, err := repo.JackpotListActiveByGame(ctx, gameUID, cur.Code)
+	require.NoError(t, err)
+	require.Len(t, jackpots, 2)
+	assert.Equal(t, "game", jackpots[0].Key)
+	assert.Equal(t, "shared", jackpots[1].Key)
+
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 100,
+		Currency:               cur.Code,
+		JackpotKey:             "game",
+		JackpotContribution:    10,
+	})
+	require.NoError(t, err)
+
+	jackpot, err := repo.JackpotGetByKey(ctx, "game", cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 110, jackpot.Amount)
+
+	_, err = repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 111,
+		Currency:               cur.Code,
+		JackpotKey:             "game",
+	})
+	assert.Equal(t, domain.ErrJackpotPayout, domain.AsError(err).Code)
+	assert.Equal(t, 900, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	payout, err := repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 110,
+		Currency:               cur.Code,
+		JackpotKey:             "game",
+	})
+	require.NoError(t, err)
+
+	jackpot, err = repo.JackpotGetByKey(ctx, "game", cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 0, jackpot.Amount)
+
+	// rolled back payout returns to the pool
+	_, err = repo.TransactionRollback(ctx, payout.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+	jackpot, err = repo.JackpotGetByKey(ctx, "game", cur.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 110, jackpot.Amount)
+}
+
+func testCampaign(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	campaign := &domain.Campaign{
+		UID:        domain.GenUID(),
+		UserUID:    user.UID,
+		GameUID:    domain.GenUID(),
+		Currency:   cur.Code,
+		Rounds:     2,
+		RoundsLeft: 2,
+		BetValue:   20,
+		Win:        domain.CampaignWinReal,
+		Status:     domain.CampaignStatusActive,
+		CreatedAt:  time.Now().Truncate(time.Millisecond),
+		ExpiresAt:  time.Now().Add(time.Hour).Truncate(time.Millisecond),
+	}
+	require.NoError(t, repo.CampaignCreate(ctx, campaign))
+
+	campaigns, err := repo.CampaignList(ctx, domain.CampaignFilter{UserUID: user.UID})
+	require.NoError(t, err)
+	require.Len(t, campaigns, 1)
+	assert.Equal(t, campaign.UID, campaigns[0].UID)
+
+	freeRound := func() (*domain.Transaction, error) {
+		return repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+			ProviderTransactionUID: domain.GenUID(),
+			UserUID:                user.UID,
+			Currency:               cur.Code,
+			CampaignUID:            campaign.UID,
+		})
+	}
+
+	// free round is paid by the campaign, the balance stays
+	first, err := freeRound()
+	require.NoError(t, err)
+	assert.Equal(t, 100, first.Balance)
+	assert.Equal(t, campaign.UID, first.CampaignUID)
+
+	_, err = freeRound()
+	require.NoError(t, err)
+
+	stored, err := repo.CampaignGetByUID(ctx, campaign.UID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.RoundsLeft)
+	assert.Equal(t, domain.CampaignStatusCompleted, stored.Status)
+
+	_, err = freeRound()
+	assert.Equal(t, domain.ErrCampaignUnavailable, domain.AsError(err).Code)
+
+	// rolled back free round is played again
+	_, err = repo.TransactionRollback(ctx, first.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+
+	stored, err = repo.CampaignGetByUID(ctx, campaign.UID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.RoundsLeft)
+	assert.Equal(t, domain.CampaignStatusActive, stored.Status)
+
+	cancelled, err := repo.CampaignCancel(ctx, campaign.UID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CampaignStatusCancelled, cancelled.Status)
+
+	_, err = freeRound()
+	assert.Equal(t, domain.ErrCampaignUnavailable, domain.AsError(err).Code)
+
+	_, err = repo.CampaignCancel(ctx, campaign.UID)
+	assert.Equal(t, domain.ErrCampaignUnavailable, domain.AsError(err).Code)
+
+	_, err = repo.CampaignCancel(ctx, domain.GenUID())
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+	assert.Equal(t, 100, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testGamingCounter(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+
+	debit, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 300,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 100,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	// adjustment isn't gaming
+	_, err = repo.BalanceAdjust(ctx, &domain.Transaction{
+		UserUID:  user.UID,
+		Amount:   -50,
+		Currency: cur.Code,
+		Operator: "admin",
+		Reason:   "correction",
+	})
+	require.NoError(t, err)
+
+	counters, err := repo.GamingCounterList(ctx, user.UID, cur.Code, time.Now())
+	require.NoError(t, err)
+	require.Len(t, counters, len(domain.LimitPeriods))
+	for _, counter := range counters {
+		assert.Equal(t, 300, counter.Wager, counter.Period)
+		assert.Equal(t, 200, counter.Loss, counter.Period)
+	}
+
+	// rolled back bet doesn't count
+	_, err = repo.TransactionRollback(ctx, debit.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+
+	counters, err = repo.GamingCounterList(ctx, user.UID, cur.Code, time.Now())
+	require.NoError(t, err)
+	for _, counter := range counters {
+		assert.Equal(t, 0, counter.Wager, counter.Period)
+		assert.Equal(t, -100, counter.Loss, counter.Period)
+	}
+
+	// the next month starts from zero
+	counters, err = repo.GamingCounterList(ctx, user.UID, cur.Code, time.Now().AddDate(0, 1, 1))
+	require.NoError(t, err)
+	for _, counter := range counters {
+		assert.Equal(t, 0, counter.Wager, counter.Period)
+		assert.Equal(t, 0, counter.Loss, counter.Period)
+	}
+}
+
+func testGamingLimit(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+	limits := &domain.GamingLimits{Wager: domain.PeriodLimits{Day: 250}}
+	debit := func() *domain.Transaction {
+		return &domain.Transaction{
+			ProviderTransactionUID: domain.GenUID(),
+			UserUID:                user.UID,
+			Amount:                 100,
+			Currency:               cur.Code,
+			GamingLimits:           limits,
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, debit())
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// concurrent debits never take the user over the limit
+	assert.LessOrEqual(t, succeeded, 2)
+	counters, err := repo.GamingCounterList(ctx, user.UID, cur.Code, time.Now())
+	require.NoError(t, err)
+	for _, counter := range counters {
+		assert.Equal(t, 100*succeeded, counter.Wager, counter.Period)
+	}
+
+	// the limit is checked against the counters at the moment of the debit
+	for succeeded < 2 {
+		_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, debit())
+		require.NoError(t, err)
+		succeeded++
+	}
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, debit())
+	assert.Equal(t, domain.ErrGamingLimitExceeded, domain.AsError(err).Code)
+
+	// the limit applies to the debits of the batch counted together
+	user, cur = player(ctx, t, repo, 1000)
+	first, second := debit(), debit()
+	first.Type, second.Type = domain.TransactionTypeDebit, domain.TransactionTypeDebit
+	first.Amount, second.Amount = 200, 200
+	_, failed, err := repo.TransactionBatch(ctx, []*domain.Transaction{first, second})
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, domain.ErrGamingLimitExceeded, domain.AsError(err).Code)
+	assert.Equal(t, 1000, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
+
+func testDepositLimit(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 0)
+	limits := &domain.GamingLimits{Deposit: domain.PeriodLimits{Week: 500}}
+	adjust := func(amount int, bonus bool) error {
+		txn := &domain.Transaction{
+			UserUID:      user.UID,
+			Amount:       amount,
+			Currency:     cur.Code,
+			Operator:     "admin",
+			Reason:       "deposit",
+			GamingLimits: limits,
+		}
+		if bonus {
+			txn.BonusAmount, txn.Wagering = amount, amount
+		}
+		_, err := repo.BalanceAdjust(ctx, txn)
+		return err
+	}
+
+	require.NoError(t, adjust(300, false))
+	err := adjust(300, false)
+	assert.Equal(t, domain.ErrDepositLimitExceeded, domain.AsError(err).Code)
+	assert.Equal(t, 300, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	// neither taking money away nor bonus money is a deposit
+	require.NoError(t, adjust(-100, false))
+	require.NoError(t, adjust(1000, true))
+	require.NoError(t, adjust(200, false))
+	assert.Equal(t, 1400, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	counters, err := repo.GamingCounterList(ctx, user.UID, cur.Code, time.Now())
+	require.NoError(t, err)
+	for _, counter := range counters {
+		assert.Equal(t, 500, counter.Deposit, counter.Period)
+		assert.Equal(t, 0, counter.Wager, counter.Period)
+	}
+}
+
+func testSession(ctx context.Context, t *testing.T, repo repository.Repo) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	active := &domain.Session{
+		UID:            domain.GenUID(),
+		UserUID:        domain.GenUID(),
+		Currency:       "USD",
+		WalletCurrency: "EUR",
+		Status:         domain.SessionStatusOpen,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+	stale := &domain.Session{
+		UID:       domain.GenUID(),
+		UserUID:   domain.GenUID(),
+		Status:    domain.SessionStatusOpen,
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	}
+	require.NoError(t, repo.SessionCreate(ctx, active))
+	require.NoError(t, repo.SessionCreate(ctx, stale))
+
+	expired, err := repo.SessionExpire(ctx, now)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, expired, 1)
+
+	res, err := repo.SessionGetByUID(ctx, stale.UID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusExpired, res.Status)
+
+	require.NoError(t, repo.SessionTouch(ctx, active.UID, now.Add(time.Minute), time.Time{}))
+	res, err = repo.SessionGetByUID(ctx, active.UID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusOpen, res.Status)
+	assert.Equal(t, "USD", res.Currency)
+	assert.Equal(t, "EUR", res.WalletCurrency)
+	assert.True(t, res.LastActivityAt.Equal(now.Add(time.Minute)))
+	assert.True(t, res.ExpiresAt.Equal(now.Add(time.Hour)))
+
+	// the activity moves the expiry forward, so the active session outlives the TTL counted from its start
+	require.NoError(t, repo.SessionTouch(ctx, active.UID, now.Add(50*time.Minute), now.Add(110*time.Minute)))
+	_, err = repo.SessionExpire(ctx, now.Add(90*time.Minute))
+	require.NoError(t, err)
+	res, err = repo.SessionGetByUID(ctx, active.UID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusOpen, res.Status)
+	assert.True(t, res.ExpiresAt.Equal(now.Add(110*time.Minute)))
+	assert.True(t, res.IsActive(now.Add(90*time.Minute)))
+
+	// the expired session isn't brought back by the late activity
+	require.NoError(t, repo.SessionTouch(ctx, stale.UID, now, now.Add(time.Hour)))
+	res, err = repo.SessionGetByUID(ctx, stale.UID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusExpired, res.Status)
+	assert.True(t, res.ExpiresAt.Equal(now.Add(-time.Hour)))
+
+	closed, err := repo.SessionClose(ctx, active.UID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusClosed, closed.Status)
+
+	_, err = repo.SessionClose(ctx, active.UID, now)
+	assert.Equal(t, domain.ErrSessionClose, domain.AsError(err).Code)
+
+	require.NoError(t, repo.SessionRealityChecked(ctx, active.UID, now.Add(30*time.Minute)))
+	res, err = repo.SessionGetByUID(ctx, active.UID)
+	require.NoError(t, err)
+	assert.True(t, res.RealityCheckedAt.Equal(now.Add(30*time.Minute)))
+
+	err = repo.SessionRealityChecked(ctx, domain.GenUID(), now)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(err).Code)
+}
+
+func testSessionTotals(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+	session := &domain.Session{
+		UID:       domain.GenUID(),
+		UserUID:   user.UID,
+		Currency:  cur.Code,
+		Status:    domain.SessionStatusOpen,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	require.NoError(t, repo.SessionCreate(ctx, session))
+
+	debit, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		SessionUID:             session.UID,
+		Amount:                 300,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.BalanceIncrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		SessionUID:             session.UID,
+		Amount:                 100,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	res, err := repo.SessionGetByUID(ctx, session.UID)
+	require.NoError(t, err)
+	assert.Equal(t, 300, res.TotalBet)
+	assert.Equal(t, 100, res.TotalWin)
+
+	// rolled back bet leaves the session
+	_, err = repo.TransactionRollback(ctx, debit.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+
+	res, err = repo.SessionGetByUID(ctx, session.UID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.TotalBet)
+	assert.Equal(t, 100, res.TotalWin)
+}
+
+// outboxPending returns the pending outbox event of the transaction, other
+// tests of the run leave their own events behind.
+func outboxPending(ctx context.Context, t *testing.T, repo repository.Repo, now time.Time, txnUID string) *domain.OutboxEvent {
+	events, err := repo.OutboxListPending(ctx, now, 100000)
+	require.NoError(t, err)
+	for i := range events {
+		if events[i].Transaction.UID == txnUID {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+func testOutbox(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+	now := time.Now().UTC()
+
+	debit, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 300,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	event := outboxPending(ctx, t, repo, now.Add(time.Second), debit.UID)
+	require.NotNil(t, event)
+	assert.Equal(t, domain.OutboxStatusPending, event.Status)
+	assert.Equal(t, domain.TransactionTypeDebit, event.Transaction.Type)
+	assert.Equal(t, 300, event.Transaction.Amount)
+	assert.Equal(t, 700, event.Transaction.Balance)
+
+	// replayed debit records no second event
+	_, err = repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: debit.ProviderTransactionUID,
+		UserUID:                user.UID,
+		Amount:                 300,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	events, err := repo.OutboxListPending(ctx, now.Add(time.Second), 100000)
+	require.NoError(t, err)
+	count := 0
+	for _, e := range events {
+		if e.Transaction.UID == debit.UID {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// retry is not due before its next attempt
+	event.Failed(errors.New("unavailable"), now, time.Minute, 10)
+	require.NoError(t, repo.OutboxUpdate(ctx, event))
+	assert.Nil(t, outboxPending(ctx, t, repo, now.Add(time.Second), debit.UID))
+
+	retry := outboxPending(ctx, t, repo, now.Add(2*time.Minute), debit.UID)
+	require.NotNil(t, retry)
+	assert.Equal(t, 1, retry.Attempts)
+	assert.Equal(t, "unavailable", retry.LastError)
+
+	retry.Delivered(now)
+	require.NoError(t, repo.OutboxUpdate(ctx, retry))
+	assert.Nil(t, outboxPending(ctx, t, repo, now.Add(2*time.Minute), debit.UID))
+
+	rollback, err := repo.TransactionRollback(ctx, debit.UID, domain.TransactionMeta{})
+	require.NoError(t, err)
+
+	event = outboxPending(ctx, t, repo, now.Add(time.Second), rollback.UID)
+	require.NotNil(t, event)
+	assert.Equal(t, domain.TransactionTypeRollback, event.Transaction.Type)
+	assert.Equal(t, debit.UID, event.Transaction.ParentTransactionUID)
+
+	// unknown event
+	missing := domain.NewOutboxEvent(debit)
+	assert.Equal(t, domain.ErrNotFound, domain.AsError(repo.OutboxUpdate(ctx, missing)).Code)
+}
+
+func testTransactionList(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+			ProviderTransactionUID: domain.GenUID(),
+			UserUID:                user.UID,
+			Amount:                 10,
+			Currency:               cur.Code,
+		})
+		require.NoError(t, err)
+	}
+	_, err := repo.BalanceAdjust(ctx, &domain.Transaction{
+		UserUID:  user.UID,
+		Amount:   5,
+		Currency: cur.Code,
+		Operator: "admin",
+		Reason:   "correction",
+	})
+	require.NoError(t, err)
+
+	page, err := repo.TransactionList(ctx, domain.TransactionFilter{
+		UserUID:    user.UID,
+		Type:       domain.TransactionTypeDebit,
+		Pagination: domain.Pagination{Offset: 1, Limit: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, domain.TransactionTypeDebit, page.Transactions[0].Type)
+
+	page, err = repo.TransactionList(ctx, domain.TransactionFilter{
+		UserUID:    user.UID,
+		Pagination: domain.Pagination{Limit: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Total)
+	// newest first
+	assert.Equal(t, domain.TransactionTypeAdjustment, page.Transactions[0].Type)
+
+	page, err = repo.TransactionList(ctx, domain.TransactionFilter{
+		UserUID:    user.UID,
+		From:       time.Now().Add(time.Hour),
+		Pagination: domain.Pagination{Limit: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, page.Total)
+}
+
+func testTransactionBatch(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	debit, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 10,
+		Currency:               cur.Code,
+	})
+	require.NoError(t, err)
+
+	drafts := []*domain.Transaction{
+		{ProviderTransactionUID: domain.GenUID(), UserUID: user.UID, Amount: 30, Currency: cur.Code, Type: domain.TransactionTypeDebit},
+		{ProviderTransactionUID: domain.GenUID(), UserUID: user.UID, Amount: 50, Currency: cur.Code, Type: domain.TransactionTypeCredit},
+		{UserUID: user.UID, ParentTransactionUID: debit.UID, Type: domain.TransactionTypeRollback},
+		{ProviderTransactionUID: domain.GenUID(), UserUID: user.UID, Currency: cur.Code, Type: domain.TransactionTypeRollback, Status: domain.TransactionStatusTombstone},
+	}
+	txns, failed, err := repo.TransactionBatch(ctx, drafts)
+	require.NoError(t, err)
+	assert.Equal(t, -1, failed)
+	require.Len(t, txns, 4)
+	assert.Equal(t, 60, txns[0].Balance)
+	assert.Equal(t, 110, txns[1].Balance)
+	assert.Equal(t, 120, txns[2].Balance)
+	assert.Equal(t, domain.TransactionStatusTombstone, txns[3].Status)
+	assert.Equal(t, 120, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	// replayed batch returns the stored transactions without moving money
+	replayed, _, err := repo.TransactionBatch(ctx, drafts)
+	require.NoError(t, err)
+	for i := range txns {
+		assert.Equal(t, txns[i].UID, replayed[i].UID)
+	}
+	assert.Equal(t, 120, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	// failed draft leaves no trace of the drafts before it
+	credit := &domain.Transaction{ProviderTransactionUID: domain.GenUID(), UserUID: user.UID, Amount: 20, Currency: cur.Code, Type: domain.TransactionTypeCredit}
+	_, failed, err = repo.TransactionBatch(ctx, []*domain.Transaction{
+		credit,
+		{ProviderTransactionUID: domain.GenUID(), UserUID: user.UID, Amount: 500, Currency: cur.Code, Type: domain.TransactionTypeDebit},
+	})
+	assert.Equal(t, domain.ErrDecrement, domain.AsError(err).Code)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, 120, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+	_, err = repo.TransactionGetByProviderUID(ctx, "", credit.ProviderTransactionUID, domain.TransactionTypeCredit)
+	assert.Error(t, err)
+
+	// replayed provider transaction with another amount conflicts
+	conflict := *drafts[0]
+	conflict.Amount = 31
+	_, failed, err = repo.TransactionBatch(ctx, []*domain.Transaction{&conflict})
+	assert.Equal(t, domain.ErrTransactionConflict, domain.AsError(err).Code)
+	assert.Equal(t, 0, failed)
+}
+
+func testTransactionBatchDebitCredit(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 100)
+	providerUID, roundUID := domain.GenUID(), domain.GenUID()
+	drafts := []*domain.Transaction{
+		{ProviderTransactionUID: providerUID, UserUID: user.UID, RoundUID: roundUID, Amount: 20, Currency: cur.Code, Type: domain.TransactionTypeDebit},
+		{ProviderTransactionUID: providerUID, UserUID: user.UID, RoundUID: roundUID, Amount: 50, Currency: cur.Code, Type: domain.TransactionTypeCredit},
+	}
+
+	txns, _, err := repo.TransactionBatch(ctx, drafts)
+	require.NoError(t, err)
+	assert.NotEqual(t, txns[0].UID, txns[1].UID)
+	assert.Equal(t, 130, txns[1].Balance)
+
+	replayed, _, err := repo.TransactionBatch(ctx, drafts)
+	require.NoError(t, err)
+	assert.Equal(t, txns[0].UID, replayed[0].UID)
+	assert.Equal(t, txns[1].UID, replayed[1].UID)
+	assert.Equal(t, 130, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+
+	round, err := repo.RoundGetByProviderUID(ctx, "", roundUID)
+	require.NoError(t, err)
+	assert.Equal(t, 20, round.TotalBet)
+	assert.Equal(t, 50, round.TotalWin)
+}
+
+func testTransactionExchange(ctx context.Context, t *testing.T, repo repository.Repo) {
+	user, cur := player(ctx, t, repo, 1000)
+	draft := &domain.Transaction{
+		ProviderTransactionUID: domain.GenUID(),
+		UserUID:                user.UID,
+		Amount:                 125,
+		Currency:               cur.Code,
+		OriginalAmount:         100,
+		OriginalCurrency:       "EUR",
+		ExchangeRate:           1.25,
+	}
+
+	txn, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, draft)
+	require.NoError(t, err)
+	assert.Equal(t, 875, txn.Balance)
+	assert.Equal(t, 100, txn.OriginalAmount)
+	assert.Equal(t, "EUR", txn.OriginalCurrency)
+	assert.Equal(t, 1.25, txn.ExchangeRate)
+
+	// replay at the changed rate books nothing and returns the stored transaction
+	replay := *draft
+	replay.Amount, replay.ExchangeRate = 130, 1.3
+	res, err := repo.BalanceDecrementByUserUIDAndCurrency(ctx, &replay)
+	require.NoError(t, err)
+	assert.Equal(t, txn.UID, res.UID)
+	assert.Equal(t, 875, balanceAmount(ctx, t, repo, user.UID, cur.Code))
+}
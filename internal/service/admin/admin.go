@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"open-api-games/internal/domain"
+)
+
+//go:generate mockery --dir . --name Repository --output ./mocks --case=underscore
+type Repository interface {
+	UserGetByUID(ctx context.Context, uid string) (*domain.User, error)
+	UserList(ctx context.Context, page domain.Pagination) (*domain.UserPage, error)
+	UserCreate(ctx context.Context, user *domain.User) error
+	UserUpdate(ctx context.Context, user *domain.User) error
+	UserDelete(ctx context.Context, uid string) error
+	CurrencyGetByCode(ctx context.Context, code string) (*domain.Currency, error)
+	CurrencyList(ctx context.Context) ([]domain.Currency, error)
+	CurrencyCreate(ctx context.Context, cur *domain.Currency) error
+	CurrencyUpdate(ctx context.Context, cur *domain.Currency) error
+	CurrencyDelete(ctx context.Context, code string) error
+	ExchangeRateList(ctx context.Context) ([]domain.ExchangeRate, error)
+	ExchangeRateSave(ctx context.Context, rate *domain.ExchangeRate) error
+	ExchangeRateDelete(ctx context.Context, from, to string) error
+	BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error)
+	BalanceCreate(ctx context.Context, balance *domain.Balance) error
+	BalanceAdjust(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error)
+	TransactionList(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error)
+	ProviderGetByUID(ctx context.Context, uid string) (*domain.Provider, error)
+	ProviderList(ctx context.Context) ([]domain.Provider, error)
+	ProviderCreate(ctx context.Context, provider *domain.Provider) error
+	ProviderUpdate(ctx context.Context, provider *domain.Provider) error
+	ProviderDelete(ctx context.Context, uid string) error
+	CampaignGetByUID(ctx context.Context, uid string) (*domain.Campaign, error)
+	CampaignList(ctx context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error)
+	CampaignCreate(ctx context.Context, campaign *domain.Campaign) error
+	CampaignCancel(ctx context.Context, uid string) (*domain.Campaign, error)
+}
+
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+func New(repo Repository, logger *slog.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorBalanceSource = "[service.admin.balance]"
+)
+
+func (s *Service) BalanceList(ctx context.Context, userUID string) ([]domain.Balance, error) {
+	_, err := s.repo.UserGetByUID(ctx, userUID)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+
+	balances, err := s.repo.BalanceListByUserUID(ctx, userUID)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return balances, nil
+}
+
+// BalanceCreate opens empty balance of the user in the currency
+func (s *Service) BalanceCreate(ctx context.Context, userUID, currency string) (*domain.Balance, error) {
+	_, err := s.repo.UserGetByUID(ctx, userUID)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+
+	cur, err := s.repo.CurrencyGetByCode(ctx, currency)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	balance := &domain.Balance{
+		UserUID:      userUID,
+		Currency:     cur.Code,
+		Denomination: cur.Denomination,
+	}
+	err = s.repo.BalanceCreate(ctx, balance)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return balance, nil
+}
+
+// BalanceAdjust corrects user balance by hand, the correction is recorded as adjustment transaction
+// keeping the operator and the reason for audit
+func (s *Service) BalanceAdjust(ctx context.Context, req *domain.BalanceAdjustReq) (*domain.Transaction, error) {
+	if req.Amount == 0 || req.Operator == "" || req.Reason == "" {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrInvalidRequest)
+	}
+	// bonus money without wagering requirement would be real money at once
+	grant := req.Bonus && req.Amount > 0
+	if grant != (req.Wagering > 0) || req.Wagering < 0 {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	user, err := s.repo.UserGetByUID(ctx, req.UserUID)
+	if err != nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+
+	draft := &domain.Transaction{
+		UserUID:  req.UserUID,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Operator: req.Operator,
+		Reason:   req.Reason,
+	}
+	if req.Bonus {
+		draft.BonusAmount = req.Amount
+		draft.Wagering = req.Wagering
+	}
+	// the repository checks the deposit against the deposit limits within the movement, like it does for the bets
+	if limits, ok := user.Limits[req.Currency]; ok {
+		draft.GamingLimits = &limits
+	}
+	txn, err := s.repo.BalanceAdjust(ctx, draft)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrDepositLimitExceeded {
+			return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrDepositLimitExceeded).Add(err)
+		}
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrAdjustment).Add(err)
+	}
+
+	s.logger.Info("balance adjusted", "userUid", req.UserUID, "currency", req.Currency, "amount", req.Amount, "bonus", req.Bonus, "operator", req.Operator, "transactionUid", txn.UID)
+
+	return txn, nil
+}
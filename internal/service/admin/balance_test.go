@@ -0,0 +1,248 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+)
+
+func TestBalanceAdjust(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("adjust balance success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("BalanceAdjust", ctx, &domain.Transaction{
+				UserUID:  "123",
+				Amount:   -100,
+				Currency: "USD",
+				Operator: "admin",
+				Reason:   "chargeback",
+			}).
+			Return(&domain.Transaction{
+				UID:           "txn",
+				UserUID:       "123",
+				Amount:        -100,
+				Currency:      "USD",
+				Type:          domain.TransactionTypeAdjustment,
+				Status:        domain.TransactionStatusCommitted,
+				Operator:      "admin",
+				Reason:        "chargeback",
+				BalanceBefore: 1000,
+				Balance:       900,
+			}, nil)
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   -100,
+			Operator: "admin",
+			Reason:   "chargeback",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TransactionTypeAdjustment, res.Type)
+		assert.Equal(t, "admin", res.Operator)
+		assert.Equal(t, 900, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("adjust balance without reason", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   100,
+			Operator: "admin",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("grant bonus money", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("BalanceAdjust", ctx, &domain.Transaction{
+				UserUID:     "123",
+				Amount:      500,
+				BonusAmount: 500,
+				Wagering:    15000,
+				Currency:    "USD",
+				Operator:    "admin",
+				Reason:      "welcome bonus",
+			}).
+			Return(&domain.Transaction{
+				UID:          "txn",
+				UserUID:      "123",
+				Amount:       500,
+				BonusAmount:  500,
+				Wagering:     15000,
+				Currency:     "USD",
+				Type:         domain.TransactionTypeAdjustment,
+				Balance:      1500,
+				BonusBalance: 500,
+			}, nil)
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   500,
+			Bonus:    true,
+			Wagering: 15000,
+			Operator: "admin",
+			Reason:   "welcome bonus",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 500, res.BonusBalance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("grant bonus money without wagering", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   500,
+			Bonus:    true,
+			Operator: "admin",
+			Reason:   "welcome bonus",
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "BalanceAdjust", mock.Anything, mock.Anything)
+	})
+
+	t.Run("adjust balance below zero", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("BalanceAdjust", ctx, &domain.Transaction{
+				UserUID:  "123",
+				Amount:   -100000,
+				Currency: "USD",
+				Operator: "admin",
+				Reason:   "chargeback",
+			}).
+			Return(nil, errors.New("no documents"))
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   -100000,
+			Operator: "admin",
+			Reason:   "chargeback",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrAdjustment, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("adjust balance over deposit limit", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		limits := domain.GamingLimits{Deposit: domain.PeriodLimits{Day: 500}}
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Limits: map[string]domain.GamingLimits{"USD": limits}}, nil)
+
+		repoMock.
+			On("BalanceAdjust", ctx, &domain.Transaction{
+				UserUID:      "123",
+				Amount:       1000,
+				Currency:     "USD",
+				Operator:     "admin",
+				Reason:       "deposit",
+				GamingLimits: &limits,
+			}).
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrDepositLimitExceeded))
+
+		res, err := service.BalanceAdjust(ctx, &domain.BalanceAdjustReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   1000,
+			Operator: "admin",
+			Reason:   "deposit",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrDepositLimitExceeded, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
+
+func TestBalanceCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("create balance takes currency denomination", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceCreate", ctx, &domain.Balance{UserUID: "123", Currency: "USD", Denomination: 2}).
+			Return(nil)
+
+		res, err := service.BalanceCreate(ctx, "123", "USD")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, res.Denomination)
+		assert.Equal(t, 0, res.Amount)
+
+		repoMock.AssertExpectations(t)
+	})
+}
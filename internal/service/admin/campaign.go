@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	errorCampaignSource = "[service.admin.campaign]"
+)
+
+// CampaignCreate grants free rounds of the game to the user, the bets of the rounds are paid by the campaign
+func (s *Service) CampaignCreate(ctx context.Context, campaign *domain.Campaign) (*domain.Campaign, error) {
+	now := time.Now()
+	if campaign.GameUID == "" || campaign.Rounds <= 0 || campaign.BetValue <= 0 || !campaign.Win.IsValid() ||
+		!campaign.ExpiresAt.After(now) || campaign.WageringMultiplier < 0 {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrInvalidRequest)
+	}
+	// bonus money win without wagering requirement would be real money at once
+	if (campaign.Win == domain.CampaignWinBonus) != (campaign.WageringMultiplier > 0) {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	_, err := s.repo.UserGetByUID(ctx, campaign.UserUID)
+	if err != nil {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+	_, err = s.repo.CurrencyGetByCode(ctx, campaign.Currency)
+	if err != nil {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	campaign.UID = domain.GenUID()
+	campaign.RoundsLeft = campaign.Rounds
+	campaign.Status = domain.CampaignStatusActive
+	campaign.CreatedAt = now
+	err = s.repo.CampaignCreate(ctx, campaign)
+	if err != nil {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+
+	s.logger.Info("campaign granted", "uid", campaign.UID, "userUid", campaign.UserUID, "gameUid", campaign.GameUID, "rounds", campaign.Rounds, "operator", campaign.Operator)
+
+	return campaign, nil
+}
+
+func (s *Service) CampaignGet(ctx context.Context, uid string) (*domain.Campaign, error) {
+	campaign, err := s.repo.CampaignGetByUID(ctx, uid)
+	if err != nil {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrCampaignNotFound).Add(err)
+	}
+	return campaign, nil
+}
+
+func (s *Service) CampaignList(ctx context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error) {
+	campaigns, err := s.repo.CampaignList(ctx, filter)
+	if err != nil {
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return campaigns, nil
+}
+
+// CampaignCancel takes the rounds left away from the user, the wins of the rounds already played are still credited
+func (s *Service) CampaignCancel(ctx context.Context, uid, operator string) (*domain.Campaign, error) {
+	campaign, err := s.repo.CampaignCancel(ctx, uid)
+	if err != nil {
+		switch domain.AsError(err).Code {
+		case domain.ErrNotFound:
+			return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrCampaignNotFound).Add(err)
+		case domain.ErrCampaignUnavailable:
+			return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrCampaignUnavailable).Add(err)
+		}
+		return nil, domain.NewError(errorCampaignSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+
+	s.logger.Info("campaign cancelled", "uid", uid, "roundsLeft", campaign.RoundsLeft, "operator", operator)
+
+	return campaign, nil
+}
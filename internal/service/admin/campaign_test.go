@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCampaign(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("grant free rounds", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("UserGetByUID", ctx, "123").Return(&domain.User{UID: "123"}, nil)
+		repoMock.On("CurrencyGetByCode", ctx, "USD").Return(&domain.Currency{Code: "USD"}, nil)
+		repoMock.
+			On("CampaignCreate", ctx, mock.MatchedBy(func(c *domain.Campaign) bool {
+				return c.UID != "" && c.RoundsLeft == 10 && c.Status == domain.CampaignStatusActive && !c.CreatedAt.IsZero()
+			})).
+			Return(nil)
+
+		res, err := service.CampaignCreate(ctx, &domain.Campaign{
+			UserUID:            "123",
+			GameUID:            "game-1",
+			Currency:           "USD",
+			Rounds:             10,
+			BetValue:           20,
+			Win:                domain.CampaignWinBonus,
+			WageringMultiplier: 30,
+			Operator:           "support",
+			ExpiresAt:          time.Now().Add(24 * time.Hour),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, res.RoundsLeft)
+		assert.Equal(t, domain.CampaignStatusActive, res.Status)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("grant free rounds with bonus win without wagering", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.CampaignCreate(ctx, &domain.Campaign{
+			UserUID:   "123",
+			GameUID:   "game-1",
+			Currency:  "USD",
+			Rounds:    10,
+			BetValue:  20,
+			Win:       domain.CampaignWinBonus,
+			ExpiresAt: time.Now().Add(24 * time.Hour),
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "CampaignCreate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("grant expired free rounds", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.CampaignCreate(ctx, &domain.Campaign{
+			UserUID:   "123",
+			GameUID:   "game-1",
+			Currency:  "USD",
+			Rounds:    10,
+			BetValue:  20,
+			Win:       domain.CampaignWinReal,
+			ExpiresAt: time.Now().Add(-time.Hour),
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "CampaignCreate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancel finished campaign", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("CampaignCancel", ctx, "fr-1").
+			Return(nil, domain.NewError("test").SetCode(domain.ErrCampaignUnavailable))
+
+		res, err := service.CampaignCancel(ctx, "fr-1", "support")
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrCampaignUnavailable, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("cancel missing campaign", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("CampaignCancel", ctx, "fr-1").
+			Return(nil, domain.NewError("test").SetCode(domain.ErrNotFound))
+
+		res, err := service.CampaignCancel(ctx, "fr-1", "support")
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrCampaignNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorCurrencySource = "[service.admin.currency]"
+)
+
+func (s *Service) CurrencyGet(ctx context.Context, code string) (*domain.Currency, error) {
+	cur, err := s.repo.CurrencyGetByCode(ctx, code)
+	if err != nil {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+	return cur, nil
+}
+
+func (s *Service) CurrencyList(ctx context.Context) ([]domain.Currency, error) {
+	currencies, err := s.repo.CurrencyList(ctx)
+	if err != nil {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return currencies, nil
+}
+
+func (s *Service) CurrencyCreate(ctx context.Context, cur *domain.Currency) (*domain.Currency, error) {
+	if cur.Code == "" || cur.Denomination < 0 || cur.Denomination > domain.MaxDenomination || cur.MaxWin < 0 {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	err := s.repo.CurrencyCreate(ctx, cur)
+	if err != nil {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return cur, nil
+}
+
+func (s *Service) CurrencyUpdate(ctx context.Context, cur *domain.Currency) (*domain.Currency, error) {
+	if cur.Code == "" || cur.Denomination < 0 || cur.Denomination > domain.MaxDenomination || cur.MaxWin < 0 {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	err := s.repo.CurrencyUpdate(ctx, cur)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrUnknownCurrency).Add(err)
+		}
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return cur, nil
+}
+
+func (s *Service) CurrencyDelete(ctx context.Context, code string) error {
+	err := s.repo.CurrencyDelete(ctx, code)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return domain.NewError(errorCurrencySource).SetCode(domain.ErrUnknownCurrency).Add(err)
+		}
+		return domain.NewError(errorCurrencySource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	return nil
+}
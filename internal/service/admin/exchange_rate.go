@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"math"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	errorExchangeRateSource = "[service.admin.exchange_rate]"
+)
+
+func (s *Service) ExchangeRateList(ctx context.Context) ([]domain.ExchangeRate, error) {
+	rates, err := s.repo.ExchangeRateList(ctx)
+	if err != nil {
+		return nil, domain.NewError(errorExchangeRateSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return rates, nil
+}
+
+// ExchangeRateSave creates or replaces the rate of the currency pair, both currencies must be known
+func (s *Service) ExchangeRateSave(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	if err := s.exchangeRateValid(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	rate.UpdatedAt = time.Now()
+	err := s.repo.ExchangeRateSave(ctx, rate)
+	if err != nil {
+		return nil, domain.NewError(errorExchangeRateSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return rate, nil
+}
+
+// ExchangeRateImport checks all the rates before saving any of them, so the broken file doesn't update the table partially
+func (s *Service) ExchangeRateImport(ctx context.Context, rates []domain.ExchangeRate) ([]domain.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return nil, domain.NewError(errorExchangeRateSource).SetCode(domain.ErrInvalidRequest)
+	}
+	for i := range rates {
+		if err := s.exchangeRateValid(ctx, &rates[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for i := range rates {
+		rates[i].UpdatedAt = now
+		err := s.repo.ExchangeRateSave(ctx, &rates[i])
+		if err != nil {
+			return nil, domain.NewError(errorExchangeRateSource).SetCode(domain.ErrRepoUpdate).Add(err)
+		}
+	}
+
+	s.logger.Info("exchange rates imported", "count", len(rates))
+	return rates, nil
+}
+
+func (s *Service) ExchangeRateDelete(ctx context.Context, from, to string) error {
+	err := s.repo.ExchangeRateDelete(ctx, from, to)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return domain.NewError(errorExchangeRateSource).SetCode(domain.ErrNotFound).Add(err)
+		}
+		return domain.NewError(errorExchangeRateSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	return nil
+}
+
+func (s *Service) exchangeRateValid(ctx context.Context, rate *domain.ExchangeRate) error {
+	if rate.From == "" || rate.To == "" || rate.From == rate.To || !(rate.Rate > 0) || math.IsInf(rate.Rate, 0) {
+		return domain.NewError(errorExchangeRateSource).SetCode(domain.ErrInvalidRequest)
+	}
+	for _, code := range []string{rate.From, rate.To} {
+		if _, err := s.repo.CurrencyGetByCode(ctx, code); err != nil {
+			return domain.NewError(errorExchangeRateSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+)
+
+func TestExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("save exchange rate", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "EUR").Return(&domain.Currency{Code: "EUR"}, nil)
+		repoMock.On("CurrencyGetByCode", ctx, "USD").Return(&domain.Currency{Code: "USD"}, nil)
+		repoMock.
+			On("ExchangeRateSave", ctx, mock.MatchedBy(func(r *domain.ExchangeRate) bool {
+				return r.From == "EUR" && r.To == "USD" && r.Rate == 1.08 && !r.UpdatedAt.IsZero()
+			})).
+			Return(nil)
+
+		res, err := service.ExchangeRateSave(ctx, &domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.08})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1.08, res.Rate)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("save exchange rate of unknown currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "EUR").Return(nil, domain.NewError("test").SetCode(domain.ErrNotFound))
+
+		res, err := service.ExchangeRateSave(ctx, &domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.08})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrUnknownCurrency, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "ExchangeRateSave", mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("import rejects all rates when one is invalid", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "EUR").Return(&domain.Currency{Code: "EUR"}, nil)
+		repoMock.On("CurrencyGetByCode", ctx, "USD").Return(&domain.Currency{Code: "USD"}, nil)
+
+		res, err := service.ExchangeRateImport(ctx, []domain.ExchangeRate{
+			{From: "EUR", To: "USD", Rate: 1.08},
+			{From: "USD", To: "EUR", Rate: 0},
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "ExchangeRateSave", mock.Anything, mock.Anything)
+	})
+
+	t.Run("import saves every rate", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "EUR").Return(&domain.Currency{Code: "EUR"}, nil)
+		repoMock.On("CurrencyGetByCode", ctx, "USD").Return(&domain.Currency{Code: "USD"}, nil)
+		repoMock.On("ExchangeRateSave", ctx, mock.Anything).Return(nil).Twice()
+
+		res, err := service.ExchangeRateImport(ctx, []domain.ExchangeRate{
+			{From: "EUR", To: "USD", Rate: 1.08},
+			{From: "USD", To: "EUR", Rate: 0.92},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/netip"
+	"open-api-games/internal/domain"
+	"strings"
+)
+
+const (
+	errorProviderSource = "[service.admin.provider]"
+
+	// providerSecretBytes is the size of generated provider secret
+	providerSecretBytes = 32
+)
+
+func (s *Service) ProviderGet(ctx context.Context, uid string) (*domain.Provider, error) {
+	provider, err := s.repo.ProviderGetByUID(ctx, uid)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+	}
+	return provider, nil
+}
+
+func (s *Service) ProviderList(ctx context.Context) ([]domain.Provider, error) {
+	providers, err := s.repo.ProviderList(ctx)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return providers, nil
+}
+
+// ProviderCreate registers the provider, uid and secret are generated when they aren't provided,
+// requests are signed with hmac unless legacy scheme is opted in
+func (s *Service) ProviderCreate(ctx context.Context, provider *domain.Provider) (*domain.Provider, error) {
+	if provider.UID == "" {
+		provider.UID = domain.GenUID()
+	}
+	if provider.SignScheme == "" {
+		provider.SignScheme = domain.SignSchemeHMACSHA256
+	}
+	if provider.AmountFormat == "" {
+		provider.AmountFormat = domain.AmountFormatMinor
+	}
+	if len(provider.Secrets) == 0 {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrServer).Add(err)
+		}
+		provider.Secrets = []string{secret}
+	}
+
+	err := s.providerValidate(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.ProviderCreate(ctx, provider)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return provider, nil
+}
+
+// ProviderUpdate changes provider settings, secrets are kept and changed by rotation only,
+// sign scheme and amount format are kept when they aren't provided
+func (s *Service) ProviderUpdate(ctx context.Context, provider *domain.Provider) (*domain.Provider, error) {
+	current, err := s.repo.ProviderGetByUID(ctx, provider.UID)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+	}
+	provider.Secrets = current.Secrets
+	if provider.SignScheme == "" {
+		provider.SignScheme = current.SignScheme
+	}
+	if provider.AmountFormat == "" {
+		provider.AmountFormat = current.AmountFormat
+	}
+
+	return s.providerUpdate(ctx, provider)
+}
+
+func (s *Service) ProviderDelete(ctx context.Context, uid string) error {
+	err := s.repo.ProviderDelete(ctx, uid)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+		}
+		return domain.NewError(errorProviderSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	return nil
+}
+
+// ProviderRotateSecret makes the secret current while the previous one stays active until the rotation is finished,
+// the secret is generated when it isn't provided
+func (s *Service) ProviderRotateSecret(ctx context.Context, uid, secret string) (*domain.Provider, error) {
+	provider, err := s.repo.ProviderGetByUID(ctx, uid)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+	}
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrServer).Add(err)
+		}
+	}
+	provider.RotateSecret(secret)
+
+	return s.providerUpdate(ctx, provider)
+}
+
+// ProviderFinishRotation revokes all the secrets of the provider except the current one
+func (s *Service) ProviderFinishRotation(ctx context.Context, uid string) (*domain.Provider, error) {
+	provider, err := s.repo.ProviderGetByUID(ctx, uid)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+	}
+	if len(provider.Secrets) > 1 {
+		provider.Secrets = provider.Secrets[:1]
+	}
+
+	return s.providerUpdate(ctx, provider)
+}
+
+func (s *Service) providerUpdate(ctx context.Context, provider *domain.Provider) (*domain.Provider, error) {
+	err := s.providerValidate(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.ProviderUpdate(ctx, provider)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+		}
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return provider, nil
+}
+
+// providerValidate checks sign scheme, amount format, secrets count, allowed ips format and that allowed currencies are known
+func (s *Service) providerValidate(ctx context.Context, provider *domain.Provider) error {
+	if provider.UID == "" || provider.Name == "" {
+		return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	if provider.SignScheme != "" && !provider.SignScheme.IsValid() {
+		return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest)
+	}
+	if provider.AmountFormat != "" && !provider.AmountFormat.IsValid() {
+		return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	if len(provider.Secrets) == 0 || len(provider.Secrets) > domain.MaxProviderSecrets {
+		return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest)
+	}
+	for _, secret := range provider.Secrets {
+		if secret == "" {
+			return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest)
+		}
+	}
+
+	for _, ip := range provider.AllowedIPs {
+		var err error
+		if strings.Contains(ip, "/") {
+			_, err = netip.ParsePrefix(ip)
+		} else {
+			_, err = netip.ParseAddr(ip)
+		}
+		if err != nil {
+			return domain.NewError(errorProviderSource).SetCode(domain.ErrInvalidRequest).Add(err)
+		}
+	}
+
+	for _, code := range provider.Currencies {
+		_, err := s.repo.CurrencyGetByCode(ctx, code)
+		if err != nil {
+			return domain.NewError(errorProviderSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, providerSecretBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,187 @@
+package admin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+)
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("create provider generates uid and secret", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "USD").Return(&domain.Currency{Code: "USD"}, nil)
+		repoMock.
+			On("ProviderCreate", ctx, mock.MatchedBy(func(p *domain.Provider) bool {
+				return p.UID != "" && len(p.Secrets) == 1 && len(p.Secrets[0]) == 2*providerSecretBytes &&
+					p.SignScheme == domain.SignSchemeHMACSHA256 && p.AmountFormat == domain.AmountFormatMinor
+			})).
+			Return(nil)
+
+		res, err := service.ProviderCreate(ctx, &domain.Provider{Name: "provider", Enabled: true, Currencies: []string{"USD"}})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.UID)
+		assert.Len(t, res.Secrets, 1)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("create provider with invalid ip", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.ProviderCreate(ctx, &domain.Provider{
+			UID:        "p1",
+			Name:       "provider",
+			Secrets:    []string{"secret"},
+			AllowedIPs: []string{"10.0.0.300"},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("create provider with unknown sign scheme", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.ProviderCreate(ctx, &domain.Provider{
+			UID:        "p1",
+			Name:       "provider",
+			Secrets:    []string{"secret"},
+			SignScheme: "sha1",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("create provider with unknown amount format", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.ProviderCreate(ctx, &domain.Provider{
+			UID:          "p1",
+			Name:         "provider",
+			Secrets:      []string{"secret"},
+			AmountFormat: "float",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("create provider with unknown currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("CurrencyGetByCode", ctx, "XXX").Return(nil, domain.NewError("repo").SetCode(domain.ErrNotFound))
+
+		res, err := service.ProviderCreate(ctx, &domain.Provider{
+			UID:        "p1",
+			Name:       "provider",
+			Secrets:    []string{"secret"},
+			Currencies: []string{"XXX"},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrUnknownCurrency, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("update provider keeps secrets", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("ProviderGetByUID", ctx, "p1").
+			Return(&domain.Provider{UID: "p1", Name: "provider", Secrets: []string{"secret"}, Enabled: true}, nil)
+		repoMock.
+			On("ProviderUpdate", ctx, &domain.Provider{UID: "p1", Name: "renamed", Secrets: []string{"secret"}, Enabled: false}).
+			Return(nil)
+
+		res, err := service.ProviderUpdate(ctx, &domain.Provider{UID: "p1", Name: "renamed"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"secret"}, res.Secrets)
+		assert.False(t, res.Enabled)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rotate secret keeps previous one", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("ProviderGetByUID", ctx, "p1").
+			Return(&domain.Provider{UID: "p1", Name: "provider", Secrets: []string{"second", "first"}}, nil)
+		repoMock.
+			On("ProviderUpdate", ctx, &domain.Provider{UID: "p1", Name: "provider", Secrets: []string{"third", "second"}}).
+			Return(nil)
+
+		res, err := service.ProviderRotateSecret(ctx, "p1", "third")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"third", "second"}, res.Secrets)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("finish rotation revokes previous secret", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("ProviderGetByUID", ctx, "p1").
+			Return(&domain.Provider{UID: "p1", Name: "provider", Secrets: []string{"second", "first"}}, nil)
+		repoMock.
+			On("ProviderUpdate", ctx, &domain.Provider{UID: "p1", Name: "provider", Secrets: []string{"second"}}).
+			Return(nil)
+
+		res, err := service.ProviderFinishRotation(ctx, "p1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"second"}, res.Secrets)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rotate secret of unknown provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(nil, domain.NewError("repo").SetCode(domain.ErrNotFound))
+
+		res, err := service.ProviderRotateSecret(ctx, "p1", "")
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
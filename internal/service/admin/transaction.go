@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorTransactionSource = "[service.admin.transaction]"
+)
+
+func (s *Service) TransactionList(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, domain.NewError(errorTransactionSource).SetCode(domain.ErrInvalidRequest)
+	}
+	filter.Pagination = filter.Pagination.Normalize()
+
+	page, err := s.repo.TransactionList(ctx, filter)
+	if err != nil {
+		return nil, domain.NewError(errorTransactionSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return page, nil
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTransactionList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("list transactions caps page limit", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("TransactionList", ctx, domain.TransactionFilter{
+				UserUID:    "123",
+				Type:       domain.TransactionTypeAdjustment,
+				From:       from,
+				Pagination: domain.Pagination{Offset: 10, Limit: domain.MaxPageLimit},
+			}).
+			Return(&domain.TransactionPage{
+				Transactions: []domain.Transaction{{UID: "txn"}},
+				Total:        11,
+			}, nil)
+
+		res, err := service.TransactionList(ctx, domain.TransactionFilter{
+			UserUID:    "123",
+			Type:       domain.TransactionTypeAdjustment,
+			From:       from,
+			Pagination: domain.Pagination{Offset: 10, Limit: 10000},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 11, res.Total)
+		assert.Len(t, res.Transactions, 1)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("list transactions with inverted period", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.TransactionList(ctx, domain.TransactionFilter{
+			From: from,
+			To:   from.Add(-time.Hour),
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
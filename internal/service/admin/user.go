@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	errorUserSource = "[service.admin.user]"
+)
+
+func (s *Service) UserGet(ctx context.Context, uid string) (*domain.User, error) {
+	user, err := s.repo.UserGetByUID(ctx, uid)
+	if err != nil {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+	return user, nil
+}
+
+func (s *Service) UserList(ctx context.Context, page domain.Pagination) (*domain.UserPage, error) {
+	users, err := s.repo.UserList(ctx, page.Normalize())
+	if err != nil {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	return users, nil
+}
+
+// UserCreate registers the user, uid is generated when it isn't provided
+func (s *Service) UserCreate(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if user.Nick == "" || !gamingLimitsValid(user) {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrInvalidRequest)
+	}
+	if user.UID == "" {
+		user.UID = domain.GenUID()
+	}
+
+	err := s.repo.UserCreate(ctx, user)
+	if err != nil {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+	return user, nil
+}
+
+// UserUpdate replaces the user, self-exclusion and cool-off left out of the update are kept and the active ones
+// can only be extended, so the update can't let the excluded user back to the game
+func (s *Service) UserUpdate(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if user.UID == "" || user.Nick == "" || !gamingLimitsValid(user) {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrInvalidRequest)
+	}
+
+	current, err := s.repo.UserGetByUID(ctx, user.UID)
+	if err != nil {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+	now := time.Now()
+	var selfExcluded, coolOff bool
+	user.SelfExcludedUntil, selfExcluded = exclusionUntil(current.SelfExcludedUntil, user.SelfExcludedUntil, now)
+	user.CoolOffUntil, coolOff = exclusionUntil(current.CoolOffUntil, user.CoolOffUntil, now)
+	if !selfExcluded || !coolOff {
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrExclusionShortened)
+	}
+
+	err = s.repo.UserUpdate(ctx, user)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return nil, domain.NewError(errorUserSource).SetCode(domain.ErrUserNotFound).Add(err)
+		}
+		return nil, domain.NewError(errorUserSource).SetCode(domain.ErrRepoUpdate).Add(err)
+	}
+	return user, nil
+}
+
+// UserDelete removes the user, users with money on any balance can't be removed
+func (s *Service) UserDelete(ctx context.Context, uid string) error {
+	balances, err := s.repo.BalanceListByUserUID(ctx, uid)
+	if err != nil {
+		return domain.NewError(errorUserSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+	for _, balance := range balances {
+		if balance.Amount != 0 {
+			return domain.NewError(errorUserSource).SetCode(domain.ErrUserHasBalance)
+		}
+	}
+
+	err = s.repo.UserDelete(ctx, uid)
+	if err != nil {
+		if domain.AsError(err).Code == domain.ErrNotFound {
+			return domain.NewError(errorUserSource).SetCode(domain.ErrUserNotFound).Add(err)
+		}
+		return domain.NewError(errorUserSource).SetCode(domain.ErrRepoDelete).Add(err)
+	}
+	return nil
+}
+
+// exclusionUntil returns the end of the exclusion after the update, the exclusion left out of the update is kept,
+// false means the update shortens the active exclusion
+func exclusionUntil(current, requested, now time.Time) (time.Time, bool) {
+	if requested.IsZero() {
+		return current, true
+	}
+	if now.Before(current) && requested.Before(current) {
+		return current, false
+	}
+	return requested, true
+}
+
+// gamingLimitsValid checks that no limit of the user is negative
+func gamingLimitsValid(user *domain.User) bool {
+	if user.SessionLimit < 0 || user.RealityCheckInterval < 0 {
+		return false
+	}
+	for _, l := range user.Limits {
+		if !l.Loss.IsValid() || !l.Wager.IsValid() || !l.Deposit.IsValid() {
+			return false
+		}
+	}
+	return true
+}
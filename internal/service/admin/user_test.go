@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/admin/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("create user generates uid", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserCreate", ctx, mock.MatchedBy(func(u *domain.User) bool {
+				return u.UID != "" && u.Nick == "test"
+			})).
+			Return(nil)
+
+		res, err := service.UserCreate(ctx, &domain.User{Nick: "test"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.UID)
+		assert.Equal(t, "test", res.Nick)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("create user without nick", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.UserCreate(ctx, &domain.User{UID: "123"})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("update user with negative limit", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		res, err := service.UserUpdate(ctx, &domain.User{
+			UID:    "123",
+			Nick:   "test",
+			Limits: map[string]domain.GamingLimits{"USD": {Loss: domain.PeriodLimits{Week: -100}}},
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "UserUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update unknown user", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(nil, domain.NewError("repo").SetCode(domain.ErrNotFound))
+
+		res, err := service.UserUpdate(ctx, &domain.User{UID: "123", Nick: "test"})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrUserNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+		repoMock.AssertNotCalled(t, "UserUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update user keeps exclusion left out", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		excludedUntil := time.Now().Add(24 * time.Hour).UTC()
+		coolOffUntil := time.Now().Add(-time.Hour).UTC()
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", SelfExcludedUntil: excludedUntil, CoolOffUntil: coolOffUntil}, nil)
+
+		repoMock.
+			On("UserUpdate", ctx, &domain.User{UID: "123", Nick: "renamed", SelfExcludedUntil: excludedUntil, CoolOffUntil: coolOffUntil}).
+			Return(nil)
+
+		res, err := service.UserUpdate(ctx, &domain.User{UID: "123", Nick: "renamed"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, excludedUntil, res.SelfExcludedUntil)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("update user can't shorten active exclusion", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", CoolOffUntil: time.Now().Add(24 * time.Hour)}, nil)
+
+		res, err := service.UserUpdate(ctx, &domain.User{UID: "123", Nick: "test", CoolOffUntil: time.Now().Add(time.Hour)})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrExclusionShortened, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+		repoMock.AssertNotCalled(t, "UserUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update user extends active exclusion and replaces expired one", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		now := time.Now().UTC()
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", SelfExcludedUntil: now.Add(time.Hour), CoolOffUntil: now.Add(-time.Hour)}, nil)
+
+		repoMock.
+			On("UserUpdate", ctx, &domain.User{UID: "123", Nick: "test", SelfExcludedUntil: now.Add(48 * time.Hour), CoolOffUntil: now.Add(-2 * time.Hour)}).
+			Return(nil)
+
+		_, err := service.UserUpdate(ctx, &domain.User{
+			UID:               "123",
+			Nick:              "test",
+			SelfExcludedUntil: now.Add(48 * time.Hour),
+			CoolOffUntil:      now.Add(-2 * time.Hour),
+		})
+
+		assert.NoError(t, err)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("delete user with money", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{
+				{UserUID: "123", Currency: "EUR"},
+				{UserUID: "123", Currency: "USD", Amount: 100},
+			}, nil)
+
+		err := service.UserDelete(ctx, "123")
+
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrUserHasBalance, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("delete user with empty balances", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Currency: "USD"}}, nil)
+
+		repoMock.
+			On("UserDelete", ctx, "123").
+			Return(nil)
+
+		err := service.UserDelete(ctx, "123")
+
+		assert.NoError(t, err)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("list users applies default page", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger)
+
+		repoMock.
+			On("UserList", ctx, domain.Pagination{Offset: 0, Limit: domain.DefaultPageLimit}).
+			Return(&domain.UserPage{Users: []domain.User{{UID: "123"}}, Total: 1}, nil)
+
+		res, err := service.UserList(ctx, domain.Pagination{Offset: -1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Total)
+
+		repoMock.AssertExpectations(t)
+	})
+}
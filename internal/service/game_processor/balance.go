@@ -20,24 +20,39 @@ func (s *Service) Balance(ctx context.Context, req *domain.ProcessBalanceReq) (*
 	if err != nil {
 		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrSessionNotFound).Add(err)
 	}
+	if err = sessionOwned(session, req.ProviderUID, errorBalanceSource); err != nil {
+		return nil, err
+	}
 
 	user, err := s.repo.UserGetByUID(ctx, session.UserUID)
 	if err != nil {
 		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrUserNotFound).Add(err)
 	}
 
-	balance, err := s.repo.BalanceGetByUserUIDAndCurrency(ctx, user.UID, req.Currency)
-	if err != nil {
-		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrBalanceNotFound).Add(err)
+	wlt := s.walletGet(ctx, nil, user.UID, cur, cur.Denomination)
+	if wlt.balance == nil {
+		return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrBalanceNotFound)
+	}
+
+	jackpots := s.sessionJackpots(ctx, session, req.Currency)
+	jpKey := ""
+	if len(jackpots) > 0 {
+		jpKey = jackpots[0].Key
 	}
 
+	amount, bonus := wlt.toGame(wlt.balance.Amount), wlt.toGame(wlt.balance.Bonus)
 	return &domain.ProcessBalanceRes{
 		UserUID:      user.UID,
 		UserNick:     user.Nick,
-		Amount:       balance.Amount,
-		Currency:     balance.Currency,
-		Denomination: balance.Denomination,
-		MaxWin:       0,  // TODO: implement MaxWin
-		JpKey:        "", // TODO: implement JpKey
+		Amount:       amount,
+		RealAmount:   amount - bonus,
+		BonusAmount:  bonus,
+		Wagering:     wlt.toGame(wlt.balance.Wagering),
+		Currency:     cur.Code,
+		Denomination: cur.Denomination,
+		MaxWin:       domain.MaxWinLimit(cur, user, session),
+		JpKey:        jpKey,
+		Jackpots:     jackpots,
+		RealityCheck: s.realityCheck(session, user, wlt),
 	}, nil
 }
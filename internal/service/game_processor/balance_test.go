@@ -9,6 +9,7 @@ import (
 	"open-api-games/internal/service/game_processor/mocks"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestBalance(t *testing.T) {
@@ -16,10 +17,11 @@ func TestBalance(t *testing.T) {
 
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
 
 	t.Run("get balance success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("CurrencyGetByCode", ctx, "USD").
@@ -61,13 +63,154 @@ func TestBalance(t *testing.T) {
 		assert.Equal(t, 100, res.Amount)
 		assert.Equal(t, "USD", res.Currency)
 		assert.Equal(t, 2, res.Denomination)
+		assert.Equal(t, 0, res.MaxWin)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("get balance with bonus money", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{UserUID: "123", UID: "123"}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{
+				Amount:       1000,
+				Bonus:        300,
+				Wagering:     9000,
+				Denomination: 2,
+				Currency:     "USD",
+			}, nil)
+
+		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, res.Amount)
+		assert.Equal(t, 700, res.RealAmount)
+		assert.Equal(t, 300, res.BonusAmount)
+		assert.Equal(t, 9000, res.Wagering)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("get balance max win of user", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+				MaxWin:       1000,
+			}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:    "123",
+				Nick:   "test",
+				MaxWin: map[string]int{"USD": 500, "EUR": 300},
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{
+				Amount:       100,
+				Denomination: 2,
+				Currency:     "USD",
+			}, nil)
+
+		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 500, res.MaxWin)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("get balance with session game jackpots", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+				GameUID: "game",
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{
+				Amount:       100,
+				Denomination: 2,
+				Currency:     "USD",
+			}, nil)
+
+		repoMock.
+			On("JackpotListActiveByGame", ctx, "game", "USD").
+			Return([]domain.Jackpot{
+				{Key: "game-mini", Currency: "USD", GameUID: "game", Amount: 1000, IsActive: true},
+				{Key: "mega", Currency: "USD", Amount: 100000, IsActive: true},
+			}, nil)
+
+		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "game-mini", res.JpKey)
+		assert.Len(t, res.Jackpots, 2)
+		assert.Equal(t, 100000, res.Jackpots[1].Amount)
 
 		repoMock.AssertExpectations(t)
 	})
 
 	t.Run("get balance unknown currency", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("CurrencyGetByCode", ctx, "USD").
@@ -86,7 +229,7 @@ func TestBalance(t *testing.T) {
 
 	t.Run("get balance session not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("CurrencyGetByCode", ctx, "USD").
@@ -112,7 +255,7 @@ func TestBalance(t *testing.T) {
 
 	t.Run("get balance user not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("CurrencyGetByCode", ctx, "USD").
@@ -145,7 +288,7 @@ func TestBalance(t *testing.T) {
 
 	t.Run("get balance not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("CurrencyGetByCode", ctx, "USD").
@@ -172,6 +315,10 @@ func TestBalance(t *testing.T) {
 			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
 			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
 
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{}, nil)
+
 		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
 			GameSessionUID: "123",
 			Currency:       "USD",
@@ -183,4 +330,98 @@ func TestBalance(t *testing.T) {
 		repoMock.AssertExpectations(t)
 	})
 
+	t.Run("get balance converted from wallet currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{
+				Code:         "EUR",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123", mock.Anything).
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(&domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.25}, nil)
+
+		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
+			GameSessionUID: "123",
+			Currency:       "EUR",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 800, res.Amount)
+		assert.Equal(t, "EUR", res.Currency)
+		assert.Equal(t, 2, res.Denomination)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("get balance with due reality check", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		now := time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:              "123",
+				UserUID:          "123",
+				CreatedAt:        now.Add(-90 * time.Minute),
+				RealityCheckedAt: now.Add(-time.Hour),
+				TotalBet:         1000,
+				TotalWin:         1200,
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", RealityCheckInterval: time.Hour}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{Amount: 100, Denomination: 2, Currency: "USD"}, nil)
+
+		res, err := service.Balance(ctx, &domain.ProcessBalanceReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.RealityCheck{
+			Elapsed:      90 * time.Minute,
+			NetResult:    200,
+			Currency:     "USD",
+			Denomination: 2,
+		}, res.RealityCheck)
+
+		repoMock.AssertExpectations(t)
+	})
 }
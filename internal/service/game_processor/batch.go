@@ -0,0 +1,131 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorBatchSource = "[service.game_processor.batch]"
+)
+
+// Batch processes debits, credits and rollbacks of the batch in the request order, results are reported per item,
+// atomic batch is stored in one repository transaction and the error of the item which failed it is returned as well
+func (s *Service) Batch(ctx context.Context, req *domain.ProcessBatchReq) (*domain.ProcessBatchRes, error) {
+	if req.Mode == domain.ProcessBatchModeBestEffort {
+		return s.batchBestEffort(ctx, req), nil
+	}
+	return s.batchAtomic(ctx, req)
+}
+
+// batchBestEffort processes every item the same way as the separate request
+func (s *Service) batchBestEffort(ctx context.Context, req *domain.ProcessBatchReq) *domain.ProcessBatchRes {
+	res := &domain.ProcessBatchRes{Mode: req.Mode, Items: make([]domain.ProcessBatchItemRes, len(req.Items))}
+	for i, item := range req.Items {
+		result, err := s.process(ctx, item.Type, &req.Items[i].Data)
+		res.Items[i] = domain.ProcessBatchItemRes{Type: item.Type, Result: result, Error: err}
+	}
+	return res
+}
+
+// batchAtomic prepares every item before storing them all at once, so nothing is stored when any item fails,
+// the items are prepared in order and the credits count the win of the same round credited by the earlier items
+// to max win, gaming limits and free rounds are checked by the repository for every item within the batch transaction
+func (s *Service) batchAtomic(ctx context.Context, req *domain.ProcessBatchReq) (*domain.ProcessBatchRes, error) {
+	ops := make([]*operation, len(req.Items))
+	pendingWin := make(map[string]int)
+	for i, item := range req.Items {
+		data := &req.Items[i].Data
+		op, err := s.prepare(ctx, item.Type, data, pendingWin[data.BetUID])
+		if err != nil {
+			return batchAborted(req, i, err), err
+		}
+		if op.txnType == domain.TransactionTypeCredit && data.BetUID != "" && data.JpKey == "" {
+			pendingWin[data.BetUID] += op.amount
+		}
+		ops[i] = op
+	}
+
+	drafts := make([]*domain.Transaction, len(ops))
+	for i, op := range ops {
+		drafts[i] = op.batchDraft()
+	}
+
+	txns, failed, err := s.repo.TransactionBatch(ctx, drafts)
+	if err != nil {
+		if failed < 0 || failed >= len(ops) {
+			err = domain.NewError(errorBatchSource).SetCode(domain.ErrProcessingRequest).Add(err)
+			return batchAborted(req, -1, err), err
+		}
+		err = storeError(ops[failed].txnType, err)
+		return batchAborted(req, failed, err), err
+	}
+
+	res := &domain.ProcessBatchRes{Mode: req.Mode, Items: make([]domain.ProcessBatchItemRes, len(ops))}
+	for i, op := range ops {
+		result, err := s.result(ctx, op, txns[i])
+		res.Items[i] = domain.ProcessBatchItemRes{Type: op.txnType, Result: result, Error: err}
+	}
+	return res, nil
+}
+
+// batchAborted reports the error of the failed item, the rest of the items are not processed
+func batchAborted(req *domain.ProcessBatchReq, failed int, err error) *domain.ProcessBatchRes {
+	res := &domain.ProcessBatchRes{Mode: req.Mode, Items: make([]domain.ProcessBatchItemRes, len(req.Items))}
+	for i, item := range req.Items {
+		res.Items[i] = domain.ProcessBatchItemRes{Type: item.Type, Error: domain.NewError(errorBatchSource).SetCode(domain.ErrBatchAborted)}
+		if i == failed {
+			res.Items[i].Error = err
+		}
+	}
+	return res
+}
+
+// process handles the item as the separate debit, credit or rollback request
+func (s *Service) process(ctx context.Context, txnType domain.TransactionType, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error) {
+	switch txnType {
+	case domain.TransactionTypeDebit:
+		return s.Debit(ctx, req)
+	case domain.TransactionTypeCredit:
+		return s.Credit(ctx, req)
+	case domain.TransactionTypeRollback:
+		return s.Rollback(ctx, req)
+	}
+	return nil, domain.NewError(errorBatchSource).SetCode(domain.ErrInvalidTransactionType)
+}
+
+// prepare checks the item and makes its draft without storing it, pendingWin is the win of the item round
+// credited by the earlier items of the batch
+func (s *Service) prepare(ctx context.Context, txnType domain.TransactionType, req *domain.ProcessDebitCreditRollbackReq, pendingWin int) (*operation, error) {
+	switch txnType {
+	case domain.TransactionTypeDebit:
+		return s.debitPrepare(ctx, req)
+	case domain.TransactionTypeCredit:
+		return s.creditPrepare(ctx, req, pendingWin)
+	case domain.TransactionTypeRollback:
+		return s.rollbackPrepare(ctx, req)
+	}
+	return nil, domain.NewError(errorBatchSource).SetCode(domain.ErrInvalidTransactionType)
+}
+
+// storeError keeps the codes the separate request of the item type reports on failed storing
+func storeError(txnType domain.TransactionType, err error) *domain.Error {
+	switch txnType {
+	case domain.TransactionTypeDebit:
+		return debitError(err)
+	case domain.TransactionTypeCredit:
+		return creditError(err)
+	}
+	return rollbackError(err)
+}
+
+// result makes the response to the item from its stored transaction
+func (s *Service) result(ctx context.Context, op *operation, txn *domain.Transaction) (*domain.ProcessDebitCreditRollbackRes, error) {
+	switch op.txnType {
+	case domain.TransactionTypeDebit:
+		return s.debitResult(ctx, op, txn)
+	case domain.TransactionTypeCredit:
+		return s.creditResult(ctx, op, txn)
+	}
+	return s.rollbackResult(op, txn), nil
+}
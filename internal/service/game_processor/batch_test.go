@@ -0,0 +1,196 @@
+package game_processor
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/game_processor/mocks"
+	"os"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
+
+	items := []domain.ProcessBatchItemReq{
+		{Type: domain.TransactionTypeDebit, Data: domain.ProcessDebitCreditRollbackReq{TransactionUID: "d1", UserUID: "123", Currency: "USD", Amount: 100}},
+		{Type: domain.TransactionTypeCredit, Data: domain.ProcessDebitCreditRollbackReq{TransactionUID: "c1", UserUID: "123", Currency: "USD", Amount: 300}},
+	}
+	drafts := []*domain.Transaction{
+		{ProviderTransactionUID: "d1", UserUID: "123", Amount: 100, Currency: "USD", BonusOrder: domain.BonusOrderRealFirst, Type: domain.TransactionTypeDebit},
+		{ProviderTransactionUID: "c1", UserUID: "123", Amount: 300, Currency: "USD", Type: domain.TransactionTypeCredit},
+	}
+
+	userAndCurrency := func(repoMock *mocks.Repository) {
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+	}
+
+	t.Run("atomic batch success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("TransactionBatch", ctx, drafts).
+			Return([]*domain.Transaction{
+				{UID: "1", UserUID: "123", Amount: 100, Balance: 900, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeDebit},
+				{UID: "2", UserUID: "123", Amount: 300, Balance: 1200, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeCredit},
+			}, -1, nil)
+
+		res, err := service.Batch(ctx, &domain.ProcessBatchReq{Mode: domain.ProcessBatchModeAtomic, Items: items})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 2)
+		assert.NoError(t, res.Items[0].Error)
+		assert.Equal(t, "1", res.Items[0].Result.TransactionUID)
+		assert.Equal(t, 900, res.Items[0].Result.Balance)
+		assert.NoError(t, res.Items[1].Error)
+		assert.Equal(t, "2", res.Items[1].Result.TransactionUID)
+		assert.Equal(t, 1200, res.Items[1].Result.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("atomic batch fails on item", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("TransactionBatch", ctx, drafts).
+			Return(nil, 0, domain.NewError("test").SetCode(domain.ErrDecrement))
+
+		res, err := service.Batch(ctx, &domain.ProcessBatchReq{Mode: domain.ProcessBatchModeAtomic, Items: items})
+
+		assert.Equal(t, domain.ErrDecrement, domain.AsError(err).Code)
+		assert.Len(t, res.Items, 2)
+		assert.Equal(t, domain.ErrDecrement, domain.AsError(res.Items[0].Error).Code)
+		assert.Equal(t, domain.ErrBatchAborted, domain.AsError(res.Items[1].Error).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("atomic batch fails on prepare", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(nil, errors.New("not found"))
+
+		res, err := service.Batch(ctx, &domain.ProcessBatchReq{Mode: domain.ProcessBatchModeAtomic, Items: items})
+
+		assert.Equal(t, domain.ErrUserNotFound, domain.AsError(err).Code)
+		assert.Equal(t, domain.ErrUserNotFound, domain.AsError(res.Items[0].Error).Code)
+		assert.Equal(t, domain.ErrBatchAborted, domain.AsError(res.Items[1].Error).Code)
+
+		repoMock.AssertNotCalled(t, "TransactionBatch", mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("best effort batch reports item errors", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "d1",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrDecrement))
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "c1",
+				UserUID:                "123",
+				Amount:                 300,
+				Currency:               "USD",
+			}).
+			Return(&domain.Transaction{UID: "2", UserUID: "123", Amount: 300, Balance: 1300, Currency: "USD", Denomination: 2}, nil)
+
+		res, err := service.Batch(ctx, &domain.ProcessBatchReq{Mode: domain.ProcessBatchModeBestEffort, Items: items})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ErrDecrement, domain.AsError(res.Items[0].Error).Code)
+		assert.Nil(t, res.Items[0].Result)
+		assert.NoError(t, res.Items[1].Error)
+		assert.Equal(t, 1300, res.Items[1].Result.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("atomic batch limits max win across two credits", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2, MaxWin: 500}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "r1").
+			Return(&domain.Round{UID: "r1", UserUID: "123", Currency: "USD", Status: domain.RoundStatusOpen}, nil)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", mock.Anything, domain.TransactionTypeCredit).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("TransactionBatch", ctx, []*domain.Transaction{
+				{ProviderTransactionUID: "c1", UserUID: "123", RoundUID: "r1", Amount: 300, Currency: "USD", Type: domain.TransactionTypeCredit},
+				{ProviderTransactionUID: "c2", UserUID: "123", RoundUID: "r1", Amount: 200, CappedAmount: 100, Currency: "USD", Type: domain.TransactionTypeCredit},
+			}).
+			Return([]*domain.Transaction{
+				{UID: "1", UserUID: "123", Amount: 300, Balance: 1300, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeCredit},
+				{UID: "2", UserUID: "123", Amount: 200, CappedAmount: 100, Balance: 1500, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeCredit},
+			}, -1, nil)
+
+		res, err := service.Batch(ctx, &domain.ProcessBatchReq{Mode: domain.ProcessBatchModeAtomic, Items: []domain.ProcessBatchItemReq{
+			{Type: domain.TransactionTypeCredit, Data: domain.ProcessDebitCreditRollbackReq{TransactionUID: "c1", UserUID: "123", BetUID: "r1", Currency: "USD", Amount: 300}},
+			{Type: domain.TransactionTypeCredit, Data: domain.ProcessDebitCreditRollbackReq{TransactionUID: "c2", UserUID: "123", BetUID: "r1", Currency: "USD", Amount: 300}},
+		}})
+
+		assert.NoError(t, err)
+		assert.NoError(t, res.Items[0].Error)
+		assert.Equal(t, 300, res.Items[0].Result.Amount)
+		assert.NoError(t, res.Items[1].Error)
+		assert.Equal(t, 200, res.Items[1].Result.Amount)
+
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,53 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+// campaignGet finds the free rounds campaign of the user in the currency
+func (s *Service) campaignGet(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, userUid, source string) (*domain.Campaign, error) {
+	campaign, err := s.repo.CampaignGetByUID(ctx, req.CampaignUID)
+	if err != nil {
+		return nil, domain.NewError(source).SetCode(domain.ErrCampaignNotFound).Add(err)
+	}
+	if campaign.UserUID != userUid || campaign.Currency != req.Currency {
+		return nil, domain.NewError(source).SetCode(domain.ErrCampaignNotFound)
+	}
+	return campaign, nil
+}
+
+// campaignDebit checks that the free round can be played in the session game, the repository takes the round
+// when the debit is booked, replayed debit of the round already taken is let through to be answered
+// with the stored transaction
+func (s *Service) campaignDebit(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, userUid string, session *domain.Session) error {
+	err := s.campaignCheck(ctx, req, userUid, session)
+	if err != nil && s.debitReplayed(ctx, req.ProviderUID, req.TransactionUID) {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) campaignCheck(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, userUid string, session *domain.Session) error {
+	campaign, err := s.campaignGet(ctx, req, userUid, errorDebitSource)
+	if err != nil {
+		return err
+	}
+	if session != nil && session.GameUID != "" && campaign.GameUID != session.GameUID {
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrCampaignNotFound)
+	}
+	if !campaign.IsAvailable(s.now()) {
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrCampaignUnavailable)
+	}
+	return nil
+}
+
+// campaignWagering returns the wagering requirement of the free round win, the win of the round already played
+// is credited even when the campaign is over
+func (s *Service) campaignWagering(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, userUid string, amount int) (int, error) {
+	campaign, err := s.campaignGet(ctx, req, userUid, errorCreditSource)
+	if err != nil {
+		return 0, err
+	}
+	return campaign.Wagering(amount), nil
+}
@@ -10,12 +10,33 @@ const (
 )
 
 func (s *Service) Credit(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error) {
+	op, err := s.creditPrepare(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.repo.BalanceIncrementByUserUIDAndCurrency(ctx, op.draft)
+	if err != nil {
+		return nil, creditError(err)
+	}
+
+	return s.creditResult(ctx, op, txn)
+}
+
+// creditPrepare checks the session, user and currency of the credit and makes the transaction draft limited by max win,
+// pendingWin is the win of the round credited by the earlier items of the batch which isn't stored yet
+func (s *Service) creditPrepare(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, pendingWin int) (*operation, error) {
+	var session *domain.Session
 	userUid := req.UserUID
 	if req.GameSessionUID != "" {
-		session, err := s.repo.SessionGetByUID(ctx, req.GameSessionUID)
+		var err error
+		session, err = s.repo.SessionGetByUID(ctx, req.GameSessionUID)
 		if err != nil {
 			return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrSessionNotFound).Add(err)
 		}
+		if err = sessionOwned(session, req.ProviderUID, errorCreditSource); err != nil {
+			return nil, err
+		}
 		userUid = session.UserUID
 	}
 
@@ -28,18 +49,125 @@ func (s *Service) Credit(ctx context.Context, req *domain.ProcessDebitCreditRoll
 	if err != nil || cur == nil {
 		return nil, domain.NewError(errorCreditSource).SetCode(domain.ErrUnknownCurrency).Add(err)
 	}
-
-	txn, err := s.repo.BalanceIncrementByUserUIDAndCurrency(ctx, userUid, req.Currency, req.Amount)
+	requested, err := denominate(req, cur, errorCreditSource)
 	if err != nil {
-		return nil, domain.NewError(errorCreditSource).SetCode(domain.ErrIncrement).Add(err)
+		return nil, err
 	}
 
-	return &domain.ProcessDebitCreditRollbackRes{
-		TransactionUID: txn.UID,
-		UserNick:       user.Nick,
-		Amount:         txn.Amount,
-		Currency:       txn.Currency,
-		Denomination:   txn.Denomination,
-		MaxWin:         0, // TODO: implement MaxWin
+	wlt := s.walletGet(ctx, session, userUid, cur, gameDenomination(req, cur))
+	maxWin := domain.MaxWinLimit(cur, user, session)
+	amount := requested
+	if req.JpKey == "" {
+		// jackpot payouts are not limited by max win
+		amount, err = s.maxWinAmount(ctx, req, wlt, requested, maxWin, pendingWin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	draft := &domain.Transaction{
+		ProviderUID:            req.ProviderUID,
+		ProviderTransactionUID: req.TransactionUID,
+		UserUID:                userUid,
+		SessionUID:             req.GameSessionUID,
+		RoundUID:               req.BetUID,
+		Amount:                 amount,
+		CappedAmount:           requested - amount,
+		Currency:               req.Currency,
+		JackpotKey:             req.JpKey,
+		CampaignUID:            req.CampaignUID,
+		Meta:                   transactionMeta(req),
+	}
+	wlt.exchange(draft, requested, false)
+	if req.CampaignUID != "" {
+		draft.Wagering, err = s.campaignWagering(ctx, req, userUid, draft.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &operation{
+		txnType:   domain.TransactionTypeCredit,
+		req:       req,
+		session:   session,
+		user:      user,
+		maxWin:    maxWin,
+		requested: requested,
+		wallet:    wlt,
+		amount:    amount,
+		draft:     draft,
 	}, nil
 }
+
+// creditError keeps business error codes of the failed credit, the rest is reported as credit failure
+func creditError(err error) *domain.Error {
+	switch code := domain.AsError(err).Code; code {
+	case domain.ErrTransactionRolledBack, domain.ErrRoundClosed, domain.ErrRoundUser, domain.ErrJackpotPayout, domain.ErrTransactionConflict:
+		return domain.NewError(errorCreditSource).SetCode(code).Add(err)
+	}
+	return domain.NewError(errorCreditSource).SetCode(domain.ErrIncrement).Add(err)
+}
+
+// creditResult checks the stored transaction against the request and makes the response
+func (s *Service) creditResult(ctx context.Context, op *operation, txn *domain.Transaction) (*domain.ProcessDebitCreditRollbackRes, error) {
+	// replayed provider transaction must match the original one
+	if txn.UserUID != op.draft.UserUID || txn.RequestedAmount() != op.requested || txn.RequestedCurrency() != op.req.Currency {
+		return nil, domain.NewError(errorCreditSource).SetCode(domain.ErrTransactionConflict)
+	}
+
+	s.sessionTouch(ctx, op.session)
+
+	return op.wallet.response(op, txn, op.amount), nil
+}
+
+// replayedWin is the amount of the stored credit the request replays, it's already a part of the round total win
+// and must not limit its own replay
+func (s *Service) replayedWin(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) int {
+	if req.TransactionUID == "" {
+		return 0
+	}
+	txn, err := s.repo.TransactionGetByProviderUID(ctx, req.ProviderUID, req.TransactionUID, domain.TransactionTypeCredit)
+	if err != nil || txn.RoundUID != req.BetUID || txn.IsRolledBack() {
+		return 0
+	}
+	return txn.Amount
+}
+
+// maxWinAmount returns the part of the win amount allowed by max win limit, the limit applies to the whole round
+// when the win is a part of it, depending on the mode the exceeding win is capped or rejected,
+// the round win booked to the converted wallet is compared in the game currency
+func (s *Service) maxWinAmount(
+	ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, wlt *wallet, amount, maxWin, pendingWin int,
+) (int, error) {
+	if maxWin == 0 {
+		return amount, nil
+	}
+
+	alreadyWon := pendingWin
+	if req.BetUID != "" {
+		round, err := s.repo.RoundGetByProviderUID(ctx, req.ProviderUID, req.BetUID)
+		if err == nil {
+			alreadyWon += wlt.fromWallet(round.TotalWin-s.replayedWin(ctx, req), round.Currency)
+		}
+	}
+
+	allowed := max(maxWin-alreadyWon, 0)
+	if amount <= allowed {
+		return amount, nil
+	}
+
+	s.logger.Warn(
+		"win exceeds max win limit",
+		"transactionUid", req.TransactionUID,
+		"betUid", req.BetUID,
+		"amount", amount,
+		"allowed", allowed,
+		"maxWin", maxWin,
+		"mode", s.maxWinMode,
+	)
+	if s.maxWinMode == domain.MaxWinModeReject {
+		return 0, domain.NewError(errorCreditSource).SetCode(domain.ErrMaxWinExceeded)
+	}
+
+	return allowed, nil
+}
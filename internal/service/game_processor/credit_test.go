@@ -3,11 +3,13 @@ package game_processor
 import (
 	"context"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"log/slog"
 	"open-api-games/internal/domain"
 	"open-api-games/internal/service/game_processor/mocks"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestCredit(t *testing.T) {
@@ -15,10 +17,11 @@ func TestCredit(t *testing.T) {
 
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
 
 	t.Run("credit by session success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("SessionGetByUID", ctx, "123").
@@ -27,6 +30,10 @@ func TestCredit(t *testing.T) {
 				UID:     "123",
 			}, nil)
 
+		repoMock.
+			On("SessionTouch", ctx, "123", mock.Anything, mock.Anything).
+			Return(nil)
+
 		repoMock.
 			On("UserGetByUID", ctx, "123").
 			Return(&domain.User{
@@ -42,9 +49,19 @@ func TestCredit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceIncrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				SessionUID: "123",
+				Amount:     100,
+				Currency:   "USD",
+			}).
 			Return(&domain.Transaction{
 				UID:          "123",
+				UserUID:      "123",
 				Amount:       100,
 				Currency:     "USD",
 				Denomination: 2,
@@ -70,7 +87,7 @@ func TestCredit(t *testing.T) {
 
 	t.Run("credit by user success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -87,9 +104,18 @@ func TestCredit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceIncrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:  "123",
+				Amount:   100,
+				Currency: "USD",
+			}).
 			Return(&domain.Transaction{
 				UID:          "123",
+				UserUID:      "123",
 				Amount:       100,
 				Currency:     "USD",
 				Denomination: 2,
@@ -115,7 +141,7 @@ func TestCredit(t *testing.T) {
 
 	t.Run("credit by session not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("SessionGetByUID", ctx, "123").
@@ -135,7 +161,7 @@ func TestCredit(t *testing.T) {
 
 	t.Run("credit by user not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -155,7 +181,7 @@ func TestCredit(t *testing.T) {
 
 	t.Run("credit unknown currency", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -182,7 +208,7 @@ func TestCredit(t *testing.T) {
 
 	t.Run("credit error increment", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -199,7 +225,15 @@ func TestCredit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceIncrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:  "123",
+				Amount:   100,
+				Currency: "USD",
+			}).
 			Return(nil, domain.NewError(errorCreditSource).SetCode(domain.ErrIncrement))
 
 		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
@@ -213,4 +247,784 @@ func TestCredit(t *testing.T) {
 
 		repoMock.AssertExpectations(t)
 	})
+
+	t.Run("credit replayed transaction returns original", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeCredit,
+				Balance:                1100,
+			}, nil).
+			Twice()
+
+		for i := 0; i < 2; i++ {
+			res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+				TransactionUID: "provider-123",
+				UserUID:        "123",
+				Currency:       "USD",
+				Amount:         100,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "original", res.TransactionUID)
+			assert.Equal(t, 1100, res.Balance)
+		}
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit replayed transaction with different amount", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "USD",
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeCredit,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         200,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrTransactionConflict)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit capped by max win", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeCap
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:    "123",
+				Nick:   "test",
+				MaxWin: map[string]int{"USD": 500},
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+				MaxWin:       1000,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(&domain.Round{
+				UID:      "round-123",
+				UserUID:  "123",
+				TotalWin: 200,
+			}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:      "123",
+				RoundUID:     "round-123",
+				Amount:       300,
+				CappedAmount: 700,
+				Currency:     "USD",
+			}).
+			Return(&domain.Transaction{
+				UID:          "123",
+				UserUID:      "123",
+				Amount:       300,
+				CappedAmount: 700,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeCredit,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:  "123",
+			BetUID:   "round-123",
+			Currency: "USD",
+			Amount:   1000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 300, res.Amount)
+		assert.Equal(t, 500, res.MaxWin)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit rejected by max win", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeReject
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+				MaxWin:  100,
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+				MaxWin:       1000,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         200,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrMaxWinExceeded)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit jackpot payout not limited by max win", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeReject
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+				MaxWin:       1000,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				Amount:     5000,
+				Currency:   "USD",
+				JackpotKey: "mega",
+				Meta:       domain.TransactionMeta{JpKey: "mega"},
+			}).
+			Return(&domain.Transaction{
+				UID:          "123",
+				UserUID:      "123",
+				Amount:       5000,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeCredit,
+				JackpotKey:   "mega",
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   5000,
+			JpKey:    "mega",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5000, res.Amount)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit jackpot payout exceeds pool", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				Amount:     5000,
+				Currency:   "USD",
+				JackpotKey: "mega",
+				Meta:       domain.TransactionMeta{JpKey: "mega"},
+			}).
+			Return(nil, domain.NewError(errorCreditSource).SetCode(domain.ErrJackpotPayout))
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   5000,
+			JpKey:    "mega",
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrJackpotPayout)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit rejected by denomination precision", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:      "123",
+			Currency:     "USD",
+			Amount:       105,
+			Denomination: 3,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+		assert.Len(t, domain.AsError(err).Violations, 1)
+		assert.Equal(t, "amount", domain.AsError(err).Violations[0].Field)
+		assert.Equal(t, "precision", domain.AsError(err).Violations[0].Rule)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit normalized into wallet denomination", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 3,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 9845, Currency: "USD", Denomination: 3}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "t1",
+				UserUID:                "123",
+				Amount:                 2500,
+				Currency:               "USD",
+				Meta:                   domain.TransactionMeta{Denomination: 2},
+			}).
+			Return(&domain.Transaction{UID: "1", UserUID: "123", Amount: 2500, Balance: 12345, Currency: "USD", Denomination: 3}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "t1",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         250,
+			Denomination:   2,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 250, res.Amount)
+		assert.Equal(t, 1234, res.Balance)
+		assert.Equal(t, 2, res.Denomination)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit converted into wallet currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{
+				Code:         "EUR",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(&domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.1}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "t1",
+				UserUID:                "123",
+				Amount:                 110,
+				Currency:               "USD",
+				OriginalAmount:         100,
+				OriginalCurrency:       "EUR",
+				ExchangeRate:           1.1,
+			}).
+			Return(&domain.Transaction{
+				UID:              "1",
+				UserUID:          "123",
+				Amount:           110,
+				Balance:          1110,
+				Currency:         "USD",
+				Denomination:     2,
+				OriginalAmount:   100,
+				OriginalCurrency: "EUR",
+				ExchangeRate:     1.1,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "t1",
+			UserUID:        "123",
+			Currency:       "EUR",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100, res.Amount)
+		assert.Equal(t, 1009, res.Balance)
+		assert.Equal(t, "EUR", res.Currency)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit free round win as bonus money", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("CampaignGetByUID", ctx, "fr-1").
+			Return(&domain.Campaign{
+				UID:                "fr-1",
+				UserUID:            "123",
+				Currency:           "USD",
+				Rounds:             10,
+				Win:                domain.CampaignWinBonus,
+				WageringMultiplier: 30,
+				Status:             domain.CampaignStatusCompleted,
+				ExpiresAt:          time.Now().Add(-time.Hour),
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:     "123",
+				Amount:      150,
+				Currency:    "USD",
+				CampaignUID: "fr-1",
+				Wagering:    4500,
+			}).
+			Return(&domain.Transaction{
+				UID:          "123",
+				UserUID:      "123",
+				Amount:       150,
+				BonusAmount:  150,
+				Balance:      1150,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeCredit,
+				CampaignUID:  "fr-1",
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:     "123",
+			Currency:    "USD",
+			Amount:      150,
+			CampaignUID: "fr-1",
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, 150, res.Amount)
+		assert.Equal(t, 1150, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit replayed transaction with different currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{
+				Code:         "EUR",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "EUR", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "EUR",
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeCredit,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			Currency:       "EUR",
+			Amount:         200,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrTransactionConflict)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit replayed after reaching max win returns original", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeReject
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+				MaxWin:       1000,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		// round total already includes the original win
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(&domain.Round{
+				UID:      "round-123",
+				UserUID:  "123",
+				TotalWin: 600,
+			}, nil)
+
+		original := &domain.Transaction{
+			UID:                    "original",
+			ProviderTransactionUID: "provider-123",
+			UserUID:                "123",
+			RoundUID:               "round-123",
+			Amount:                 600,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeCredit,
+			Balance:                1600,
+		}
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "provider-123", domain.TransactionTypeCredit).
+			Return(original, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				RoundUID:               "round-123",
+				Amount:                 600,
+				Currency:               "USD",
+			}).
+			Return(original, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			BetUID:         "round-123",
+			Currency:       "USD",
+			Amount:         600,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "original", res.TransactionUID)
+		assert.Equal(t, 600, res.Amount)
+		assert.Equal(t, 1600, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit capped by max win of the round of the provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeCap
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", MaxWin: map[string]int{"USD": 500}}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "p1", "round-123").
+			Return(&domain.Round{ProviderUID: "p1", UID: "round-123", UserUID: "123", TotalWin: 400}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderUID:  "p1",
+				UserUID:      "123",
+				RoundUID:     "round-123",
+				Amount:       100,
+				CappedAmount: 200,
+				Currency:     "USD",
+			}).
+			Return(&domain.Transaction{
+				UID:          "123",
+				ProviderUID:  "p1",
+				UserUID:      "123",
+				Amount:       100,
+				CappedAmount: 200,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeCredit,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID: "p1",
+			UserUID:     "123",
+			BetUID:      "round-123",
+			Currency:    "USD",
+			Amount:      300,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100, res.Amount)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("credit capped by max win of the round booked to converted wallet", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		service.maxWinMode = domain.MaxWinModeCap
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", MaxWin: map[string]int{"EUR": 5000}}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Amount: 100000, Currency: "JPY"}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "JPY").
+			Return(&domain.ExchangeRate{From: "EUR", To: "JPY", Rate: 160}, nil)
+
+		// 3200 JPY won in the round is 20 EUR of the 50 EUR limit
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-1").
+			Return(&domain.Round{UID: "round-1", UserUID: "123", Currency: "JPY", TotalWin: 3200}, nil)
+
+		repoMock.
+			On("BalanceIncrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:          "123",
+				RoundUID:         "round-1",
+				Amount:           4800,
+				CappedAmount:     1600,
+				Currency:         "JPY",
+				OriginalAmount:   4000,
+				OriginalCurrency: "EUR",
+				ExchangeRate:     160,
+			}).
+			Return(&domain.Transaction{
+				UID:              "1",
+				UserUID:          "123",
+				Amount:           4800,
+				CappedAmount:     1600,
+				Balance:          104800,
+				Currency:         "JPY",
+				OriginalAmount:   4000,
+				OriginalCurrency: "EUR",
+				ExchangeRate:     160,
+			}, nil)
+
+		res, err := service.Credit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:  "123",
+			BetUID:   "round-1",
+			Currency: "EUR",
+			Amount:   4000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3000, res.Amount)
+		assert.Equal(t, 65500, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
 }
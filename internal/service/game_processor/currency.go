@@ -0,0 +1,19 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorCurrencySource = "[service.game_processor.currency]"
+)
+
+// Currency returns the currency the amounts of the game are denominated in
+func (s *Service) Currency(ctx context.Context, code string) (*domain.Currency, error) {
+	cur, err := s.repo.CurrencyGetByCode(ctx, code)
+	if err != nil || cur == nil {
+		return nil, domain.NewError(errorCurrencySource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+	return cur, nil
+}
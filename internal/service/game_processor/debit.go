@@ -10,12 +10,35 @@ const (
 )
 
 func (s *Service) Debit(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error) {
+	op, err := s.debitPrepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.repo.BalanceDecrementByUserUIDAndCurrency(ctx, op.draft)
+	if err != nil {
+		return nil, debitError(err)
+	}
+
+	return s.debitResult(ctx, op, txn)
+}
+
+// debitPrepare checks the session, user and currency of the debit and makes the transaction draft
+func (s *Service) debitPrepare(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*operation, error) {
+	var session *domain.Session
 	userUid := req.UserUID
 	if req.GameSessionUID != "" {
-		session, err := s.repo.SessionGetByUID(ctx, req.GameSessionUID)
+		var err error
+		session, err = s.repo.SessionGetByUID(ctx, req.GameSessionUID)
 		if err != nil {
 			return nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrSessionNotFound).Add(err)
 		}
+		if err = sessionOwned(session, req.ProviderUID, errorDebitSource); err != nil {
+			return nil, err
+		}
+		if err = s.sessionActive(session, req.Currency); err != nil {
+			return nil, err
+		}
 		userUid = session.UserUID
 	}
 
@@ -28,18 +51,71 @@ func (s *Service) Debit(ctx context.Context, req *domain.ProcessDebitCreditRollb
 	if err != nil || cur == nil {
 		return nil, domain.NewError(errorDebitSource).SetCode(domain.ErrUnknownCurrency).Add(err)
 	}
-
-	txn, err := s.repo.BalanceDecrementByUserUIDAndCurrency(ctx, userUid, req.Currency, req.Amount)
+	amount, err := denominate(req, cur, errorDebitSource)
 	if err != nil {
-		return nil, domain.NewError(errorDebitSource).SetCode(domain.ErrDecrement).Add(err)
+		return nil, err
+	}
+	if req.CampaignUID != "" {
+		if err = s.campaignDebit(ctx, req, userUid, session); err != nil {
+			return nil, err
+		}
 	}
 
-	return &domain.ProcessDebitCreditRollbackRes{
-		TransactionUID: txn.UID,
-		UserNick:       user.Nick,
-		Amount:         txn.Amount,
-		Currency:       txn.Currency,
-		Denomination:   txn.Denomination,
-		MaxWin:         0, // TODO: implement MaxWin
+	wlt := s.walletGet(ctx, session, userUid, cur, gameDenomination(req, cur))
+	draft := &domain.Transaction{
+		ProviderUID:            req.ProviderUID,
+		ProviderTransactionUID: req.TransactionUID,
+		UserUID:                userUid,
+		SessionUID:             req.GameSessionUID,
+		RoundUID:               req.BetUID,
+		Amount:                 amount,
+		Currency:               req.Currency,
+		BonusOrder:             s.bonusOrder,
+		CampaignUID:            req.CampaignUID,
+		Meta:                   transactionMeta(req),
+	}
+	wlt.exchange(draft, amount, true)
+	if err = s.gamingAllowed(ctx, user, session, draft); err != nil {
+		return nil, err
+	}
+	if jackpot := s.debitJackpot(ctx, req, session); jackpot != nil && jackpot.Contribution(amount) > 0 {
+		draft.JackpotKey = jackpot.Key
+		draft.JackpotContribution = jackpot.Contribution(amount)
+	}
+
+	return &operation{
+		txnType:   domain.TransactionTypeDebit,
+		req:       req,
+		session:   session,
+		user:      user,
+		maxWin:    domain.MaxWinLimit(cur, user, session),
+		requested: amount,
+		wallet:    wlt,
+		amount:    amount,
+		draft:     draft,
 	}, nil
 }
+
+// debitError keeps business error codes of the failed debit, the rest is reported as insufficient balance
+func debitError(err error) *domain.Error {
+	switch code := domain.AsError(err).Code; code {
+	case domain.ErrTransactionRolledBack, domain.ErrRoundClosed, domain.ErrRoundUser, domain.ErrTransactionConflict,
+		domain.ErrCampaignUnavailable, domain.ErrGamingLimitExceeded:
+		return domain.NewError(errorDebitSource).SetCode(code).Add(err)
+	}
+	return domain.NewError(errorDebitSource).SetCode(domain.ErrDecrement).Add(err)
+}
+
+// debitResult checks the stored transaction against the request and makes the response
+func (s *Service) debitResult(ctx context.Context, op *operation, txn *domain.Transaction) (*domain.ProcessDebitCreditRollbackRes, error) {
+	// replayed provider transaction must match the original one
+	if txn.UserUID != op.draft.UserUID || txn.RequestedAmount() != op.requested || txn.RequestedCurrency() != op.req.Currency {
+		return nil, domain.NewError(errorDebitSource).SetCode(domain.ErrTransactionConflict)
+	}
+
+	s.sessionTouch(ctx, op.session)
+
+	res := op.wallet.response(op, txn, op.amount)
+	res.RealityCheck = s.realityCheck(op.session, op.user, op.wallet)
+	return res, nil
+}
@@ -0,0 +1,100 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+const (
+	errorDebitCreditSource = "[service.game_processor.debit_credit]"
+)
+
+// DebitCredit debits the bet and credits the win of the instant round in one repository transaction,
+// both transactions are stored under the provider transaction id and linked to one round,
+// so the replayed request returns the stored pair and never leaves the debit without its credit
+func (s *Service) DebitCredit(ctx context.Context, req *domain.ProcessDebitCreditReq) (*domain.ProcessDebitCreditRes, error) {
+	debitReq, creditReq := debitCreditSplit(req)
+
+	debitOp, err := s.debitPrepare(ctx, debitReq)
+	if err != nil {
+		return nil, debitCreditViolations(err, "betAmount")
+	}
+	creditOp, err := s.creditPrepare(ctx, creditReq, 0)
+	if err != nil {
+		return nil, debitCreditViolations(err, "winAmount")
+	}
+
+	txns, failed, err := s.repo.TransactionBatch(ctx, []*domain.Transaction{debitOp.batchDraft(), creditOp.batchDraft()})
+	if err != nil {
+		switch failed {
+		case 0:
+			return nil, debitError(err)
+		case 1:
+			return nil, creditError(err)
+		}
+		return nil, domain.NewError(errorDebitCreditSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+
+	debitRes, err := s.debitResult(ctx, debitOp, txns[0])
+	if err != nil {
+		return nil, err
+	}
+	creditRes, err := s.creditResult(ctx, creditOp, txns[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ProcessDebitCreditRes{
+		DebitTransactionUID:  debitRes.TransactionUID,
+		CreditTransactionUID: creditRes.TransactionUID,
+		UserNick:             creditRes.UserNick,
+		BetAmount:            debitRes.Amount,
+		WinAmount:            creditRes.Amount,
+		Balance:              creditRes.Balance,
+		Currency:             creditRes.Currency,
+		Denomination:         creditRes.Denomination,
+		MaxWin:               creditRes.MaxWin,
+	}, nil
+}
+
+// debitCreditSplit makes the debit and the credit of the round, the round is the transaction itself when the bet id is omitted,
+// jackpot key is the jackpot the bet contributes to, so it isn't passed to the credit to not be paid out as jackpot win
+func debitCreditSplit(req *domain.ProcessDebitCreditReq) (*domain.ProcessDebitCreditRollbackReq, *domain.ProcessDebitCreditRollbackReq) {
+	betUID := req.BetUID
+	if betUID == "" {
+		betUID = req.TransactionUID
+	}
+
+	debitReq := &domain.ProcessDebitCreditRollbackReq{
+		ProviderUID:    req.ProviderUID,
+		TransactionUID: req.TransactionUID,
+		GameSessionUID: req.GameSessionUID,
+		UserUID:        req.UserUID,
+		UserNick:       req.UserNick,
+		Amount:         req.BetAmount,
+		Currency:       req.Currency,
+		Denomination:   req.Denomination,
+		MaxWin:         req.MaxWin,
+		JpKey:          req.JpKey,
+		SpinMeta:       req.SpinMeta,
+		BetMeta:        req.BetMeta,
+		BetUID:         betUID,
+	}
+
+	creditReq := *debitReq
+	creditReq.Amount = req.WinAmount
+	creditReq.JpKey = ""
+
+	return debitReq, &creditReq
+}
+
+// debitCreditViolations reports the amount violation of the debit or the credit by the amount field of the round request
+func debitCreditViolations(err error, field string) error {
+	e := domain.AsError(err)
+	for i := range e.Violations {
+		if e.Violations[i].Field == "amount" {
+			e.Violations[i].Field = field
+		}
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package game_processor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/game_processor/mocks"
+	"os"
+	"testing"
+)
+
+func TestDebitCredit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
+
+	req := &domain.ProcessDebitCreditReq{
+		TransactionUID: "t1",
+		UserUID:        "123",
+		Currency:       "USD",
+		BetAmount:      100,
+		WinAmount:      250,
+	}
+	drafts := []*domain.Transaction{
+		{ProviderTransactionUID: "t1", UserUID: "123", RoundUID: "t1", Amount: 100, Currency: "USD", BonusOrder: domain.BonusOrderRealFirst, Type: domain.TransactionTypeDebit},
+		{ProviderTransactionUID: "t1", UserUID: "123", RoundUID: "t1", Amount: 250, Currency: "USD", Type: domain.TransactionTypeCredit},
+	}
+
+	userAndCurrency := func(repoMock *mocks.Repository) {
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+	}
+
+	t.Run("debit credit success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("TransactionBatch", ctx, drafts).
+			Return([]*domain.Transaction{
+				{UID: "1", UserUID: "123", Amount: 100, Balance: 900, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeDebit},
+				{UID: "2", UserUID: "123", Amount: 250, Balance: 1150, Currency: "USD", Denomination: 2, Type: domain.TransactionTypeCredit},
+			}, -1, nil)
+
+		res, err := service.DebitCredit(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", res.DebitTransactionUID)
+		assert.Equal(t, "2", res.CreditTransactionUID)
+		assert.Equal(t, "test", res.UserNick)
+		assert.Equal(t, 100, res.BetAmount)
+		assert.Equal(t, 250, res.WinAmount)
+		assert.Equal(t, 1150, res.Balance)
+		assert.Equal(t, 2, res.Denomination)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit credit insufficient balance", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("TransactionBatch", ctx, drafts).
+			Return(nil, 0, domain.NewError("test").SetCode(domain.ErrDecrement))
+
+		res, err := service.DebitCredit(ctx, req)
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrDecrement, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit credit replay conflict", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		userAndCurrency(repoMock)
+
+		repoMock.
+			On("TransactionBatch", ctx, drafts).
+			Return(nil, 1, domain.NewError("test").SetCode(domain.ErrTransactionConflict))
+
+		res, err := service.DebitCredit(ctx, req)
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrTransactionConflict, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -3,11 +3,13 @@ package game_processor
 import (
 	"context"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"log/slog"
 	"open-api-games/internal/domain"
 	"open-api-games/internal/service/game_processor/mocks"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestDebit(t *testing.T) {
@@ -15,10 +17,11 @@ func TestDebit(t *testing.T) {
 
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
 
 	t.Run("debit by session success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("SessionGetByUID", ctx, "123").
@@ -27,6 +30,10 @@ func TestDebit(t *testing.T) {
 				UID:     "123",
 			}, nil)
 
+		repoMock.
+			On("SessionTouch", ctx, "123", mock.Anything, mock.Anything).
+			Return(nil)
+
 		repoMock.
 			On("UserGetByUID", ctx, "123").
 			Return(&domain.User{
@@ -42,9 +49,20 @@ func TestDebit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceDecrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				SessionUID: "123",
+				Amount:     100,
+				Currency:   "USD",
+				BonusOrder: domain.BonusOrderRealFirst,
+			}).
 			Return(&domain.Transaction{
 				UID:          "123",
+				UserUID:      "123",
 				Amount:       100,
 				Currency:     "USD",
 				Denomination: 2,
@@ -70,7 +88,7 @@ func TestDebit(t *testing.T) {
 
 	t.Run("debit by user success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -87,9 +105,19 @@ func TestDebit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceDecrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				Amount:     100,
+				Currency:   "USD",
+				BonusOrder: domain.BonusOrderRealFirst,
+			}).
 			Return(&domain.Transaction{
 				UID:          "123",
+				UserUID:      "123",
 				Amount:       100,
 				Currency:     "USD",
 				Denomination: 2,
@@ -115,7 +143,7 @@ func TestDebit(t *testing.T) {
 
 	t.Run("debit by session not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("SessionGetByUID", ctx, "123").
@@ -135,7 +163,7 @@ func TestDebit(t *testing.T) {
 
 	t.Run("credit by user not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -155,7 +183,7 @@ func TestDebit(t *testing.T) {
 
 	t.Run("debit unknown currency", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -182,7 +210,7 @@ func TestDebit(t *testing.T) {
 
 	t.Run("debit insufficient funds", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		repoMock.
 			On("UserGetByUID", ctx, "123").
@@ -199,7 +227,16 @@ func TestDebit(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceDecrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:    "123",
+				Amount:     100,
+				Currency:   "USD",
+				BonusOrder: domain.BonusOrderRealFirst,
+			}).
 			Return(nil, domain.NewError(errorDebitSource).SetCode(domain.ErrDecrement))
 
 		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
@@ -213,4 +250,1165 @@ func TestDebit(t *testing.T) {
 
 		repoMock.AssertExpectations(t)
 	})
+
+	t.Run("debit replayed transaction returns original", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeDebit,
+				Balance:                900,
+			}, nil).
+			Twice()
+
+		for i := 0; i < 2; i++ {
+			res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+				TransactionUID: "provider-123",
+				UserUID:        "123",
+				Currency:       "USD",
+				Amount:         100,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "original", res.TransactionUID)
+			assert.Equal(t, 900, res.Balance)
+		}
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit replayed transaction with different amount", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "USD",
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeDebit,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         200,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrTransactionConflict)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit after its rollback rejected", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(nil, domain.NewError(errorDebitSource).SetCode(domain.ErrTransactionRolledBack))
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrTransactionRolledBack)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit stores bet context", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("SessionTouch", ctx, "123", mock.Anything, mock.Anything).
+			Return(nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("JackpotGetByKey", ctx, "jp", "USD").
+			Return(&domain.Jackpot{
+				Key:                 "jp",
+				Currency:            "USD",
+				Amount:              10000,
+				ContributionPercent: 1.5,
+				IsActive:            true,
+			}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				SessionUID:             "123",
+				RoundUID:               "round-123",
+				Amount:                 100,
+				Currency:               "USD",
+				JackpotKey:             "jp",
+				JackpotContribution:    1,
+				Meta: domain.TransactionMeta{
+					SpinMeta:     "spin",
+					BetMeta:      "bet",
+					JpKey:        "jp",
+					Denomination: 2,
+					MaxWin:       1000,
+				},
+				BonusOrder: domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{
+				UID:           "123",
+				UserUID:       "123",
+				Amount:        100,
+				Currency:      "USD",
+				Denomination:  2,
+				Type:          domain.TransactionTypeDebit,
+				BalanceBefore: 1000,
+				Balance:       900,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			GameSessionUID: "123",
+			BetUID:         "round-123",
+			Currency:       "USD",
+			Amount:         100,
+			SpinMeta:       "spin",
+			BetMeta:        "bet",
+			JpKey:          "jp",
+			Denomination:   2,
+			MaxWin:         1000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 900, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by closed session", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:     "123",
+				UserUID: "123",
+				Status:  domain.SessionStatusClosed,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSessionClosed, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by expired session", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:       "123",
+				UserUID:   "123",
+				Status:    domain.SessionStatusOpen,
+				ExpiresAt: now.Add(-time.Minute),
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSessionExpired, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by session currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:      "123",
+				UserUID:  "123",
+				Status:   domain.SessionStatusOpen,
+				Currency: "EUR",
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSessionCurrency, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by denomination precision", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:      "123",
+			Currency:     "USD",
+			Amount:       105,
+			Denomination: 3,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrInvalidRequest, domain.AsError(err).Code)
+		assert.Len(t, domain.AsError(err).Violations, 1)
+		assert.Equal(t, "amount", domain.AsError(err).Violations[0].Field)
+		assert.Equal(t, "precision", domain.AsError(err).Violations[0].Rule)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit normalized from game denomination", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{
+				Code:         "USD",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "t1",
+				UserUID:                "123",
+				Amount:                 100,
+				Currency:               "USD",
+				Meta:                   domain.TransactionMeta{Denomination: 3},
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{UID: "1", UserUID: "123", Amount: 100, Balance: 900, Currency: "USD", Denomination: 2}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "t1",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         1000,
+			Denomination:   3,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, res.Amount)
+		assert.Equal(t, 9000, res.Balance)
+		assert.Equal(t, 3, res.Denomination)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit converted into wallet currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{
+				Code:         "EUR",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "USD", "EUR").
+			Return(&domain.ExchangeRate{From: "USD", To: "EUR", Rate: 0.8}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "t1",
+				UserUID:                "123",
+				Amount:                 125,
+				Currency:               "USD",
+				OriginalAmount:         100,
+				OriginalCurrency:       "EUR",
+				ExchangeRate:           1.25,
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{
+				UID:              "1",
+				UserUID:          "123",
+				Amount:           125,
+				Balance:          875,
+				Currency:         "USD",
+				Denomination:     2,
+				OriginalAmount:   100,
+				OriginalCurrency: "EUR",
+				ExchangeRate:     1.25,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "t1",
+			UserUID:        "123",
+			Currency:       "EUR",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100, res.Amount)
+		assert.Equal(t, 700, res.Balance)
+		assert.Equal(t, "EUR", res.Currency)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit free round paid by campaign", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("CampaignGetByUID", ctx, "fr-1").
+			Return(&domain.Campaign{
+				UID:        "fr-1",
+				UserUID:    "123",
+				GameUID:    "game-1",
+				Currency:   "USD",
+				Rounds:     10,
+				RoundsLeft: 3,
+				BetValue:   20,
+				Win:        domain.CampaignWinReal,
+				Status:     domain.CampaignStatusActive,
+				ExpiresAt:  time.Now().Add(time.Hour),
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				UserUID:     "123",
+				Currency:    "USD",
+				BonusOrder:  domain.BonusOrderRealFirst,
+				CampaignUID: "fr-1",
+			}).
+			Return(&domain.Transaction{
+				UID:          "123",
+				UserUID:      "123",
+				Balance:      1000,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeDebit,
+				CampaignUID:  "fr-1",
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:     "123",
+			Currency:    "USD",
+			CampaignUID: "fr-1",
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, 0, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit free round rejected by unavailable campaign", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("CampaignGetByUID", ctx, "fr-1").
+			Return(&domain.Campaign{
+				UID:        "fr-1",
+				UserUID:    "123",
+				Currency:   "USD",
+				Rounds:     10,
+				RoundsLeft: 3,
+				Status:     domain.CampaignStatusActive,
+				ExpiresAt:  time.Now().Add(-time.Hour),
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:     "123",
+			Currency:    "USD",
+			CampaignUID: "fr-1",
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrCampaignUnavailable, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit free round rejected by campaign of another user", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("CampaignGetByUID", ctx, "fr-1").
+			Return(&domain.Campaign{
+				UID:        "fr-1",
+				UserUID:    "456",
+				Currency:   "USD",
+				RoundsLeft: 3,
+				Status:     domain.CampaignStatusActive,
+				ExpiresAt:  time.Now().Add(time.Hour),
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:     "123",
+			Currency:    "USD",
+			CampaignUID: "fr-1",
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrCampaignNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by self-exclusion", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", SelfExcludedUntil: time.Now().AddDate(0, 6, 0)}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:  "123",
+			Currency: "USD",
+			Amount:   100,
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrPlayerExcluded, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "BalanceDecrementByUserUIDAndCurrency", mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by daily loss limit", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:    "123",
+				Nick:   "test",
+				Limits: map[string]domain.GamingLimits{"USD": {Loss: domain.PeriodLimits{Day: 1000}}},
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.MatchedBy(func(txn *domain.Transaction) bool {
+				return txn.GamingLimits != nil && txn.GamingLimits.Loss.Day == 1000
+			})).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrGamingLimitExceeded))
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "tx-1",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrGamingLimitExceeded, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit replayed after reaching limit returns original", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:    "123",
+				Nick:   "test",
+				Limits: map[string]domain.GamingLimits{"USD": {Wager: domain.PeriodLimits{Week: 1000}}},
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 900, Currency: "USD", Denomination: 2}, nil)
+
+		original := &domain.Transaction{
+			UID:                    "456",
+			ProviderTransactionUID: "tx-1",
+			UserUID:                "123",
+			Amount:                 100,
+			Balance:                900,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeDebit,
+		}
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.Anything).
+			Return(original, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "tx-1",
+			UserUID:        "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "456", res.TransactionUID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit rejected by session time limit", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "s-1").
+			Return(&domain.Session{
+				UID:       "s-1",
+				UserUID:   "123",
+				Currency:  "USD",
+				Status:    domain.SessionStatusOpen,
+				CreatedAt: time.Now().Add(-2 * time.Hour),
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", SessionLimit: time.Hour}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "tx-1", domain.TransactionTypeDebit).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrNotFound))
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "tx-1",
+			GameSessionUID: "s-1",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSessionTimeLimit, domain.AsError(err).Code)
+
+		repoMock.AssertNotCalled(t, "BalanceDecrementByUserUIDAndCurrency", mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit response carries due reality check", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		now := time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		repoMock.
+			On("SessionGetByUID", ctx, "s-1").
+			Return(&domain.Session{
+				UID:       "s-1",
+				UserUID:   "123",
+				Currency:  "USD",
+				Status:    domain.SessionStatusOpen,
+				CreatedAt: now.Add(-45 * time.Minute),
+				TotalBet:  2500,
+				TotalWin:  1000,
+			}, nil)
+
+		repoMock.
+			On("SessionTouch", ctx, "s-1", now, time.Time{}).
+			Return(nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test", RealityCheckInterval: 30 * time.Minute}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.Anything).
+			Return(&domain.Transaction{
+				UID:          "456",
+				UserUID:      "123",
+				Amount:       100,
+				Balance:      900,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeDebit,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "s-1",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.RealityCheck{
+			Elapsed:      45 * time.Minute,
+			NetResult:    -1500,
+			Currency:     "USD",
+			Denomination: 2,
+		}, res.RealityCheck)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit replayed transaction with different currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{
+				Code:         "EUR",
+				Denomination: 2,
+			}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "EUR", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, &domain.Transaction{
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "EUR",
+				BonusOrder:             domain.BonusOrderRealFirst,
+			}).
+			Return(&domain.Transaction{
+				UID:                    "original",
+				ProviderTransactionUID: "provider-123",
+				UserUID:                "123",
+				Amount:                 200,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeDebit,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "provider-123",
+			UserUID:        "123",
+			Currency:       "EUR",
+			Amount:         200,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrTransactionConflict)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("free round debit replayed after last round returns original", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("CampaignGetByUID", ctx, "fr-1").
+			Return(&domain.Campaign{
+				UID:        "fr-1",
+				UserUID:    "123",
+				Currency:   "USD",
+				Rounds:     10,
+				RoundsLeft: 0,
+				Status:     domain.CampaignStatusCompleted,
+				ExpiresAt:  time.Now().Add(time.Hour),
+			}, nil)
+
+		original := &domain.Transaction{
+			UID:                    "debit-1",
+			ProviderTransactionUID: "tx-1",
+			UserUID:                "123",
+			Balance:                1000,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeDebit,
+			CampaignUID:            "fr-1",
+		}
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "tx-1", domain.TransactionTypeDebit).
+			Return(original, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.Anything).
+			Return(original, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			UserUID:        "123",
+			Currency:       "USD",
+			CampaignUID:    "fr-1",
+			TransactionUID: "tx-1",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "debit-1", res.TransactionUID)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit in session of another provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:         "123",
+				UserUID:     "123",
+				ProviderUID: "provider-a",
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "provider-b",
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderSession, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+		repoMock.AssertNotCalled(t, "BalanceDecrementByUserUIDAndCurrency", mock.Anything, mock.Anything)
+	})
+
+	t.Run("debit stores provider of the request", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:         "123",
+				UserUID:     "123",
+				ProviderUID: "provider-a",
+			}, nil)
+
+		repoMock.
+			On("SessionTouch", ctx, "123", mock.Anything, mock.Anything).
+			Return(nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.MatchedBy(func(txn *domain.Transaction) bool {
+				return txn.ProviderUID == "provider-a" && txn.ProviderTransactionUID == "tx-1"
+			})).
+			Return(&domain.Transaction{
+				UID:                    "debit-1",
+				ProviderUID:            "provider-a",
+				ProviderTransactionUID: "tx-1",
+				UserUID:                "123",
+				Amount:                 100,
+				Balance:                900,
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeDebit,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "provider-a",
+			TransactionUID: "tx-1",
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "debit-1", res.TransactionUID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit in session converted into its wallet", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "s1").
+			Return(&domain.Session{
+				UID:            "s1",
+				UserUID:        "123",
+				ProviderUID:    "provider-a",
+				Currency:       "EUR",
+				WalletCurrency: "USD",
+			}, nil)
+
+		repoMock.
+			On("SessionTouch", ctx, "s1", mock.Anything, mock.Anything).
+			Return(nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR", Denomination: 2}, nil)
+
+		// the session plays from its wallet without looking for a balance in the game currency
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(&domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.25}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.MatchedBy(func(txn *domain.Transaction) bool {
+				return txn.Currency == "USD" && txn.Amount == 125 && txn.OriginalCurrency == "EUR" && txn.OriginalAmount == 100
+			})).
+			Return(&domain.Transaction{
+				UID:              "debit-1",
+				UserUID:          "123",
+				Amount:           125,
+				Balance:          875,
+				Currency:         "USD",
+				Denomination:     2,
+				OriginalAmount:   100,
+				OriginalCurrency: "EUR",
+				ExchangeRate:     1.25,
+				Type:             domain.TransactionTypeDebit,
+			}, nil)
+
+		res, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "provider-a",
+			TransactionUID: "tx-1",
+			GameSessionUID: "s1",
+			Currency:       "EUR",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100, res.Amount)
+		assert.Equal(t, 700, res.Balance)
+		assert.Equal(t, "EUR", res.Currency)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("debit moves session expiry forward by its TTL", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{SessionTTL: time.Hour})
+		now := time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		// the session opened longer than the TTL ago is still active thanks to the activity
+		repoMock.
+			On("SessionGetByUID", ctx, "s-1").
+			Return(&domain.Session{
+				UID:            "s-1",
+				UserUID:        "123",
+				Currency:       "USD",
+				Status:         domain.SessionStatusOpen,
+				CreatedAt:      now.Add(-3 * time.Hour),
+				LastActivityAt: now.Add(-10 * time.Minute),
+				ExpiresAt:      now.Add(50 * time.Minute),
+			}, nil)
+
+		repoMock.
+			On("SessionTouch", ctx, "s-1", now, now.Add(time.Hour)).
+			Return(nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceDecrementByUserUIDAndCurrency", ctx, mock.Anything).
+			Return(&domain.Transaction{
+				UID:          "456",
+				UserUID:      "123",
+				Amount:       100,
+				Balance:      900,
+				Currency:     "USD",
+				Denomination: 2,
+				Type:         domain.TransactionTypeDebit,
+			}, nil)
+
+		_, err := service.Debit(ctx, &domain.ProcessDebitCreditRollbackReq{
+			GameSessionUID: "s-1",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+
+		repoMock.AssertExpectations(t)
+	})
 }
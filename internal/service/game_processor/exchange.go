@@ -0,0 +1,149 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+// wallet is the balance the request in the game currency is booked to
+type wallet struct {
+	cur *domain.Currency
+	// denomination is the denomination the amounts are reported to the game in
+	denomination int
+	balance      *domain.Balance
+	// rate converts the game currency into the wallet currency, nil when the user has balance in the game currency
+	rate *domain.ExchangeRate
+}
+
+// walletGet finds the balance of the user in the game currency, the user without it plays from the first balance
+// having exchange rate from the game currency, wallet without balance books the request in the game currency as is,
+// the session opened in the converted wallet keeps playing from it
+func (s *Service) walletGet(ctx context.Context, session *domain.Session, userUID string, cur *domain.Currency, denomination int) *wallet {
+	if session != nil && session.WalletCurrency != "" && session.WalletCurrency != cur.Code {
+		if wlt := s.sessionWalletGet(ctx, session, cur, denomination); wlt != nil {
+			return wlt
+		}
+	}
+
+	balance, err := s.repo.BalanceGetByUserUIDAndCurrency(ctx, userUID, cur.Code)
+	if err == nil {
+		return &wallet{cur: cur, denomination: denomination, balance: balance}
+	}
+
+	balances, err := s.repo.BalanceListByUserUID(ctx, userUID)
+	if err != nil {
+		s.logger.Warn("failed to list user balances", "userUid", userUID, "error", err)
+		return &wallet{cur: cur, denomination: denomination}
+	}
+	for i := range balances {
+		rate, err := s.exchangeRate(ctx, cur.Code, balances[i].Currency)
+		if err == nil {
+			return &wallet{cur: cur, denomination: denomination, balance: &balances[i], rate: rate}
+		}
+	}
+	return &wallet{cur: cur, denomination: denomination}
+}
+
+// sessionWalletGet finds the wallet the session was opened in, nil when its balance or exchange rate is gone
+func (s *Service) sessionWalletGet(ctx context.Context, session *domain.Session, cur *domain.Currency, denomination int) *wallet {
+	balance, err := s.repo.BalanceGetByUserUIDAndCurrency(ctx, session.UserUID, session.WalletCurrency)
+	if err != nil {
+		s.logger.Warn("failed to find session wallet", "sessionUid", session.UID, "currency", session.WalletCurrency, "error", err)
+		return nil
+	}
+	rate, err := s.exchangeRate(ctx, cur.Code, balance.Currency)
+	if err != nil {
+		s.logger.Warn("failed to find session exchange rate", "sessionUid", session.UID, "currency", session.WalletCurrency, "error", err)
+		return nil
+	}
+	return &wallet{cur: cur, denomination: denomination, balance: balance, rate: rate}
+}
+
+// exchangeRate finds the rate of the currency pair, the rate of the opposite pair is inverted when the direct one is missing
+func (s *Service) exchangeRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rate, err := s.repo.ExchangeRateGet(ctx, from, to)
+	if err == nil {
+		return rate, nil
+	}
+	opposite, errOpposite := s.repo.ExchangeRateGet(ctx, to, from)
+	if errOpposite != nil {
+		return nil, err
+	}
+	return opposite.Inverse(), nil
+}
+
+// exchange books the draft of the requested amount in the wallet currency, the requested amount and the rate are kept on the draft,
+// debits are converted up and credits down, so the conversion never pays out more than it takes
+func (w *wallet) exchange(draft *domain.Transaction, requested int, debit bool) {
+	if w.rate == nil {
+		return
+	}
+
+	draft.OriginalAmount = requested
+	draft.OriginalCurrency = draft.Currency
+	draft.ExchangeRate = w.rate.Rate
+	draft.Currency = w.balance.Currency
+	if debit {
+		draft.Amount = w.rate.ConvertUp(draft.Amount, w.cur.Denomination, w.balance.Denomination)
+		return
+	}
+	draft.Amount = w.rate.ConvertDown(draft.Amount, w.cur.Denomination, w.balance.Denomination)
+	draft.CappedAmount = w.rate.ConvertDown(requested, w.cur.Denomination, w.balance.Denomination) - draft.Amount
+}
+
+// toGame converts the wallet amount back into the game currency and denomination rounding down
+func (w *wallet) toGame(amount int) int {
+	if w.rate == nil {
+		return domain.DenominateDown(amount, w.balance.Denomination, w.denomination)
+	}
+	return w.rate.Inverse().ConvertDown(amount, w.balance.Denomination, w.denomination)
+}
+
+// fromWallet converts the amount booked in the currency back into minor units of the game currency at the wallet rate,
+// it's rounded up, so the amount compared to the limits isn't lowered by the conversion,
+// the amount booked in the game currency is returned as is
+func (w *wallet) fromWallet(amount int, currency string) int {
+	if w.rate == nil || currency != w.balance.Currency {
+		return amount
+	}
+	return w.rate.Inverse().ConvertUp(amount, w.balance.Denomination, w.cur.Denomination)
+}
+
+// rolledBack is the amount of the rolled back transaction in minor units of the game currency,
+// the capped credit is converted back from the credited amount
+func (w *wallet) rolledBack(txn *domain.Transaction) int {
+	if w.rate == nil || txn.CappedAmount == 0 {
+		return txn.RequestedAmount()
+	}
+	return w.rate.Inverse().ConvertDown(txn.Amount, w.balance.Denomination, w.cur.Denomination)
+}
+
+// denominate reports the amount in minor units of the game currency in the game denomination rounding down
+func (w *wallet) denominate(amount int) int {
+	return domain.DenominateDown(amount, w.cur.Denomination, w.denomination)
+}
+
+// response describes the stored transaction in the game currency and denomination,
+// amount is the moved amount in minor units of the game currency
+func (w *wallet) response(op *operation, txn *domain.Transaction, amount int) *domain.ProcessDebitCreditRollbackRes {
+	if w.rate == nil {
+		return &domain.ProcessDebitCreditRollbackRes{
+			TransactionUID: txn.UID,
+			UserNick:       op.user.Nick,
+			Amount:         domain.DenominateDown(txn.Amount, txn.Denomination, w.denomination),
+			Balance:        domain.DenominateDown(txn.Balance, txn.Denomination, w.denomination),
+			Currency:       txn.Currency,
+			Denomination:   w.denomination,
+			MaxWin:         w.denominate(op.maxWin),
+		}
+	}
+	return &domain.ProcessDebitCreditRollbackRes{
+		TransactionUID: txn.UID,
+		UserNick:       op.user.Nick,
+		Amount:         w.denominate(amount),
+		Balance:        w.toGame(txn.Balance),
+		Currency:       w.cur.Code,
+		Denomination:   w.denomination,
+		MaxWin:         w.denominate(op.maxWin),
+	}
+}
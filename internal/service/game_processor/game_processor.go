@@ -2,29 +2,161 @@ package game_processor
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"open-api-games/internal/domain"
+	"time"
 )
 
 //go:generate mockery --dir . --name Repository --output ./mocks --case=underscore
 type Repository interface {
 	UserGetByUID(ctx context.Context, uid string) (*domain.User, error)
 	SessionGetByUID(ctx context.Context, uid string) (*domain.Session, error)
+	SessionTouch(ctx context.Context, uid string, now, expiresAt time.Time) error
+	SessionRealityChecked(ctx context.Context, uid string, now time.Time) error
 	BalanceGetByUserUIDAndCurrency(ctx context.Context, userUID, currency string) (*domain.Balance, error)
-	BalanceDecrementByUserUIDAndCurrency(ctx context.Context, userUID, currency string, amount int) (*domain.Transaction, error)
-	BalanceIncrementByUserUIDAndCurrency(ctx context.Context, userUID, currency string, amount int) (*domain.Transaction, error)
+	BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error)
+	BalanceDecrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error)
+	BalanceIncrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error)
 	TransactionGetByUID(ctx context.Context, uid string) (*domain.Transaction, error)
+	TransactionGetByProviderUID(ctx context.Context, providerUID, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error)
+	TransactionRollback(ctx context.Context, uid string, meta domain.TransactionMeta) (*domain.Transaction, error)
+	TransactionCreateTombstone(ctx context.Context, tombstone *domain.Transaction) (*domain.Transaction, error)
+	TransactionBatch(ctx context.Context, drafts []*domain.Transaction) ([]*domain.Transaction, int, error)
 	CurrencyGetByCode(ctx context.Context, code string) (*domain.Currency, error)
+	ExchangeRateGet(ctx context.Context, from, to string) (*domain.ExchangeRate, error)
+	RoundGetByProviderUID(ctx context.Context, providerUID, uid string) (*domain.Round, error)
+	RoundClose(ctx context.Context, round *domain.Round) (*domain.Round, error)
+	JackpotGetByKey(ctx context.Context, key, currency string) (*domain.Jackpot, error)
+	JackpotListActiveByGame(ctx context.Context, gameUID, currency string) ([]domain.Jackpot, error)
+	CampaignGetByUID(ctx context.Context, uid string) (*domain.Campaign, error)
+	GamingCounterList(ctx context.Context, userUID, currency string, now time.Time) ([]domain.GamingCounter, error)
 }
 
 type Service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo       Repository
+	logger     *slog.Logger
+	maxWinMode domain.MaxWinMode
+	bonusOrder domain.BonusOrder
+	sessionTTL time.Duration
+	now        func() time.Time
 }
 
-func New(repo Repository, logger *slog.Logger) *Service {
+// Settings tune the processing of game requests, zero values cap wins by max win and spend real money first,
+// SessionTTL is the idle time the activity moves the session expiry forward by, zero leaves the expiry as is
+type Settings struct {
+	MaxWinMode domain.MaxWinMode
+	BonusOrder domain.BonusOrder
+	SessionTTL time.Duration
+}
+
+func New(repo Repository, logger *slog.Logger, settings Settings) *Service {
 	return &Service{
-		repo:   repo,
-		logger: logger,
+		repo:       repo,
+		logger:     logger,
+		maxWinMode: settings.MaxWinMode,
+		bonusOrder: settings.BonusOrder,
+		sessionTTL: settings.SessionTTL,
+		now:        time.Now,
+	}
+}
+
+// operation is the debit, credit or rollback request checked and turned into the transaction draft
+type operation struct {
+	txnType domain.TransactionType
+	req     *domain.ProcessDebitCreditRollbackReq
+	session *domain.Session
+	user    *domain.User
+	maxWin  int
+	// requested is the amount of the request in minor units of the game currency
+	requested int
+	// wallet is the balance the debit or credit is booked to, amount is the moved amount in minor units of the game currency
+	wallet *wallet
+	amount int
+	// draft is the transaction to store, rollback draft refers the rolled back transaction by ParentTransactionUID
+	draft *domain.Transaction
+	// tombstone marks rollback of the transaction which never reached us
+	tombstone bool
+}
+
+// batchDraft returns the draft typed for the batch, so the repository tells the movement of every item
+func (op *operation) batchDraft() *domain.Transaction {
+	draft := *op.draft
+	draft.Type = op.txnType
+	if op.tombstone {
+		draft.Status = domain.TransactionStatusTombstone
+	}
+	return &draft
+}
+
+// transactionMeta extracts raw bet context of the provider request to store it with the transaction
+func transactionMeta(req *domain.ProcessDebitCreditRollbackReq) domain.TransactionMeta {
+	return domain.TransactionMeta{
+		SpinMeta:     req.SpinMeta,
+		BetMeta:      req.BetMeta,
+		JpKey:        req.JpKey,
+		Denomination: req.Denomination,
+		MaxWin:       req.MaxWin,
+	}
+}
+
+// gameDenomination is the denomination of the amounts of the request, zero means the currency one
+func gameDenomination(req *domain.ProcessDebitCreditRollbackReq, cur *domain.Currency) int {
+	if req.Denomination == 0 {
+		return cur.Denomination
+	}
+	return req.Denomination
+}
+
+// denominate converts the amount of the request into minor units of the currency,
+// the amount which doesn't fit the currency denomination is rejected instead of being rounded
+func denominate(req *domain.ProcessDebitCreditRollbackReq, cur *domain.Currency, source string) (int, error) {
+	amount, ok := domain.Denominate(req.Amount, gameDenomination(req, cur), cur.Denomination)
+	if ok {
+		return amount, nil
+	}
+	return 0, domain.NewError(source).SetCode(domain.ErrInvalidRequest).SetViolations(domain.Violation{
+		Field:   "amount",
+		Rule:    "precision",
+		Message: fmt.Sprintf("value must fit denomination %d of currency %s", cur.Denomination, cur.Code),
+	})
+}
+
+// sessionOwned checks that the session was opened by the provider authenticated by the request sign,
+// so the provider can't move money of the sessions of another provider
+func sessionOwned(session *domain.Session, providerUID, source string) error {
+	if session.ProviderUID != providerUID {
+		return domain.NewError(source).SetCode(domain.ErrProviderSession)
+	}
+	return nil
+}
+
+// sessionActive checks that the session still accepts bets in the requested currency
+func (s *Service) sessionActive(session *domain.Session, currency string) error {
+	if !session.IsActive(s.now()) {
+		if session.Status == domain.SessionStatusClosed {
+			return domain.NewError(errorDebitSource).SetCode(domain.ErrSessionClosed)
+		}
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrSessionExpired)
+	}
+	if session.Currency != "" && session.Currency != currency {
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrSessionCurrency)
+	}
+	return nil
+}
+
+// sessionTouch prolongs the session activity, so the active session expires only after the TTL of idle time,
+// failure must not break already processed money movement
+func (s *Service) sessionTouch(ctx context.Context, session *domain.Session) {
+	if session == nil {
+		return
+	}
+	now := s.now()
+	var expiresAt time.Time
+	if s.sessionTTL > 0 {
+		expiresAt = now.Add(s.sessionTTL)
+	}
+	if err := s.repo.SessionTouch(ctx, session.UID, now, expiresAt); err != nil {
+		s.logger.Error("failed to touch session", "sessionUid", session.UID, "error", err)
 	}
 }
@@ -0,0 +1,43 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+// gamingAllowed checks that the user isn't excluded from gaming and the session hasn't reached the session time limit of the user,
+// responsible gaming limits of the wallet currency are passed on the draft, replayed debit is let through
+// to be answered with the stored transaction
+func (s *Service) gamingAllowed(ctx context.Context, user *domain.User, session *domain.Session, draft *domain.Transaction) error {
+	err := s.gamingCheck(user, session, draft)
+	if err != nil && s.debitReplayed(ctx, draft.ProviderUID, draft.ProviderTransactionUID) {
+		return nil
+	}
+	return err
+}
+
+// debitReplayed reports whether the debit of the provider transaction is already booked, checks rejecting the debit
+// let the replayed one through to be answered with the stored transaction
+func (s *Service) debitReplayed(ctx context.Context, providerUID, transactionUID string) bool {
+	if transactionUID == "" {
+		return false
+	}
+	_, err := s.repo.TransactionGetByProviderUID(ctx, providerUID, transactionUID, domain.TransactionTypeDebit)
+	return err == nil
+}
+
+func (s *Service) gamingCheck(user *domain.User, session *domain.Session, draft *domain.Transaction) error {
+	now := s.now()
+	if user.IsExcluded(now) {
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrPlayerExcluded)
+	}
+	if session != nil && session.TimeLimitReached(now, user.SessionLimit) {
+		return domain.NewError(errorDebitSource).SetCode(domain.ErrSessionTimeLimit)
+	}
+
+	// the repository checks the bet against the limits within the movement, so concurrent bets can't exceed them together
+	if limits, ok := user.Limits[draft.Currency]; ok {
+		draft.GamingLimits = &limits
+	}
+	return nil
+}
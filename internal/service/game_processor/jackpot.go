@@ -0,0 +1,39 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+)
+
+// debitJackpot finds the pool the bet contributes to: requested by the game provider or active pool of the session game,
+// missing pool doesn't block the bet
+func (s *Service) debitJackpot(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, session *domain.Session) *domain.Jackpot {
+	if req.JpKey != "" {
+		jackpot, err := s.repo.JackpotGetByKey(ctx, req.JpKey, req.Currency)
+		if err != nil || !jackpot.IsActive {
+			s.logger.Warn("jackpot not available for contribution", "jpKey", req.JpKey, "currency", req.Currency, "error", err)
+			return nil
+		}
+		return jackpot
+	}
+
+	jackpots := s.sessionJackpots(ctx, session, req.Currency)
+	if len(jackpots) == 0 {
+		return nil
+	}
+	return &jackpots[0]
+}
+
+// sessionJackpots returns active pools of the session game, the game's own pools go first
+func (s *Service) sessionJackpots(ctx context.Context, session *domain.Session, currency string) []domain.Jackpot {
+	if session == nil || session.GameUID == "" {
+		return nil
+	}
+
+	jackpots, err := s.repo.JackpotListActiveByGame(ctx, session.GameUID, currency)
+	if err != nil {
+		s.logger.Warn("failed to get session jackpots", "gameUid", session.GameUID, "currency", currency, "error", err)
+		return nil
+	}
+	return jackpots
+}
@@ -14,11 +14,61 @@ func (s *Service) MetaData(ctx context.Context, req *domain.ProcessMetaDataReq)
 	if err != nil {
 		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrSessionNotFound).Add(err)
 	}
+	if err = sessionOwned(session, req.ProviderUID, errorMetadataSource); err != nil {
+		return nil, err
+	}
+
+	switch req.Api {
+	case domain.ProcessApiDataApiRoundComplete:
+		round, err := s.roundComplete(ctx, session, req.Data.BetUID)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.ProcessMetaDataRes{
+			Api:   req.Api,
+			Round: round,
+		}, nil
+	case domain.ProcessApiDataApiRealityCheck:
+		check, err := s.realityChecked(ctx, session, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.ProcessMetaDataRes{
+			Api:          req.Api,
+			RealityCheck: check,
+		}, nil
+	default:
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrInvalidApiCommand)
+	}
+}
 
-	// TODO: implement metadata api
+// roundComplete closes the round of the session after checking its bets and wins reconcile,
+// already closed round is returned as is
+func (s *Service) roundComplete(ctx context.Context, session *domain.Session, betUID string) (*domain.Round, error) {
+	if betUID == "" {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrEmptyBetUID)
+	}
+
+	round, err := s.repo.RoundGetByProviderUID(ctx, session.ProviderUID, betUID)
+	if err != nil {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrRoundNotFound).Add(err)
+	}
+	if round.UserUID != session.UserUID {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrRoundNotFound)
+	}
+	if round.IsClosed() {
+		return round, nil
+	}
+
+	if !round.Reconcile() {
+		s.logger.Error("round totals don't match its transactions", "round", round)
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrRoundNotReconciled)
+	}
+
+	round, err = s.repo.RoundClose(ctx, round)
+	if err != nil {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrRoundClose).Add(err)
+	}
 
-	return &domain.ProcessMetaDataRes{
-		Api:  "",
-		Data: session.UID,
-	}, nil
+	return round, nil
 }
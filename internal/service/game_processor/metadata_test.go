@@ -0,0 +1,269 @@
+package game_processor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/game_processor/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMetaData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
+
+	openRound := func() *domain.Round {
+		return &domain.Round{
+			UID:        "round-123",
+			SessionUID: "123",
+			UserUID:    "123",
+			Currency:   "USD",
+			Status:     domain.RoundStatusOpen,
+			TotalBet:   100,
+			TotalWin:   50,
+			Transactions: []domain.RoundTransaction{
+				{UID: "1", Type: domain.TransactionTypeDebit, Amount: 100},
+				{UID: "2", Type: domain.TransactionTypeDebit, Amount: 30},
+				{UID: "3", Type: domain.TransactionTypeRollback, Amount: 30, ParentTransactionUID: "2"},
+				{UID: "4", Type: domain.TransactionTypeCredit, Amount: 50},
+			},
+		}
+	}
+
+	t.Run("round complete success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(openRound(), nil)
+
+		closedRound := openRound()
+		closedRound.Status = domain.RoundStatusClosed
+		repoMock.
+			On("RoundClose", ctx, openRound()).
+			Return(closedRound, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+			Data:           domain.ProcessApiDataData{BetUID: "round-123"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProcessApiDataApiRoundComplete, res.Api)
+		assert.Equal(t, domain.RoundStatusClosed, res.Round.Status)
+		assert.Equal(t, 100, res.Round.TotalBet)
+		assert.Equal(t, 50, res.Round.TotalWin)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("round complete already closed", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		closedRound := openRound()
+		closedRound.Status = domain.RoundStatusClosed
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(closedRound, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+			Data:           domain.ProcessApiDataData{BetUID: "round-123"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoundStatusClosed, res.Round.Status)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("round complete not reconciled", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		round := openRound()
+		round.TotalBet = 130
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(round, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+			Data:           domain.ProcessApiDataData{BetUID: "round-123"},
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrRoundNotReconciled)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("round complete round of another user", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "321",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("RoundGetByProviderUID", ctx, "", "round-123").
+			Return(openRound(), nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+			Data:           domain.ProcessApiDataData{BetUID: "round-123"},
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrRoundNotFound)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("round complete empty bet id", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrEmptyBetUID)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("metadata invalid api", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            "unknown",
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrInvalidApiCommand)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("metadata session not found", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrNotFound))
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRoundComplete,
+		})
+
+		assert.Equal(t, domain.AsError(err).Code, domain.ErrSessionNotFound)
+		assert.Nil(t, res)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("reality check confirmed", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+		now := time.Date(2026, time.October, 14, 16, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UID:       "123",
+				UserUID:   "123",
+				Currency:  "USD",
+				CreatedAt: now.Add(-time.Hour),
+				TotalBet:  500,
+				TotalWin:  200,
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("SessionRealityChecked", ctx, "123", now).
+			Return(nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		res, err := service.MetaData(ctx, &domain.ProcessMetaDataReq{
+			GameSessionUID: "123",
+			Api:            domain.ProcessApiDataApiRealityCheck,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProcessApiDataApiRealityCheck, res.Api)
+		assert.Nil(t, res.Round)
+		assert.Equal(t, &domain.RealityCheck{
+			Elapsed:      time.Hour,
+			NetResult:    -300,
+			Currency:     "USD",
+			Denomination: 2,
+		}, res.RealityCheck)
+
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -7,6 +7,8 @@ import (
 	domain "open-api-games/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -14,9 +16,9 @@ type Repository struct {
 	mock.Mock
 }
 
-// BalanceDecrementByUserUIDAndCurrency provides a mock function with given fields: ctx, userUID, currency, amount
-func (_m *Repository) BalanceDecrementByUserUIDAndCurrency(ctx context.Context, userUID string, currency string, amount int) (*domain.Transaction, error) {
-	ret := _m.Called(ctx, userUID, currency, amount)
+// BalanceDecrementByUserUIDAndCurrency provides a mock function with given fields: ctx, txn
+func (_m *Repository) BalanceDecrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, txn)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BalanceDecrementByUserUIDAndCurrency")
@@ -24,19 +26,19 @@ func (_m *Repository) BalanceDecrementByUserUIDAndCurrency(ctx context.Context,
 
 	var r0 *domain.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*domain.Transaction, error)); ok {
-		return rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) (*domain.Transaction, error)); ok {
+		return rf(ctx, txn)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *domain.Transaction); ok {
-		r0 = rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) *domain.Transaction); ok {
+		r0 = rf(ctx, txn)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
-		r1 = rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Transaction) error); ok {
+		r1 = rf(ctx, txn)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -74,9 +76,9 @@ func (_m *Repository) BalanceGetByUserUIDAndCurrency(ctx context.Context, userUI
 	return r0, r1
 }
 
-// BalanceIncrementByUserUIDAndCurrency provides a mock function with given fields: ctx, userUID, currency, amount
-func (_m *Repository) BalanceIncrementByUserUIDAndCurrency(ctx context.Context, userUID string, currency string, amount int) (*domain.Transaction, error) {
-	ret := _m.Called(ctx, userUID, currency, amount)
+// BalanceIncrementByUserUIDAndCurrency provides a mock function with given fields: ctx, txn
+func (_m *Repository) BalanceIncrementByUserUIDAndCurrency(ctx context.Context, txn *domain.Transaction) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, txn)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BalanceIncrementByUserUIDAndCurrency")
@@ -84,19 +86,79 @@ func (_m *Repository) BalanceIncrementByUserUIDAndCurrency(ctx context.Context,
 
 	var r0 *domain.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*domain.Transaction, error)); ok {
-		return rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) (*domain.Transaction, error)); ok {
+		return rf(ctx, txn)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *domain.Transaction); ok {
-		r0 = rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) *domain.Transaction); ok {
+		r0 = rf(ctx, txn)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
-		r1 = rf(ctx, userUID, currency, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Transaction) error); ok {
+		r1 = rf(ctx, txn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceListByUserUID provides a mock function with given fields: ctx, userUID
+func (_m *Repository) BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error) {
+	ret := _m.Called(ctx, userUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BalanceListByUserUID")
+	}
+
+	var r0 []domain.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Balance, error)); ok {
+		return rf(ctx, userUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Balance); ok {
+		r0 = rf(ctx, userUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CampaignGetByUID provides a mock function with given fields: ctx, uid
+func (_m *Repository) CampaignGetByUID(ctx context.Context, uid string) (*domain.Campaign, error) {
+	ret := _m.Called(ctx, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CampaignGetByUID")
+	}
+
+	var r0 *domain.Campaign
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Campaign, error)); ok {
+		return rf(ctx, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Campaign); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Campaign)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uid)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,6 +196,186 @@ func (_m *Repository) CurrencyGetByCode(ctx context.Context, code string) (*doma
 	return r0, r1
 }
 
+// ExchangeRateGet provides a mock function with given fields: ctx, from, to
+func (_m *Repository) ExchangeRateGet(ctx context.Context, from string, to string) (*domain.ExchangeRate, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeRateGet")
+	}
+
+	var r0 *domain.ExchangeRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.ExchangeRate, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.ExchangeRate); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExchangeRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GamingCounterList provides a mock function with given fields: ctx, userUID, currency, now
+func (_m *Repository) GamingCounterList(ctx context.Context, userUID string, currency string, now time.Time) ([]domain.GamingCounter, error) {
+	ret := _m.Called(ctx, userUID, currency, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GamingCounterList")
+	}
+
+	var r0 []domain.GamingCounter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) ([]domain.GamingCounter, error)); ok {
+		return rf(ctx, userUID, currency, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) []domain.GamingCounter); ok {
+		r0 = rf(ctx, userUID, currency, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.GamingCounter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, userUID, currency, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JackpotGetByKey provides a mock function with given fields: ctx, key, currency
+func (_m *Repository) JackpotGetByKey(ctx context.Context, key string, currency string) (*domain.Jackpot, error) {
+	ret := _m.Called(ctx, key, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for JackpotGetByKey")
+	}
+
+	var r0 *domain.Jackpot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Jackpot, error)); ok {
+		return rf(ctx, key, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Jackpot); ok {
+		r0 = rf(ctx, key, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Jackpot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, key, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JackpotListActiveByGame provides a mock function with given fields: ctx, gameUID, currency
+func (_m *Repository) JackpotListActiveByGame(ctx context.Context, gameUID string, currency string) ([]domain.Jackpot, error) {
+	ret := _m.Called(ctx, gameUID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for JackpotListActiveByGame")
+	}
+
+	var r0 []domain.Jackpot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]domain.Jackpot, error)); ok {
+		return rf(ctx, gameUID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []domain.Jackpot); ok {
+		r0 = rf(ctx, gameUID, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Jackpot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, gameUID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoundClose provides a mock function with given fields: ctx, round
+func (_m *Repository) RoundClose(ctx context.Context, round *domain.Round) (*domain.Round, error) {
+	ret := _m.Called(ctx, round)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RoundClose")
+	}
+
+	var r0 *domain.Round
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Round) (*domain.Round, error)); ok {
+		return rf(ctx, round)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Round) *domain.Round); ok {
+		r0 = rf(ctx, round)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Round)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Round) error); ok {
+		r1 = rf(ctx, round)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoundGetByProviderUID provides a mock function with given fields: ctx, providerUID, uid
+func (_m *Repository) RoundGetByProviderUID(ctx context.Context, providerUID string, uid string) (*domain.Round, error) {
+	ret := _m.Called(ctx, providerUID, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RoundGetByProviderUID")
+	}
+
+	var r0 *domain.Round
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Round, error)); ok {
+		return rf(ctx, providerUID, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Round); ok {
+		r0 = rf(ctx, providerUID, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Round)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, providerUID, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SessionGetByUID provides a mock function with given fields: ctx, uid
 func (_m *Repository) SessionGetByUID(ctx context.Context, uid string) (*domain.Session, error) {
 	ret := _m.Called(ctx, uid)
@@ -164,6 +406,139 @@ func (_m *Repository) SessionGetByUID(ctx context.Context, uid string) (*domain.
 	return r0, r1
 }
 
+// SessionRealityChecked provides a mock function with given fields: ctx, uid, now
+func (_m *Repository) SessionRealityChecked(ctx context.Context, uid string, now time.Time) error {
+	ret := _m.Called(ctx, uid, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SessionRealityChecked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, uid, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionTouch provides a mock function with given fields: ctx, uid, now, expiresAt
+func (_m *Repository) SessionTouch(ctx context.Context, uid string, now time.Time, expiresAt time.Time) error {
+	ret := _m.Called(ctx, uid, now, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SessionTouch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, uid, now, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionBatch provides a mock function with given fields: ctx, drafts
+func (_m *Repository) TransactionBatch(ctx context.Context, drafts []*domain.Transaction) ([]*domain.Transaction, int, error) {
+	ret := _m.Called(ctx, drafts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransactionBatch")
+	}
+
+	var r0 []*domain.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Transaction) ([]*domain.Transaction, int, error)); ok {
+		return rf(ctx, drafts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Transaction) []*domain.Transaction); ok {
+		r0 = rf(ctx, drafts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*domain.Transaction) int); ok {
+		r1 = rf(ctx, drafts)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []*domain.Transaction) error); ok {
+		r2 = rf(ctx, drafts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// TransactionCreateTombstone provides a mock function with given fields: ctx, tombstone
+func (_m *Repository) TransactionCreateTombstone(ctx context.Context, tombstone *domain.Transaction) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, tombstone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransactionCreateTombstone")
+	}
+
+	var r0 *domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) (*domain.Transaction, error)); ok {
+		return rf(ctx, tombstone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Transaction) *domain.Transaction); ok {
+		r0 = rf(ctx, tombstone)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Transaction) error); ok {
+		r1 = rf(ctx, tombstone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionGetByProviderUID provides a mock function with given fields: ctx, providerUID, transactionUID, txnType
+func (_m *Repository) TransactionGetByProviderUID(ctx context.Context, providerUID string, transactionUID string, txnType domain.TransactionType) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, providerUID, transactionUID, txnType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransactionGetByProviderUID")
+	}
+
+	var r0 *domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.TransactionType) (*domain.Transaction, error)); ok {
+		return rf(ctx, providerUID, transactionUID, txnType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.TransactionType) *domain.Transaction); ok {
+		r0 = rf(ctx, providerUID, transactionUID, txnType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.TransactionType) error); ok {
+		r1 = rf(ctx, providerUID, transactionUID, txnType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TransactionGetByUID provides a mock function with given fields: ctx, uid
 func (_m *Repository) TransactionGetByUID(ctx context.Context, uid string) (*domain.Transaction, error) {
 	ret := _m.Called(ctx, uid)
@@ -194,6 +569,36 @@ func (_m *Repository) TransactionGetByUID(ctx context.Context, uid string) (*dom
 	return r0, r1
 }
 
+// TransactionRollback provides a mock function with given fields: ctx, uid, meta
+func (_m *Repository) TransactionRollback(ctx context.Context, uid string, meta domain.TransactionMeta) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, uid, meta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransactionRollback")
+	}
+
+	var r0 *domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TransactionMeta) (*domain.Transaction, error)); ok {
+		return rf(ctx, uid, meta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TransactionMeta) *domain.Transaction); ok {
+		r0 = rf(ctx, uid, meta)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TransactionMeta) error); ok {
+		r1 = rf(ctx, uid, meta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UserGetByUID provides a mock function with given fields: ctx, uid
 func (_m *Repository) UserGetByUID(ctx context.Context, uid string) (*domain.User, error) {
 	ret := _m.Called(ctx, uid)
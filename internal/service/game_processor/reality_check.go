@@ -0,0 +1,50 @@
+package game_processor
+
+import (
+	"context"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+// realityCheck returns the reality check of the session once the interval of the user has passed since the last one,
+// nil when it isn't due
+func (s *Service) realityCheck(session *domain.Session, user *domain.User, wlt *wallet) *domain.RealityCheck {
+	now := s.now()
+	if session == nil || !session.RealityCheckDue(now, user.RealityCheckInterval) {
+		return nil
+	}
+	return sessionRealityCheck(session, wlt, now)
+}
+
+// realityChecked registers that the user has seen the reality check dialog and returns the reality check of the session,
+// so the next one is due after the whole interval
+func (s *Service) realityChecked(ctx context.Context, session *domain.Session, currency string) (*domain.RealityCheck, error) {
+	if session.Currency != "" {
+		currency = session.Currency
+	}
+	cur, err := s.repo.CurrencyGetByCode(ctx, currency)
+	if err != nil || cur == nil {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	now := s.now()
+	if err = s.repo.SessionRealityChecked(ctx, session.UID, now); err != nil {
+		return nil, domain.NewError(errorMetadataSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+
+	return sessionRealityCheck(session, s.walletGet(ctx, session, session.UserUID, cur, cur.Denomination), now), nil
+}
+
+// sessionRealityCheck reports the session time and net result in the game currency and denomination
+func sessionRealityCheck(session *domain.Session, wlt *wallet, now time.Time) *domain.RealityCheck {
+	net := wlt.denominate(session.NetResult())
+	if wlt.balance != nil {
+		net = wlt.toGame(session.NetResult())
+	}
+	return &domain.RealityCheck{
+		Elapsed:      session.Elapsed(now),
+		NetResult:    net,
+		Currency:     wlt.cur.Code,
+		Denomination: wlt.denomination,
+	}
+}
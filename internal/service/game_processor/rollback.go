@@ -10,44 +10,209 @@ const (
 )
 
 func (s *Service) Rollback(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error) {
+	op, err := s.rollbackPrepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var txn *domain.Transaction
+	switch {
+	case op.tombstone:
+		txn, err = s.repo.TransactionCreateTombstone(ctx, op.draft)
+	case op.draft.RollbackTransactionUID != "":
+		// repeated rollback returns result of the first one
+		txn, err = s.repo.TransactionGetByUID(ctx, op.draft.RollbackTransactionUID)
+	default:
+		txn, err = s.repo.TransactionRollback(ctx, op.draft.ParentTransactionUID, op.draft.Meta)
+	}
+	if err != nil {
+		return nil, rollbackError(err)
+	}
+
+	return s.rollbackResult(op, txn), nil
+}
+
+// rollbackPrepare finds the transaction to roll back, the draft refers it by ParentTransactionUID,
+// unknown transaction gets the tombstone draft
+func (s *Service) rollbackPrepare(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*operation, error) {
 	if req.TransactionUID == "" {
 		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrEmptyTransactionUID)
 	}
 
-	txn, err := s.repo.TransactionGetByUID(ctx, req.TransactionUID)
+	txn, err := s.rollbackFind(ctx, req.ProviderUID, req.TransactionUID)
 	if err != nil {
-		return nil, domain.NewError(errorCreditSource).SetCode(domain.ErrTransactionNotFound).Add(err)
+		return s.rollbackUnknownPrepare(ctx, req, err)
+	}
+	if err = s.rollbackPaired(ctx, req, txn); err != nil {
+		return nil, err
 	}
 
-	userUid := txn.UserUID
-
-	user, err := s.repo.UserGetByUID(ctx, userUid)
+	user, err := s.repo.UserGetByUID(ctx, txn.UserUID)
 	if err != nil {
 		return nil, domain.NewError(errorCreditSource).SetCode(domain.ErrUserNotFound).Add(err)
 	}
 
-	// TODO: implement custom rollback logic to save already rollbacked transaction
-	// just only create the opposite transaction for now
-
-	var txnRollback *domain.Transaction
-	switch txn.Type {
-	case domain.TransactionTypeCredit:
-		txnRollback, err = s.repo.BalanceDecrementByUserUIDAndCurrency(ctx, userUid, txn.Currency, txn.Amount)
-	case domain.TransactionTypeDebit:
-		txnRollback, err = s.repo.BalanceIncrementByUserUIDAndCurrency(ctx, userUid, txn.Currency, txn.Amount)
-	default:
+	if txn.Type != domain.TransactionTypeCredit && txn.Type != domain.TransactionTypeDebit {
 		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrInvalidTransactionType)
 	}
+
+	draft := &domain.Transaction{
+		UserUID:              txn.UserUID,
+		ParentTransactionUID: txn.UID,
+		Meta:                 transactionMeta(req),
+	}
+	if txn.IsRolledBack() {
+		draft.RollbackTransactionUID = txn.RollbackTransactionUID
+	}
+
+	w, err := s.rollbackWallet(ctx, req, txn)
 	if err != nil {
-		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrRollback).Add(err)
+		return nil, err
 	}
 
-	return &domain.ProcessDebitCreditRollbackRes{
-		TransactionUID: txnRollback.UID,
-		UserNick:       user.Nick,
-		Amount:         txnRollback.Amount,
-		Currency:       txnRollback.Currency,
-		Denomination:   txnRollback.Denomination,
-		MaxWin:         0, // TODO: implement MaxWin
+	return &operation{
+		txnType: domain.TransactionTypeRollback,
+		req:     req,
+		user:    user,
+		maxWin:  domain.MaxWinLimit(w.cur, user, s.rollbackSession(ctx, txn.SessionUID)),
+		wallet:  w,
+		amount:  w.rolledBack(txn),
+		draft:   draft,
 	}, nil
 }
+
+// rollbackWallet describes the balance the rolled back transaction was booked to, the converted transaction
+// is reported back in the game currency at the rate it was booked at
+func (s *Service) rollbackWallet(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, txn *domain.Transaction) (*wallet, error) {
+	cur, err := s.repo.CurrencyGetByCode(ctx, txn.RequestedCurrency())
+	if err != nil || cur == nil {
+		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	w := &wallet{
+		cur:          cur,
+		denomination: gameDenomination(req, cur),
+		balance:      &domain.Balance{Currency: txn.Currency, Denomination: txn.Denomination},
+	}
+	if txn.OriginalCurrency != "" {
+		w.rate = &domain.ExchangeRate{From: txn.OriginalCurrency, To: txn.Currency, Rate: txn.ExchangeRate}
+	}
+	return w, nil
+}
+
+// rollbackSession finds the session of the rolled back transaction for its max win limit, the rollback isn't rejected
+// when the session can't be found, the limit falls back to the user and currency ones
+func (s *Service) rollbackSession(ctx context.Context, sessionUID string) *domain.Session {
+	if sessionUID == "" {
+		return nil
+	}
+	session, err := s.repo.SessionGetByUID(ctx, sessionUID)
+	if err != nil {
+		s.logger.Warn("failed to get session of rolled back transaction", "sessionUid", sessionUID, "error", err)
+		return nil
+	}
+	return session
+}
+
+// rollbackFind resolves the transaction of the provider to roll back, the provider transaction id refers the debit first,
+// as the debit and the credit of the instant round share it, the credit of such round is referenced
+// by the id returned in our response
+func (s *Service) rollbackFind(ctx context.Context, providerUID, transactionUID string) (*domain.Transaction, error) {
+	txn, err := s.repo.TransactionGetByProviderUID(ctx, providerUID, transactionUID, domain.TransactionTypeDebit)
+	if err == nil {
+		return txn, nil
+	}
+	txn, err = s.repo.TransactionGetByProviderUID(ctx, providerUID, transactionUID, domain.TransactionTypeCredit)
+	if err == nil {
+		return txn, nil
+	}
+	txn, err = s.repo.TransactionGetByUID(ctx, transactionUID)
+	if err != nil {
+		return nil, err
+	}
+	// transaction of another provider is unknown to the provider
+	if txn.ProviderUID != providerUID {
+		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrTransactionNotFound)
+	}
+	return txn, nil
+}
+
+// rollbackPaired rejects the rollback referring the debit by the provider transaction id the debit shares with its credit,
+// as the bet would be returned while the win stays with the player, such legs are rolled back by the ids of our response
+func (s *Service) rollbackPaired(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, txn *domain.Transaction) error {
+	if txn.Type != domain.TransactionTypeDebit || txn.IsRolledBack() || txn.ProviderTransactionUID != req.TransactionUID {
+		return nil
+	}
+	credit, err := s.repo.TransactionGetByProviderUID(ctx, req.ProviderUID, req.TransactionUID, domain.TransactionTypeCredit)
+	if err != nil || credit.IsRolledBack() {
+		return nil
+	}
+	return domain.NewError(errorRollbackSource).SetCode(domain.ErrRollbackAmbiguous)
+}
+
+// rollbackUnknownPrepare makes tombstone draft for the transaction which never reached us, so the late original
+// transaction will be rejected, the tombstone keeps current balance
+func (s *Service) rollbackUnknownPrepare(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq, errNotFound error) (*operation, error) {
+	var session *domain.Session
+	userUid := req.UserUID
+	if req.GameSessionUID != "" {
+		var err error
+		session, err = s.repo.SessionGetByUID(ctx, req.GameSessionUID)
+		if err != nil {
+			return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrTransactionNotFound).Add(errNotFound).Add(err)
+		}
+		if err = sessionOwned(session, req.ProviderUID, errorRollbackSource); err != nil {
+			return nil, err
+		}
+		userUid = session.UserUID
+	}
+	if userUid == "" || req.Currency == "" {
+		// not enough data to find the balance to tombstone
+		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrTransactionNotFound).Add(errNotFound)
+	}
+
+	user, err := s.repo.UserGetByUID(ctx, userUid)
+	if err != nil {
+		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+
+	cur, err := s.repo.CurrencyGetByCode(ctx, req.Currency)
+	if err != nil || cur == nil {
+		return nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	// the tombstone is booked to the balance the original transaction would have been booked to
+	wlt := s.walletGet(ctx, session, userUid, cur, gameDenomination(req, cur))
+	draft := &domain.Transaction{
+		ProviderUID:            req.ProviderUID,
+		ProviderTransactionUID: req.TransactionUID,
+		UserUID:                userUid,
+		SessionUID:             req.GameSessionUID,
+		RoundUID:               req.BetUID,
+		Currency:               req.Currency,
+		Meta:                   transactionMeta(req),
+	}
+	wlt.exchange(draft, 0, false)
+
+	return &operation{
+		txnType:   domain.TransactionTypeRollback,
+		req:       req,
+		user:      user,
+		maxWin:    domain.MaxWinLimit(cur, user, session),
+		wallet:    wlt,
+		draft:     draft,
+		tombstone: true,
+	}, nil
+}
+
+func rollbackError(err error) *domain.Error {
+	return domain.NewError(errorRollbackSource).SetCode(domain.ErrRollback).Add(err)
+}
+
+func (s *Service) rollbackResult(op *operation, txn *domain.Transaction) *domain.ProcessDebitCreditRollbackRes {
+	if op.tombstone {
+		s.logger.Info("tombstone created for unknown transaction", "providerTransactionUid", op.req.TransactionUID, "uid", txn.UID)
+	}
+
+	return op.wallet.response(op, txn, op.amount)
+}
@@ -3,6 +3,7 @@ package game_processor
 import (
 	"context"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"log/slog"
 	"open-api-games/internal/domain"
 	"open-api-games/internal/service/game_processor/mocks"
@@ -15,12 +16,13 @@ func TestRollback(t *testing.T) {
 
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	settings := Settings{MaxWinMode: domain.MaxWinModeCap, BonusOrder: domain.BonusOrderRealFirst}
 
 	t.Run("rollback debit success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
-		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
 			UID:          "123",
 			UserUID:      "123",
 			Amount:       100,
@@ -37,13 +39,18 @@ func TestRollback(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceIncrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2, MaxWin: 5000}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", domain.TransactionMeta{}).
 			Return(&domain.Transaction{
-				UID:          "1234",
-				Amount:       100,
-				Currency:     "USD",
-				Denomination: 2,
-				Type:         domain.TransactionTypeCredit,
+				UID:                  "1234",
+				Amount:               100,
+				Currency:             "USD",
+				Denomination:         2,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "123",
 			}, nil)
 
 		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
@@ -56,15 +63,19 @@ func TestRollback(t *testing.T) {
 		assert.Equal(t, 100, res.Amount)
 		assert.Equal(t, "USD", res.Currency)
 		assert.Equal(t, 2, res.Denomination)
+		assert.Equal(t, 5000, res.MaxWin)
 
 		repoMock.AssertExpectations(t)
 	})
 
 	t.Run("rollback credit success", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
-		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeCredit).Return(&domain.Transaction{
 			UID:          "123",
 			UserUID:      "123",
 			Amount:       100,
@@ -81,13 +92,18 @@ func TestRollback(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceDecrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", domain.TransactionMeta{}).
 			Return(&domain.Transaction{
-				UID:          "1234",
-				Amount:       100,
-				Currency:     "USD",
-				Denomination: 2,
-				Type:         domain.TransactionTypeDebit,
+				UID:                  "1234",
+				Amount:               100,
+				Currency:             "USD",
+				Denomination:         2,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "123",
 			}, nil)
 
 		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
@@ -106,7 +122,7 @@ func TestRollback(t *testing.T) {
 
 	t.Run("rollback transaction uid empty", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
 		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
 			TransactionUID: "",
@@ -120,7 +136,11 @@ func TestRollback(t *testing.T) {
 
 	t.Run("rollback transaction not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "321", mock.Anything).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
 
 		repoMock.
 			On("TransactionGetByUID", ctx, "321").
@@ -138,9 +158,9 @@ func TestRollback(t *testing.T) {
 
 	t.Run("rollback transaction user not found", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
-		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
 			UID:          "123",
 			UserUID:      "123",
 			Amount:       100,
@@ -165,9 +185,9 @@ func TestRollback(t *testing.T) {
 
 	t.Run("rollback transaction type invalid", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
-		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
 			UID:          "123",
 			UserUID:      "123",
 			Amount:       100,
@@ -195,9 +215,9 @@ func TestRollback(t *testing.T) {
 
 	t.Run("rollback transaction type invalid", func(t *testing.T) {
 		repoMock := &mocks.Repository{}
-		service := New(repoMock, logger)
+		service := New(repoMock, logger, settings)
 
-		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
 			UID:          "123",
 			UserUID:      "123",
 			Amount:       100,
@@ -214,8 +234,12 @@ func TestRollback(t *testing.T) {
 			}, nil)
 
 		repoMock.
-			On("BalanceIncrementByUserUIDAndCurrency", ctx, "123", "USD", 100).
-			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrIncrement))
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", domain.TransactionMeta{}).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrRollback))
 
 		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
 			TransactionUID: "123",
@@ -226,4 +250,468 @@ func TestRollback(t *testing.T) {
 
 		repoMock.AssertExpectations(t)
 	})
+
+	t.Run("rollback already rolled back transaction", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
+			UID:                    "123",
+			UserUID:                "123",
+			Amount:                 100,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeDebit,
+			Status:                 domain.TransactionStatusRolledBack,
+			RollbackTransactionUID: "1234",
+		}, nil)
+
+		repoMock.On("TransactionGetByUID", ctx, "1234").Return(&domain.Transaction{
+			UID:                  "1234",
+			UserUID:              "123",
+			Amount:               100,
+			Currency:             "USD",
+			Denomination:         2,
+			Type:                 domain.TransactionTypeRollback,
+			Status:               domain.TransactionStatusCommitted,
+			ParentTransactionUID: "123",
+			Balance:              1000,
+		}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "123",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", res.TransactionUID)
+		assert.Equal(t, 100, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertNotCalled(t, "TransactionRollback", ctx, "123", domain.TransactionMeta{})
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback by internal transaction uid", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "123", mock.Anything).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+			UID:          "123",
+			UserUID:      "123",
+			Amount:       100,
+			Currency:     "USD",
+			Denomination: 2,
+			Type:         domain.TransactionTypeDebit,
+		}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", domain.TransactionMeta{}).
+			Return(&domain.Transaction{
+				UID:                  "1234",
+				Amount:               100,
+				Currency:             "USD",
+				Denomination:         2,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "123",
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "123",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", res.TransactionUID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback unknown transaction creates tombstone", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "321", mock.Anything).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("TransactionGetByUID", ctx, "321").
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("SessionGetByUID", ctx, "123").
+			Return(&domain.Session{
+				UserUID: "123",
+				UID:     "123",
+			}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionCreateTombstone", ctx, &domain.Transaction{
+				ProviderTransactionUID: "321",
+				UserUID:                "123",
+				SessionUID:             "123",
+				Currency:               "USD",
+			}).
+			Return(&domain.Transaction{
+				UID:                    "tombstone",
+				ProviderTransactionUID: "321",
+				UserUID:                "123",
+				Currency:               "USD",
+				Denomination:           2,
+				Type:                   domain.TransactionTypeRollback,
+				Status:                 domain.TransactionStatusTombstone,
+				Balance:                1000,
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "321",
+			GameSessionUID: "123",
+			Currency:       "USD",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "tombstone", res.TransactionUID)
+		assert.Equal(t, 0, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback converted debit reports game currency", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
+			UID:              "123",
+			UserUID:          "123",
+			Amount:           125,
+			Currency:         "USD",
+			Denomination:     2,
+			OriginalAmount:   100,
+			OriginalCurrency: "EUR",
+			ExchangeRate:     1.25,
+			Type:             domain.TransactionTypeDebit,
+		}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:  "123",
+				Nick: "test",
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR", Denomination: 3}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", mock.Anything).
+			Return(&domain.Transaction{
+				UID:                  "1234",
+				Amount:               125,
+				Currency:             "USD",
+				Denomination:         2,
+				Balance:              1250,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "123",
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "123",
+			Denomination:   2,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", res.TransactionUID)
+		assert.Equal(t, "EUR", res.Currency)
+		assert.Equal(t, 2, res.Denomination)
+		assert.Equal(t, 10, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback reports max win of the session", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.On("TransactionGetByProviderUID", ctx, "", "123", domain.TransactionTypeDebit).Return(&domain.Transaction{
+			UID:          "123",
+			UserUID:      "123",
+			SessionUID:   "s1",
+			Amount:       100,
+			Currency:     "USD",
+			Denomination: 2,
+			Type:         domain.TransactionTypeDebit,
+		}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{
+				UID:    "123",
+				Nick:   "test",
+				MaxWin: map[string]int{"USD": 3000},
+			}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2, MaxWin: 5000}, nil)
+
+		repoMock.
+			On("SessionGetByUID", ctx, "s1").
+			Return(&domain.Session{UID: "s1", UserUID: "123", MaxWin: 700}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "123", domain.TransactionMeta{}).
+			Return(&domain.Transaction{
+				UID:                  "1234",
+				Amount:               100,
+				Currency:             "USD",
+				Denomination:         2,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "123",
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "123",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 700, res.MaxWin)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback by internal uid of transaction of another provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "provider-b", "123", mock.Anything).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.On("TransactionGetByUID", ctx, "123").Return(&domain.Transaction{
+			UID:          "123",
+			ProviderUID:  "provider-a",
+			UserUID:      "123",
+			Amount:       100,
+			Currency:     "USD",
+			Denomination: 2,
+			Type:         domain.TransactionTypeDebit,
+		}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "provider-b",
+			TransactionUID: "123",
+		})
+
+		assert.Equal(t, domain.ErrTransactionNotFound, domain.AsError(err).Code)
+		assert.Nil(t, res)
+
+		repoMock.AssertNotCalled(t, "TransactionRollback", mock.Anything, mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback of instant round by shared provider transaction id", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.On("TransactionGetByProviderUID", ctx, "p1", "dc-1", domain.TransactionTypeDebit).Return(&domain.Transaction{
+			UID:                    "debit-1",
+			ProviderUID:            "p1",
+			ProviderTransactionUID: "dc-1",
+			UserUID:                "123",
+			Amount:                 100,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeDebit,
+		}, nil)
+		repoMock.On("TransactionGetByProviderUID", ctx, "p1", "dc-1", domain.TransactionTypeCredit).Return(&domain.Transaction{
+			UID:                    "credit-1",
+			ProviderUID:            "p1",
+			ProviderTransactionUID: "dc-1",
+			UserUID:                "123",
+			Amount:                 500,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeCredit,
+		}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "p1",
+			TransactionUID: "dc-1",
+		})
+
+		assert.Equal(t, domain.ErrRollbackAmbiguous, domain.AsError(err).Code)
+		assert.Nil(t, res)
+
+		repoMock.AssertNotCalled(t, "TransactionRollback", mock.Anything, mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback of debit by shared provider transaction id after its credit is rolled back", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.On("TransactionGetByProviderUID", ctx, "p1", "dc-1", domain.TransactionTypeDebit).Return(&domain.Transaction{
+			UID:                    "debit-1",
+			ProviderUID:            "p1",
+			ProviderTransactionUID: "dc-1",
+			UserUID:                "123",
+			Amount:                 100,
+			Currency:               "USD",
+			Denomination:           2,
+			Type:                   domain.TransactionTypeDebit,
+		}, nil)
+		repoMock.On("TransactionGetByProviderUID", ctx, "p1", "dc-1", domain.TransactionTypeCredit).Return(&domain.Transaction{
+			UID:                    "credit-1",
+			ProviderUID:            "p1",
+			ProviderTransactionUID: "dc-1",
+			Type:                   domain.TransactionTypeCredit,
+			Status:                 domain.TransactionStatusRolledBack,
+			RollbackTransactionUID: "rollback-1",
+		}, nil)
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD", Denomination: 2}, nil)
+
+		repoMock.
+			On("TransactionRollback", ctx, "debit-1", domain.TransactionMeta{}).
+			Return(&domain.Transaction{
+				UID:                  "rollback-2",
+				Amount:               100,
+				Balance:              1000,
+				Currency:             "USD",
+				Denomination:         2,
+				Type:                 domain.TransactionTypeRollback,
+				ParentTransactionUID: "debit-1",
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			ProviderUID:    "p1",
+			TransactionUID: "dc-1",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rollback-2", res.TransactionUID)
+		assert.Equal(t, 100, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("rollback unknown transaction tombstones converted wallet", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, settings)
+
+		repoMock.
+			On("TransactionGetByProviderUID", ctx, "", "321", mock.Anything).
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("TransactionGetByUID", ctx, "321").
+			Return(nil, domain.NewError(errorRollbackSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123", Nick: "test"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR", Denomination: 2}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, domain.NewError(errorBalanceSource).SetCode(domain.ErrNotFound))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Amount: 1100, Currency: "USD", Denomination: 2}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(&domain.ExchangeRate{From: "EUR", To: "USD", Rate: 1.1}, nil)
+
+		repoMock.
+			On("TransactionCreateTombstone", ctx, &domain.Transaction{
+				ProviderTransactionUID: "321",
+				UserUID:                "123",
+				Currency:               "USD",
+				OriginalCurrency:       "EUR",
+				ExchangeRate:           1.1,
+			}).
+			Return(&domain.Transaction{
+				UID:                    "tombstone",
+				ProviderTransactionUID: "321",
+				UserUID:                "123",
+				Currency:               "USD",
+				Denomination:           2,
+				OriginalCurrency:       "EUR",
+				ExchangeRate:           1.1,
+				Type:                   domain.TransactionTypeRollback,
+				Status:                 domain.TransactionStatusTombstone,
+				Balance:                1100,
+			}, nil)
+
+		res, err := service.Rollback(ctx, &domain.ProcessDebitCreditRollbackReq{
+			TransactionUID: "321",
+			UserUID:        "123",
+			Currency:       "EUR",
+			Amount:         100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "tombstone", res.TransactionUID)
+		assert.Equal(t, 0, res.Amount)
+		assert.Equal(t, 1000, res.Balance)
+		assert.Equal(t, "EUR", res.Currency)
+
+		repoMock.AssertExpectations(t)
+	})
 }
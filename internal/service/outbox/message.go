@@ -0,0 +1,52 @@
+package outbox
+
+import (
+	"open-api-games/internal/domain"
+	"time"
+)
+
+// message is the published form of the wallet movement event, amounts are in minor units of the currency
+type message struct {
+	EventUID               string    `json:"eventId"`
+	Type                   string    `json:"type"`
+	TransactionUID         string    `json:"transactionId"`
+	ProviderTransactionUID string    `json:"providerTransactionId,omitempty"`
+	ParentTransactionUID   string    `json:"parentTransactionId,omitempty"`
+	UserUID                string    `json:"userId"`
+	GameSessionUID         string    `json:"gameSessionId,omitempty"`
+	BetUID                 string    `json:"betId,omitempty"`
+	CampaignUID            string    `json:"campaignId,omitempty"`
+	Amount                 int       `json:"amount"`
+	BonusAmount            int       `json:"bonusAmount"`
+	Balance                int       `json:"balance"`
+	BonusBalance           int       `json:"bonusBalance"`
+	Currency               string    `json:"currency"`
+	Denomination           int       `json:"denomination"`
+	Operator               string    `json:"operator,omitempty"`
+	Reason                 string    `json:"reason,omitempty"`
+	OccurredAt             time.Time `json:"occurredAt"`
+}
+
+func messageOf(event *domain.OutboxEvent) message {
+	txn := &event.Transaction
+	return message{
+		EventUID:               event.UID,
+		Type:                   string(txn.Type),
+		TransactionUID:         txn.UID,
+		ProviderTransactionUID: txn.ProviderTransactionUID,
+		ParentTransactionUID:   txn.ParentTransactionUID,
+		UserUID:                txn.UserUID,
+		GameSessionUID:         txn.SessionUID,
+		BetUID:                 txn.RoundUID,
+		CampaignUID:            txn.CampaignUID,
+		Amount:                 txn.Amount,
+		BonusAmount:            txn.BonusAmount,
+		Balance:                txn.Balance,
+		BonusBalance:           txn.BonusBalance,
+		Currency:               txn.Currency,
+		Denomination:           txn.Denomination,
+		Operator:               txn.Operator,
+		Reason:                 txn.Reason,
+		OccurredAt:             txn.CreatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	errorOutboxSource = "[service.outbox]"
+)
+
+//go:generate mockery --dir . --name Repository --output ./mocks --case=underscore
+type Repository interface {
+	OutboxListPending(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error)
+	OutboxUpdate(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+//go:generate mockery --dir . --name Sink --output ./mocks --case=underscore
+
+// Sink publishes events of wallet movements to downstream services
+type Sink interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// Service relays events stored in the outbox along with wallet movements to the sink,
+// the event is marked delivered only after the sink accepts it, so every event is published at least once.
+// Pending events aren't claimed, the relays of several instances publish the same events, consumers drop
+// the repeated ones by event uid same as the retried ones
+type Service struct {
+	repo        Repository
+	sink        Sink
+	logger      *slog.Logger
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+// Settings tune the relay, zero Interval disables it, MaxAttempts failed deliveries make the event dead
+// and retries wait Backoff doubled with every attempt
+type Settings struct {
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+func New(repo Repository, sink Sink, logger *slog.Logger, settings Settings) *Service {
+	return &Service{
+		repo:        repo,
+		sink:        sink,
+		logger:      logger,
+		interval:    settings.Interval,
+		batchSize:   settings.BatchSize,
+		maxAttempts: settings.MaxAttempts,
+		backoff:     settings.Backoff,
+		now:         time.Now,
+	}
+}
+
+// Relay periodically publishes pending events until the context is done, full batch is followed by the next one at once
+func (s *Service) Relay(ctx context.Context) {
+	if s.sink == nil || s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				published, err := s.relay(ctx)
+				if err != nil {
+					s.logger.Error("failed to relay outbox events", "error", err)
+				}
+				if err != nil || published < s.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// relay publishes the batch of pending events, returns number of delivered events, the batch stops at the first
+// failed delivery, so unavailable sink isn't called for every event, the failed event is retried after its backoff
+func (s *Service) relay(ctx context.Context) (int, error) {
+	events, err := s.repo.OutboxListPending(ctx, s.now(), s.batchSize)
+	if err != nil {
+		return 0, domain.NewError(errorOutboxSource).SetCode(domain.ErrProcessingRequest).Add(err)
+	}
+
+	for i := range events {
+		event := &events[i]
+		errPublish := s.sink.Publish(ctx, event)
+		if errPublish == nil {
+			event.Delivered(s.now())
+		} else {
+			event.Failed(errPublish, s.now(), s.backoff, s.maxAttempts)
+			s.logFailed(event)
+		}
+
+		// delivered event failed to be marked is published again, consumers tell repeated events by uid
+		if err = s.repo.OutboxUpdate(ctx, event); err != nil {
+			return i, domain.NewError(errorOutboxSource).SetCode(domain.ErrProcessingRequest).Add(err)
+		}
+		if errPublish != nil {
+			return i, nil
+		}
+	}
+	return len(events), nil
+}
+
+func (s *Service) logFailed(event *domain.OutboxEvent) {
+	if event.Status == domain.OutboxStatusDead {
+		s.logger.Error(
+			"outbox event is dead",
+			"uid", event.UID,
+			"transactionUid", event.Transaction.UID,
+			"attempts", event.Attempts,
+			"error", event.LastError,
+		)
+		return
+	}
+	s.logger.Warn(
+		"failed to publish outbox event",
+		"uid", event.UID,
+		"attempts", event.Attempts,
+		"nextAttemptAt", event.NextAttemptAt,
+		"error", event.LastError,
+	)
+}
@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/outbox/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	now := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+
+	pending := func(uid string) domain.OutboxEvent {
+		return *domain.NewOutboxEvent(&domain.Transaction{UID: "txn-" + uid, Type: domain.TransactionTypeDebit, CreatedAt: now})
+	}
+
+	newService := func(repo Repository, sink Sink) *Service {
+		service := New(repo, sink, logger, Settings{Interval: time.Second, BatchSize: 10, MaxAttempts: 3, Backoff: time.Second})
+		service.now = func() time.Time { return now }
+		return service
+	}
+
+	t.Run("relay delivers pending events in order", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		sink := NewChannelSink(2)
+		service := newService(repoMock, sink)
+
+		first, second := pending("1"), pending("2")
+		repoMock.
+			On("OutboxListPending", ctx, now, 10).
+			Return([]domain.OutboxEvent{first, second}, nil)
+
+		repoMock.
+			On("OutboxUpdate", ctx, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+				return e.Status == domain.OutboxStatusDelivered && e.DeliveredAt.Equal(now)
+			})).
+			Return(nil).
+			Twice()
+
+		delivered, err := service.relay(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, delivered)
+		assert.Equal(t, first.UID, (<-sink.Events).UID)
+		assert.Equal(t, second.UID, (<-sink.Events).UID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("relay stops at failed delivery and schedules retry", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		sinkMock := &mocks.Sink{}
+		service := newService(repoMock, sinkMock)
+
+		first, second := pending("1"), pending("2")
+		repoMock.
+			On("OutboxListPending", ctx, now, 10).
+			Return([]domain.OutboxEvent{first, second}, nil)
+
+		sinkMock.
+			On("Publish", ctx, mock.Anything).
+			Return(errors.New("connection refused")).
+			Once()
+
+		repoMock.
+			On("OutboxUpdate", ctx, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+				return e.UID == first.UID &&
+					e.Status == domain.OutboxStatusPending &&
+					e.Attempts == 1 &&
+					e.LastError == "connection refused" &&
+					e.NextAttemptAt.Equal(now.Add(time.Second))
+			})).
+			Return(nil)
+
+		delivered, err := service.relay(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+
+		repoMock.AssertExpectations(t)
+		sinkMock.AssertExpectations(t)
+	})
+
+	t.Run("relay gives up event after last attempt", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		sinkMock := &mocks.Sink{}
+		service := newService(repoMock, sinkMock)
+
+		event := pending("1")
+		event.Attempts = 2
+		repoMock.
+			On("OutboxListPending", ctx, now, 10).
+			Return([]domain.OutboxEvent{event}, nil)
+
+		sinkMock.
+			On("Publish", ctx, mock.Anything).
+			Return(errors.New("webhook responded with status 500"))
+
+		repoMock.
+			On("OutboxUpdate", ctx, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+				return e.Status == domain.OutboxStatusDead && e.Attempts == 3
+			})).
+			Return(nil)
+
+		delivered, err := service.relay(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+
+		repoMock.AssertExpectations(t)
+		sinkMock.AssertExpectations(t)
+	})
+
+	t.Run("relay list error", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		sinkMock := &mocks.Sink{}
+		service := newService(repoMock, sinkMock)
+
+		repoMock.
+			On("OutboxListPending", ctx, now, 10).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrProcessingRequest))
+
+		delivered, err := service.relay(ctx)
+
+		assert.Equal(t, domain.ErrProcessingRequest, domain.AsError(err).Code)
+		assert.Equal(t, 0, delivered)
+
+		sinkMock.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+		repoMock.AssertExpectations(t)
+	})
+}
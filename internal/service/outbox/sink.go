@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"open-api-games/internal/domain"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// sinks selectable by config
+	SinkStdout  = "stdout"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+)
+
+// SinkSettings choose the sink the events are published to
+type SinkSettings struct {
+	// Kind is stdout, file or webhook, empty keeps events in the outbox
+	Kind           string
+	File           string
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+// NewSink makes the sink chosen by settings, nil sink keeps events in the outbox
+func NewSink(settings SinkSettings, logger *slog.Logger) (Sink, error) {
+	switch settings.Kind {
+	case "":
+		logger.Warn("outbox sink isn't configured, wallet movement events are kept in the outbox")
+		return nil, nil
+	case SinkStdout:
+		return NewWriterSink(os.Stdout), nil
+	case SinkFile:
+		file, err := os.OpenFile(settings.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			logger.Error("failed to open outbox file", "file", settings.File, "error", err)
+			return nil, domain.NewError(errorOutboxSource).SetCode(domain.ErrConfig).Add(err)
+		}
+		return &WriterSink{w: file, closer: file}, nil
+	case SinkWebhook:
+		if settings.WebhookURL == "" {
+			logger.Error("missing outbox webhook url")
+			return nil, domain.NewError(errorOutboxSource).SetCode(domain.ErrConfig)
+		}
+		return NewWebhookSink(settings.WebhookURL, settings.WebhookTimeout), nil
+	default:
+		logger.Error("unknown outbox sink", "sink", settings.Kind)
+		return nil, domain.NewError(errorOutboxSource).SetCode(domain.ErrConfig)
+	}
+}
+
+// WriterSink writes events as json lines
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+	// closer is the file the sink opened itself, the writer passed by the caller is left to the caller to close
+	closer io.Closer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Publish(_ context.Context, event *domain.OutboxEvent) error {
+	line, err := json.Marshal(messageOf(event))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file of the sink, the relay has to be stopped before
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// WebhookSink posts events to the url, any response but 2xx is the failed delivery,
+// Idempotency-Key header carries the event uid to drop repeated events
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	body, err := json.Marshal(messageOf(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.UID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ChannelSink hands events over to the in-process consumer, publishing waits for the consumer or the context
+type ChannelSink struct {
+	Events chan domain.OutboxEvent
+}
+
+func NewChannelSink(size int) *ChannelSink {
+	return &ChannelSink{Events: make(chan domain.OutboxEvent, size)}
+}
+
+func (s *ChannelSink) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	select {
+	case s.Events <- *event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"open-api-games/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	event := domain.NewOutboxEvent(&domain.Transaction{
+		UID:          "txn-1",
+		UserUID:      "123",
+		SessionUID:   "s-1",
+		RoundUID:     "round-1",
+		Type:         domain.TransactionTypeDebit,
+		Amount:       100,
+		Balance:      900,
+		Currency:     "USD",
+		Denomination: 2,
+		CreatedAt:    time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC),
+	})
+
+	t.Run("writer sink writes json line", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewWriterSink(&buf).Publish(ctx, event))
+
+		var msg message
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &msg))
+		assert.Equal(t, event.UID, msg.EventUID)
+		assert.Equal(t, "debit", msg.Type)
+		assert.Equal(t, "txn-1", msg.TransactionUID)
+		assert.Equal(t, "round-1", msg.BetUID)
+		assert.Equal(t, 900, msg.Balance)
+		assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1])
+	})
+
+	t.Run("webhook sink posts event with idempotency key", func(t *testing.T) {
+		var key string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key = r.Header.Get("Idempotency-Key")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		require.NoError(t, NewWebhookSink(server.URL, time.Second).Publish(ctx, event))
+		assert.Equal(t, event.UID, key)
+	})
+
+	t.Run("webhook sink fails on error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(server.URL, time.Second).Publish(ctx, event)
+		assert.EqualError(t, err, "webhook responded with status 502")
+	})
+
+	t.Run("channel sink respects context", func(t *testing.T) {
+		sink := NewChannelSink(0)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.ErrorIs(t, sink.Publish(cancelled, event), context.Canceled)
+	})
+
+	t.Run("file sink appends events and closes the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "outbox.jsonl")
+		sink, err := NewSink(SinkSettings{Kind: SinkFile, File: file}, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Publish(ctx, event))
+		require.NoError(t, sink.(io.Closer).Close())
+		assert.Error(t, sink.Publish(ctx, event))
+
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, 1, bytes.Count(content, []byte("\n")))
+	})
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// nonceCachePurgeInterval is how often expired nonces are removed from memory
+const nonceCachePurgeInterval = time.Minute
+
+// NonceCache remembers used nonces until they expire
+type NonceCache interface {
+	// Remember stores the nonce, false means the nonce has been used already
+	Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+}
+
+// MemoryNonceCache keeps nonces in process memory, it's enough while the api runs as single instance
+type MemoryNonceCache struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+func NewMemoryNonceCache() *MemoryNonceCache {
+	return &MemoryNonceCache{
+		nonces: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func (nc *MemoryNonceCache) Remember(_ context.Context, key string, expiresAt time.Time) (bool, error) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	now := nc.now()
+	if now.Sub(nc.lastPurge) >= nonceCachePurgeInterval {
+		for k, exp := range nc.nonces {
+			if !now.Before(exp) {
+				delete(nc.nonces, k)
+			}
+		}
+		nc.lastPurge = now
+	}
+
+	if exp, ok := nc.nonces[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	nc.nonces[key] = expiresAt
+	return true, nil
+}
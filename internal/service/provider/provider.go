@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"strconv"
+	"time"
+)
+
+const (
+	errorProviderSource = "[service.provider]"
+)
+
+//go:generate mockery --dir . --name Repository --output ./mocks --case=underscore
+type Repository interface {
+	ProviderGetByUID(ctx context.Context, uid string) (*domain.Provider, error)
+}
+
+type Service struct {
+	repo      Repository
+	logger    *slog.Logger
+	signers   map[domain.SignScheme]Signer
+	nonces    NonceCache
+	clockSkew time.Duration
+	now       func() time.Time
+}
+
+// Settings tune the request authentication
+type Settings struct {
+	// ClockSkew is the allowed difference between the request sign timestamp and the server time
+	ClockSkew time.Duration
+}
+
+func New(repo Repository, logger *slog.Logger, settings Settings) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+		signers: map[domain.SignScheme]Signer{
+			domain.SignSchemeHMACSHA256: HMACSigner{},
+			domain.SignSchemeMD5:        MD5Signer{},
+		},
+		nonces:    NewMemoryNonceCache(),
+		clockSkew: settings.ClockSkew,
+		now:       time.Now,
+	}
+}
+
+// Authenticate resolves the provider of the request and verifies the request is signed by one of its active secrets,
+// requests of timestamped schemes are accepted within clock skew window and only once
+func (s *Service) Authenticate(ctx context.Context, req *domain.ProviderAuthReq) (*domain.Provider, error) {
+	if req.ProviderUID == "" {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderEmpty)
+	}
+
+	provider, err := s.repo.ProviderGetByUID(ctx, req.ProviderUID)
+	if err != nil {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderNotFound).Add(err)
+	}
+
+	if !provider.Enabled {
+		s.logger.Warn("request of disabled provider", "provider", provider.UID)
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderDisabled)
+	}
+
+	if !provider.IsIPAllowed(req.IP) {
+		s.logger.Warn("request of provider from not allowed ip", "provider", provider.UID, "ip", req.IP)
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrProviderIP)
+	}
+
+	if req.Sign == "" {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrSignEmpty)
+	}
+
+	signer, ok := s.signers[provider.Scheme()]
+	if !ok {
+		s.logger.Error("unknown sign scheme of provider", "provider", provider.UID, "scheme", provider.SignScheme)
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrConfig)
+	}
+
+	var signedAt time.Time
+	if !signer.Replayable() {
+		signedAt, err = s.signTimestamp(req.Payload.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if req.Payload.Nonce == "" {
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrSignNonce)
+		}
+	}
+
+	if !s.verifySign(provider, signer, req) {
+		return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrSignInvalid)
+	}
+
+	// nonce is remembered only for the valid sign, so forged requests can't burn nonces of the provider
+	if !signer.Replayable() {
+		fresh, err := s.nonces.Remember(ctx, provider.UID+":"+req.Payload.Nonce, signedAt.Add(s.clockSkew))
+		if err != nil {
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrServer).Add(err)
+		}
+		if !fresh {
+			s.logger.Warn("replayed request of provider", "provider", provider.UID, "nonce", req.Payload.Nonce)
+			return nil, domain.NewError(errorProviderSource).SetCode(domain.ErrSignReplay)
+		}
+	}
+
+	return provider, nil
+}
+
+// SignResponse signs the response payload with the scheme and the current secret of the provider,
+// payload timestamp is set to the current time and the payload is marked as the response, so the sign never matches a request
+func (s *Service) SignResponse(_ context.Context, provider *domain.Provider, payload *domain.SignPayload) (string, error) {
+	signer, ok := s.signers[provider.Scheme()]
+	if !ok {
+		return "", domain.NewError(errorProviderSource).SetCode(domain.ErrConfig)
+	}
+	if len(provider.Secrets) == 0 || provider.Secrets[0] == "" {
+		return "", domain.NewError(errorProviderSource).SetCode(domain.ErrConfig)
+	}
+
+	payload.Timestamp = strconv.FormatInt(s.now().Unix(), 10)
+	payload.Response = true
+	return signer.Sign(provider.Secrets[0], payload), nil
+}
+
+// signTimestamp parses the unix timestamp of the sign and checks it's within clock skew window
+func (s *Service) signTimestamp(timestamp string) (time.Time, error) {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, domain.NewError(errorProviderSource).SetCode(domain.ErrSignTimestamp).Add(err)
+	}
+
+	signedAt := time.Unix(sec, 0)
+	skew := s.now().Sub(signedAt)
+	if skew > s.clockSkew || skew < -s.clockSkew {
+		return time.Time{}, domain.NewError(errorProviderSource).SetCode(domain.ErrSignTimestamp)
+	}
+	return signedAt, nil
+}
+
+// verifySign checks the sign against every active secret of the provider
+func (s *Service) verifySign(provider *domain.Provider, signer Signer, req *domain.ProviderAuthReq) bool {
+	for i, secret := range provider.Secrets {
+		if secret == "" {
+			continue
+		}
+		expected := signer.Sign(secret, &req.Payload)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(req.Sign)) == 1 {
+			if i > 0 {
+				s.logger.Info("request signed by previous secret of provider", "provider", provider.UID)
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/provider/mocks"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func md5Sign(body, secret string) string {
+	hash := md5.Sum([]byte(body + secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestHMACSigner(t *testing.T) {
+	t.Parallel()
+
+	payload := &domain.SignPayload{
+		Method:    "POST",
+		Path:      "/open-api-games/v1/games-processor",
+		Timestamp: "1700000000",
+		Nonce:     "n1",
+		Body:      []byte(`{"a":1}`),
+	}
+
+	assert.Equal(t, "73b7a5ab6a79ce1861574309d5fb3cb9db92f21b12cda1ea013508db8a76790f", HMACSigner{}.Sign("secret", payload))
+
+	payload.Path = "/open-api-games/v1/other"
+	assert.NotEqual(t, "73b7a5ab6a79ce1861574309d5fb3cb9db92f21b12cda1ea013508db8a76790f", HMACSigner{}.Sign("secret", payload))
+
+	// the response with the fields of the request is signed differently
+	payload.Path = "/open-api-games/v1/games-processor"
+	payload.Response = true
+	assert.NotEqual(t, "73b7a5ab6a79ce1861574309d5fb3cb9db92f21b12cda1ea013508db8a76790f", HMACSigner{}.Sign("secret", payload))
+}
+
+func TestMemoryNonceCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewMemoryNonceCache()
+	cache.now = func() time.Time { return now }
+
+	fresh, err := cache.Remember(ctx, "p1:n1", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = cache.Remember(ctx, "p1:n1", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, fresh)
+
+	fresh, err = cache.Remember(ctx, "p2:n1", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+
+	// expired nonce is purged and can't be replayed anyway, the timestamp is out of the window
+	now = now.Add(2 * time.Minute)
+	fresh, err = cache.Remember(ctx, "p1:n1", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+	assert.Len(t, cache.nonces, 1)
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	body := `{"api":"balance","data":{"gameSessionId":"123","currency":"USD"}}`
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newService := func(repoMock *mocks.Repository) *Service {
+		service := New(repoMock, logger, Settings{ClockSkew: 5 * time.Minute})
+		service.now = func() time.Time { return now }
+		nonces := NewMemoryNonceCache()
+		nonces.now = service.now
+		service.nonces = nonces
+		return service
+	}
+
+	provider := func() *domain.Provider {
+		return &domain.Provider{
+			UID:        "p1",
+			Name:       "provider",
+			Secrets:    []string{"current", "previous"},
+			AllowedIPs: []string{"10.0.0.1", "192.168.0.0/16"},
+			Enabled:    true,
+			Currencies: []string{"USD"},
+			SignScheme: domain.SignSchemeHMACSHA256,
+		}
+	}
+
+	// signed returns request signed by hmac with the secret at the moment
+	signed := func(secret string, at time.Time, nonce string) *domain.ProviderAuthReq {
+		req := &domain.ProviderAuthReq{
+			ProviderUID: "p1",
+			IP:          "10.0.0.1",
+			Payload: domain.SignPayload{
+				Method:    "POST",
+				Path:      "/open-api-games/v1/games-processor",
+				Timestamp: strconv.FormatInt(at.Unix(), 10),
+				Nonce:     nonce,
+				Body:      []byte(body),
+			},
+		}
+		req.Sign = HMACSigner{}.Sign(secret, &req.Payload)
+		return req
+	}
+
+	t.Run("signed by current secret", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		res, err := service.Authenticate(ctx, signed("current", now, "n1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p1", res.UID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("signed by previous secret during rotation", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("previous", now.Add(-time.Minute), "n1")
+		req.IP = "192.168.1.10"
+		res, err := service.Authenticate(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p1", res.UID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("signed by revoked secret", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		res, err := service.Authenticate(ctx, signed("revoked", now, "n1"))
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignInvalid, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("sign of another path", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		req.Payload.Path = "/open-api-games/v1/p1/games-processor"
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignInvalid, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("replayed request", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		_, err := service.Authenticate(ctx, req)
+		assert.NoError(t, err)
+
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignReplay, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("forged request doesn't burn nonce", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		_, err := service.Authenticate(ctx, signed("forged", now, "n1"))
+		assert.Equal(t, domain.ErrSignInvalid, domain.AsError(err).Code)
+
+		res, err := service.Authenticate(ctx, signed("current", now, "n1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p1", res.UID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("timestamp out of clock skew window", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		for _, at := range []time.Time{now.Add(-6 * time.Minute), now.Add(6 * time.Minute)} {
+			res, err := service.Authenticate(ctx, signed("current", at, "n1"))
+
+			assert.Error(t, err)
+			assert.Nil(t, res)
+			assert.Equal(t, domain.ErrSignTimestamp, domain.AsError(err).Code)
+		}
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		req.Payload.Timestamp = "yesterday"
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignTimestamp, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("empty nonce", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		res, err := service.Authenticate(ctx, signed("current", now, ""))
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignNonce, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("legacy md5 sign of opted in provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		legacy := provider()
+		legacy.SignScheme = domain.SignSchemeMD5
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(legacy, nil)
+
+		res, err := service.Authenticate(ctx, &domain.ProviderAuthReq{
+			ProviderUID: "p1",
+			IP:          "10.0.0.1",
+			Payload:     domain.SignPayload{Body: []byte(body)},
+			Sign:        md5Sign(body, "current"),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p1", res.UID)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("legacy md5 sign of hmac provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		req.Sign = md5Sign(body, "current")
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignInvalid, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("empty sign", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		req.Sign = ""
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSignEmpty, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("provider not provided", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		req := signed("current", now, "n1")
+		req.ProviderUID = ""
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderEmpty, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p2").Return(nil, domain.NewError("repo").SetCode(domain.ErrNotFound))
+
+		req := signed("current", now, "n1")
+		req.ProviderUID = "p2"
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("disabled provider", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		disabled := provider()
+		disabled.Enabled = false
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(disabled, nil)
+
+		res, err := service.Authenticate(ctx, signed("current", now, "n1"))
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderDisabled, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("ip is not allowed", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := newService(repoMock)
+
+		repoMock.On("ProviderGetByUID", ctx, "p1").Return(provider(), nil)
+
+		req := signed("current", now, "n1")
+		req.IP = "10.0.0.2"
+		res, err := service.Authenticate(ctx, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrProviderIP, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
+
+func TestSignResponse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"api":"balance","isSuccess":true}`
+
+	t.Run("hmac response is signed by current secret", func(t *testing.T) {
+		service := New(&mocks.Repository{}, logger, Settings{ClockSkew: 5 * time.Minute})
+		service.now = func() time.Time { return now }
+
+		provider := &domain.Provider{UID: "p1", Secrets: []string{"current", "previous"}, SignScheme: domain.SignSchemeHMACSHA256}
+		payload := &domain.SignPayload{
+			Method: "POST",
+			Path:   "/open-api-games/v1/games-processor",
+			Nonce:  "n1",
+			Body:   []byte(body),
+		}
+
+		sign, err := service.SignResponse(ctx, provider, payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, strconv.FormatInt(now.Unix(), 10), payload.Timestamp)
+		assert.True(t, payload.Response)
+		assert.Equal(t, HMACSigner{}.Sign("current", payload), sign)
+
+		// the sign of the response doesn't authenticate the request of the same fields
+		request := *payload
+		request.Response = false
+		assert.NotEqual(t, HMACSigner{}.Sign("current", &request), sign)
+	})
+
+	t.Run("legacy md5 response", func(t *testing.T) {
+		service := New(&mocks.Repository{}, logger, Settings{ClockSkew: 5 * time.Minute})
+		service.now = func() time.Time { return now }
+
+		provider := &domain.Provider{UID: "p1", Secrets: []string{"current"}, SignScheme: domain.SignSchemeMD5}
+
+		sign, err := service.SignResponse(ctx, provider, &domain.SignPayload{Body: []byte(body)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, md5Sign("response\n"+body, "current"), sign)
+	})
+
+	t.Run("provider without secret", func(t *testing.T) {
+		service := New(&mocks.Repository{}, logger, Settings{ClockSkew: 5 * time.Minute})
+
+		sign, err := service.SignResponse(ctx, &domain.Provider{UID: "p1"}, &domain.SignPayload{Body: []byte(body)})
+
+		assert.Error(t, err)
+		assert.Empty(t, sign)
+		assert.Equal(t, domain.ErrConfig, domain.AsError(err).Code)
+	})
+}
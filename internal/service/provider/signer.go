@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"open-api-games/internal/domain"
+)
+
+// Signer computes the sign of the payload with the provider secret
+type Signer interface {
+	// Sign returns hex encoded sign of the payload
+	Sign(secret string, payload *domain.SignPayload) string
+	// Replayable reports whether the sign doesn't cover timestamp and nonce, so replays can't be detected
+	Replayable() bool
+}
+
+// responsePrefix starts the signed payload of responses, requests never start with it
+const responsePrefix = "response\n"
+
+// HMACSigner signs method, path, timestamp, nonce and body joined by new line with hmac-sha256,
+// the payload of the response is prefixed with responsePrefix
+type HMACSigner struct{}
+
+func (HMACSigner) Sign(secret string, payload *domain.SignPayload) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	if payload.Response {
+		mac.Write([]byte(responsePrefix))
+	}
+	mac.Write([]byte(payload.Method + "\n" + payload.Path + "\n" + payload.Timestamp + "\n" + payload.Nonce + "\n"))
+	mac.Write(payload.Body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (HMACSigner) Replayable() bool {
+	return false
+}
+
+// MD5Signer is the legacy md5 of body concatenated with secret, the body of the response is prefixed with responsePrefix
+type MD5Signer struct{}
+
+func (MD5Signer) Sign(secret string, payload *domain.SignPayload) string {
+	hash := md5.New()
+	if payload.Response {
+		hash.Write([]byte(responsePrefix))
+	}
+	hash.Write(payload.Body)
+	hash.Write([]byte(secret))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (MD5Signer) Replayable() bool {
+	return true
+}
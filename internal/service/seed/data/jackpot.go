@@ -0,0 +1,17 @@
+package data
+
+import "open-api-games/internal/domain"
+
+func Jackpots() []domain.Jackpot {
+	return []domain.Jackpot{
+		{
+			Key:                 "FIRST_GAME_JACKPOT",
+			Name:                "First Game Jackpot",
+			Currency:            "USD",
+			GameUID:             "FIRST_GAME_UID",
+			Amount:              10000,
+			ContributionPercent: 1,
+			IsActive:            true,
+		},
+	}
+}
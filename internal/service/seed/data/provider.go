@@ -0,0 +1,16 @@
+package data
+
+import "open-api-games/internal/domain"
+
+func Providers() []domain.Provider {
+	return []domain.Provider{
+		{
+			UID:        "FIRST_PROVIDER_UID",
+			Name:       "First provider",
+			Secrets:    []string{"a1s2d3f4"},
+			Enabled:    true,
+			Currencies: []string{"USD"},
+			SignScheme: domain.SignSchemeHMACSHA256,
+		},
+	}
+}
@@ -5,8 +5,11 @@ import "open-api-games/internal/domain"
 func Sessions() []domain.Session {
 	return []domain.Session{
 		{
-			UID:     "FIRST_SESSION_UID",
-			UserUID: "FIRST_USER_UID",
+			UID:      "FIRST_SESSION_UID",
+			UserUID:  "FIRST_USER_UID",
+			GameUID:  "FIRST_GAME_UID",
+			Currency: "USD",
+			Status:   domain.SessionStatusOpen,
 		},
 	}
 }
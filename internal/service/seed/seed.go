@@ -16,6 +16,8 @@ type Repository interface {
 	SessionCreate(ctx context.Context, sess *domain.Session) error
 	CurrencyCreate(ctx context.Context, cur *domain.Currency) error
 	BalanceCreate(ctx context.Context, balance *domain.Balance) error
+	JackpotCreate(ctx context.Context, jackpot *domain.Jackpot) error
+	ProviderCreate(ctx context.Context, provider *domain.Provider) error
 }
 
 type Service struct {
@@ -51,5 +53,15 @@ func (s *Service) Seed(ctx context.Context) error {
 		s.db.BalanceCreate(ctx, &bl)
 	}
 
+	jackpots := data.Jackpots()
+	for _, jp := range jackpots {
+		s.db.JackpotCreate(ctx, &jp)
+	}
+
+	providers := data.Providers()
+	for _, p := range providers {
+		s.db.ProviderCreate(ctx, &p)
+	}
+
 	return nil
 }
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"time"
+)
+
+const (
+	errorSessionSource = "[service.session]"
+)
+
+//go:generate mockery --dir . --name Repository --output ./mocks --case=underscore
+type Repository interface {
+	UserGetByUID(ctx context.Context, uid string) (*domain.User, error)
+	CurrencyGetByCode(ctx context.Context, code string) (*domain.Currency, error)
+	BalanceGetByUserUIDAndCurrency(ctx context.Context, userUID, currency string) (*domain.Balance, error)
+	BalanceListByUserUID(ctx context.Context, userUID string) ([]domain.Balance, error)
+	ExchangeRateGet(ctx context.Context, from, to string) (*domain.ExchangeRate, error)
+	SessionCreate(ctx context.Context, sess *domain.Session) error
+	SessionClose(ctx context.Context, uid string, now time.Time) (*domain.Session, error)
+	SessionExpire(ctx context.Context, now time.Time) (int, error)
+}
+
+type Service struct {
+	repo          Repository
+	logger        *slog.Logger
+	ttl           time.Duration
+	sweepInterval time.Duration
+	now           func() time.Time
+}
+
+// Settings tune the session lifetime, TTL is the idle time the session expires after, zero TTL keeps sessions open until closed
+// and zero SweepInterval disables the sweep
+type Settings struct {
+	TTL           time.Duration
+	SweepInterval time.Duration
+}
+
+func New(repo Repository, logger *slog.Logger, settings Settings) *Service {
+	return &Service{
+		repo:          repo,
+		logger:        logger,
+		ttl:           settings.TTL,
+		sweepInterval: settings.SweepInterval,
+		now:           time.Now,
+	}
+}
+
+// Open launches the game session of the user in the currency, the user without balance in it plays
+// from the balance the game currency is exchanged into
+func (s *Service) Open(ctx context.Context, req *domain.SessionOpenReq) (*domain.Session, error) {
+	user, err := s.repo.UserGetByUID(ctx, req.UserUID)
+	if err != nil {
+		return nil, domain.NewError(errorSessionSource).SetCode(domain.ErrUserNotFound).Add(err)
+	}
+
+	_, err = s.repo.CurrencyGetByCode(ctx, req.Currency)
+	if err != nil {
+		return nil, domain.NewError(errorSessionSource).SetCode(domain.ErrUnknownCurrency).Add(err)
+	}
+
+	walletCurrency, err := s.walletCurrency(ctx, user.UID, req.Currency)
+	if err != nil {
+		return nil, domain.NewError(errorSessionSource).SetCode(domain.ErrBalanceNotFound).Add(err)
+	}
+
+	now := s.now()
+	session := &domain.Session{
+		UID:            domain.GenUID(),
+		UserUID:        user.UID,
+		GameUID:        req.GameUID,
+		ProviderUID:    req.ProviderUID,
+		Currency:       req.Currency,
+		WalletCurrency: walletCurrency,
+		MaxWin:         req.MaxWin,
+		Status:         domain.SessionStatusOpen,
+		CreatedAt:      now,
+		LastActivityAt: now,
+	}
+	if s.ttl > 0 {
+		session.ExpiresAt = now.Add(s.ttl)
+	}
+
+	err = s.repo.SessionCreate(ctx, session)
+	if err != nil {
+		return nil, domain.NewError(errorSessionSource).SetCode(domain.ErrRepoCreate).Add(err)
+	}
+
+	return session, nil
+}
+
+// walletCurrency finds the currency of the balance the session plays from, it's the game currency when the user has
+// balance in it, otherwise the first balance having exchange rate from the game currency either way
+func (s *Service) walletCurrency(ctx context.Context, userUID, currency string) (string, error) {
+	_, err := s.repo.BalanceGetByUserUIDAndCurrency(ctx, userUID, currency)
+	if err == nil {
+		return currency, nil
+	}
+
+	balances, errList := s.repo.BalanceListByUserUID(ctx, userUID)
+	if errList != nil {
+		return "", errList
+	}
+	for _, balance := range balances {
+		if _, errRate := s.repo.ExchangeRateGet(ctx, currency, balance.Currency); errRate == nil {
+			return balance.Currency, nil
+		}
+		if _, errRate := s.repo.ExchangeRateGet(ctx, balance.Currency, currency); errRate == nil {
+			return balance.Currency, nil
+		}
+	}
+	return "", err
+}
+
+// Close closes the game session, rounds in flight still can be settled by credits and rollbacks
+func (s *Service) Close(ctx context.Context, uid string) (*domain.Session, error) {
+	session, err := s.repo.SessionClose(ctx, uid, s.now())
+	if err != nil {
+		return nil, domain.NewError(errorSessionSource).SetCode(domain.ErrSessionClose).Add(err)
+	}
+	return session, nil
+}
+
+// Sweep periodically marks expired sessions until the context is done
+func (s *Service) Sweep(ctx context.Context) {
+	if s.sweepInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.repo.SessionExpire(ctx, s.now())
+			if err != nil {
+				s.logger.Error("failed to expire sessions", "error", err)
+				continue
+			}
+			if expired > 0 {
+				s.logger.Info("sessions expired", "count", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/service/session/mocks"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("open session success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{TTL: time.Hour})
+		service.now = func() time.Time { return now }
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "USD").
+			Return(&domain.Currency{Code: "USD"}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "USD").
+			Return(&domain.Balance{UserUID: "123", Currency: "USD"}, nil)
+
+		repoMock.
+			On("SessionCreate", ctx, mock.MatchedBy(func(s *domain.Session) bool {
+				return s.UID != "" &&
+					s.UserUID == "123" &&
+					s.GameUID == "game" &&
+					s.Currency == "USD" &&
+					s.WalletCurrency == "USD" &&
+					s.Status == domain.SessionStatusOpen &&
+					s.ExpiresAt.Equal(now.Add(time.Hour))
+			})).
+			Return(nil)
+
+		res, err := service.Open(ctx, &domain.SessionOpenReq{
+			UserUID:  "123",
+			GameUID:  "game",
+			Currency: "USD",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SessionStatusOpen, res.Status)
+		assert.Equal(t, now, res.CreatedAt)
+		assert.Equal(t, now, res.LastActivityAt)
+		assert.True(t, res.IsActive(now))
+		assert.False(t, res.IsActive(now.Add(time.Hour)))
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("open session without balance", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{TTL: time.Hour})
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR"}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Currency: "USD"}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "USD", "EUR").
+			Return(nil, errors.New("not found"))
+
+		res, err := service.Open(ctx, &domain.SessionOpenReq{
+			UserUID:  "123",
+			Currency: "EUR",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrBalanceNotFound, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("open session in currency exchanged into wallet", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{TTL: time.Hour})
+
+		repoMock.
+			On("UserGetByUID", ctx, "123").
+			Return(&domain.User{UID: "123"}, nil)
+
+		repoMock.
+			On("CurrencyGetByCode", ctx, "EUR").
+			Return(&domain.Currency{Code: "EUR"}, nil)
+
+		repoMock.
+			On("BalanceGetByUserUIDAndCurrency", ctx, "123", "EUR").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("BalanceListByUserUID", ctx, "123").
+			Return([]domain.Balance{{UserUID: "123", Currency: "GBP"}, {UserUID: "123", Currency: "USD"}}, nil)
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "GBP").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "GBP", "EUR").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "EUR", "USD").
+			Return(nil, errors.New("not found"))
+
+		repoMock.
+			On("ExchangeRateGet", ctx, "USD", "EUR").
+			Return(&domain.ExchangeRate{From: "USD", To: "EUR", Rate: 0.9}, nil)
+
+		repoMock.
+			On("SessionCreate", ctx, mock.MatchedBy(func(s *domain.Session) bool {
+				return s.Currency == "EUR" && s.WalletCurrency == "USD"
+			})).
+			Return(nil)
+
+		res, err := service.Open(ctx, &domain.SessionOpenReq{
+			UserUID:  "123",
+			GameUID:  "game",
+			Currency: "EUR",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", res.Currency)
+		assert.Equal(t, "USD", res.WalletCurrency)
+
+		repoMock.AssertExpectations(t)
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("close session success", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{TTL: time.Hour})
+
+		repoMock.
+			On("SessionClose", ctx, "123", mock.Anything).
+			Return(&domain.Session{UID: "123", Status: domain.SessionStatusClosed}, nil)
+
+		res, err := service.Close(ctx, "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SessionStatusClosed, res.Status)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("close session error", func(t *testing.T) {
+		repoMock := &mocks.Repository{}
+		service := New(repoMock, logger, Settings{TTL: time.Hour})
+
+		repoMock.
+			On("SessionClose", ctx, "123", mock.Anything).
+			Return(nil, errors.New("not found"))
+
+		res, err := service.Close(ctx, "123")
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, domain.ErrSessionClose, domain.AsError(err).Code)
+
+		repoMock.AssertExpectations(t)
+	})
+}
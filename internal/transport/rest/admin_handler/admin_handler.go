@@ -0,0 +1,608 @@
+package admin_handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/transport/rest/model"
+	"strings"
+	"time"
+)
+
+const (
+	errorSource = "[transport.rest.admin_handler]"
+
+	// operatorKey is the echo context key of the authenticated operator name
+	operatorKey = "operator"
+)
+
+type AdminService interface {
+	UserGet(ctx context.Context, uid string) (*domain.User, error)
+	UserList(ctx context.Context, page domain.Pagination) (*domain.UserPage, error)
+	UserCreate(ctx context.Context, user *domain.User) (*domain.User, error)
+	UserUpdate(ctx context.Context, user *domain.User) (*domain.User, error)
+	UserDelete(ctx context.Context, uid string) error
+	CurrencyGet(ctx context.Context, code string) (*domain.Currency, error)
+	CurrencyList(ctx context.Context) ([]domain.Currency, error)
+	CurrencyCreate(ctx context.Context, cur *domain.Currency) (*domain.Currency, error)
+	CurrencyUpdate(ctx context.Context, cur *domain.Currency) (*domain.Currency, error)
+	CurrencyDelete(ctx context.Context, code string) error
+	BalanceList(ctx context.Context, userUID string) ([]domain.Balance, error)
+	BalanceCreate(ctx context.Context, userUID, currency string) (*domain.Balance, error)
+	BalanceAdjust(ctx context.Context, req *domain.BalanceAdjustReq) (*domain.Transaction, error)
+	TransactionList(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error)
+	ProviderGet(ctx context.Context, uid string) (*domain.Provider, error)
+	ProviderList(ctx context.Context) ([]domain.Provider, error)
+	ProviderCreate(ctx context.Context, provider *domain.Provider) (*domain.Provider, error)
+	ProviderUpdate(ctx context.Context, provider *domain.Provider) (*domain.Provider, error)
+	ProviderDelete(ctx context.Context, uid string) error
+	ProviderRotateSecret(ctx context.Context, uid, secret string) (*domain.Provider, error)
+	ProviderFinishRotation(ctx context.Context, uid string) (*domain.Provider, error)
+	ExchangeRateList(ctx context.Context) ([]domain.ExchangeRate, error)
+	ExchangeRateSave(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	ExchangeRateImport(ctx context.Context, rates []domain.ExchangeRate) ([]domain.ExchangeRate, error)
+	ExchangeRateDelete(ctx context.Context, from, to string) error
+	CampaignCreate(ctx context.Context, campaign *domain.Campaign) (*domain.Campaign, error)
+	CampaignGet(ctx context.Context, uid string) (*domain.Campaign, error)
+	CampaignList(ctx context.Context, filter domain.CampaignFilter) ([]domain.Campaign, error)
+	CampaignCancel(ctx context.Context, uid, operator string) (*domain.Campaign, error)
+}
+
+type SessionService interface {
+	Open(ctx context.Context, req *domain.SessionOpenReq) (*domain.Session, error)
+	Close(ctx context.Context, uid string) (*domain.Session, error)
+}
+
+type Handler struct {
+	adminService   AdminService
+	sessionService SessionService
+	logger         *slog.Logger
+	// adminTokens maps operator name to its admin api token
+	adminTokens map[string]string
+}
+
+func New(adminService AdminService, sessionService SessionService, logger *slog.Logger, adminTokens map[string]string) *Handler {
+	return &Handler{
+		adminService:   adminService,
+		sessionService: sessionService,
+		logger:         logger,
+		adminTokens:    adminTokens,
+	}
+}
+
+// Register mounts admin routes to the group
+func (h *Handler) Register(g *echo.Group) {
+	g.GET("/users", h.UserList)
+	g.POST("/users", h.UserCreate)
+	g.GET("/users/:uid", h.UserGet)
+	g.PUT("/users/:uid", h.UserUpdate)
+	g.DELETE("/users/:uid", h.UserDelete)
+	g.GET("/users/:uid/balances", h.BalanceList)
+	g.POST("/users/:uid/balances", h.BalanceCreate)
+	g.POST("/users/:uid/balances/:currency/adjustments", h.BalanceAdjust)
+
+	g.GET("/currencies", h.CurrencyList)
+	g.POST("/currencies", h.CurrencyCreate)
+	g.GET("/currencies/:code", h.CurrencyGet)
+	g.PUT("/currencies/:code", h.CurrencyUpdate)
+	g.DELETE("/currencies/:code", h.CurrencyDelete)
+
+	g.GET("/exchange-rates", h.ExchangeRateList)
+	g.POST("/exchange-rates/import", h.ExchangeRateImport)
+	g.PUT("/exchange-rates/:from/:to", h.ExchangeRateSave)
+	g.DELETE("/exchange-rates/:from/:to", h.ExchangeRateDelete)
+
+	g.GET("/providers", h.ProviderList)
+	g.POST("/providers", h.ProviderCreate)
+	g.GET("/providers/:uid", h.ProviderGet)
+	g.PUT("/providers/:uid", h.ProviderUpdate)
+	g.DELETE("/providers/:uid", h.ProviderDelete)
+	g.POST("/providers/:uid/secrets", h.ProviderRotateSecret)
+	g.DELETE("/providers/:uid/secrets/previous", h.ProviderFinishRotation)
+
+	g.GET("/campaigns", h.CampaignList)
+	g.POST("/campaigns", h.CampaignCreate)
+	g.GET("/campaigns/:uid", h.CampaignGet)
+	g.DELETE("/campaigns/:uid", h.CampaignCancel)
+
+	g.POST("/sessions", h.SessionOpen)
+	g.POST("/sessions/:uid/close", h.SessionClose)
+
+	g.GET("/transactions", h.TransactionList)
+}
+
+// CheckAuth authenticates operator by the bearer token from admin tokens config
+func (h *Handler) CheckAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, found := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !found || token == "" {
+			return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrUnauthorized))
+		}
+
+		for operator, operatorToken := range h.adminTokens {
+			if operatorToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(operatorToken)) == 1 {
+				c.Set(operatorKey, operator)
+				return next(c)
+			}
+		}
+
+		h.logger.Warn("admin authentication failed", "path", c.Request().URL.Path, "ip", c.RealIP())
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrUnauthorized))
+	}
+}
+
+func (h *Handler) UserList(c echo.Context) error {
+	page, err := paginationFromQuery(c)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	users, err := h.adminService.UserList(c.Request().Context(), page)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	page = page.Normalize()
+	res := model.AdminPageRes[model.AdminUserRes]{
+		Items:  make([]model.AdminUserRes, 0, len(users.Users)),
+		Total:  users.Total,
+		Offset: page.Offset,
+		Limit:  page.Limit,
+	}
+	for i := range users.Users {
+		res.Items = append(res.Items, userToTransport(&users.Users[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) UserGet(c echo.Context) error {
+	user, err := h.adminService.UserGet(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, userToTransport(user))
+}
+
+func (h *Handler) UserCreate(c echo.Context) error {
+	req := &model.AdminUserReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	user, err := h.adminService.UserCreate(c.Request().Context(), &domain.User{
+		UID:                  req.UserUID,
+		Nick:                 req.Nick,
+		MaxWin:               req.MaxWin,
+		Limits:               gamingLimitsFromTransport(req.Limits),
+		SelfExcludedUntil:    req.SelfExcludedUntil,
+		CoolOffUntil:         req.CoolOffUntil,
+		SessionLimit:         time.Duration(req.SessionLimitMinutes) * time.Minute,
+		RealityCheckInterval: time.Duration(req.RealityCheckMinutes) * time.Minute,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, userToTransport(user))
+}
+
+func (h *Handler) UserUpdate(c echo.Context) error {
+	req := &model.AdminUserReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	user, err := h.adminService.UserUpdate(c.Request().Context(), &domain.User{
+		UID:                  c.Param("uid"),
+		Nick:                 req.Nick,
+		MaxWin:               req.MaxWin,
+		Limits:               gamingLimitsFromTransport(req.Limits),
+		SelfExcludedUntil:    req.SelfExcludedUntil,
+		CoolOffUntil:         req.CoolOffUntil,
+		SessionLimit:         time.Duration(req.SessionLimitMinutes) * time.Minute,
+		RealityCheckInterval: time.Duration(req.RealityCheckMinutes) * time.Minute,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, userToTransport(user))
+}
+
+func (h *Handler) UserDelete(c echo.Context) error {
+	err := h.adminService.UserDelete(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) BalanceList(c echo.Context) error {
+	balances, err := h.adminService.BalanceList(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	res := make([]model.AdminBalanceRes, 0, len(balances))
+	for i := range balances {
+		res = append(res, balanceToTransport(&balances[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) BalanceCreate(c echo.Context) error {
+	req := &model.AdminBalanceReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	balance, err := h.adminService.BalanceCreate(c.Request().Context(), c.Param("uid"), req.Currency)
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, balanceToTransport(balance))
+}
+
+func (h *Handler) BalanceAdjust(c echo.Context) error {
+	req := &model.AdminAdjustmentReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	operator, _ := c.Get(operatorKey).(string)
+	txn, err := h.adminService.BalanceAdjust(c.Request().Context(), &domain.BalanceAdjustReq{
+		UserUID:  c.Param("uid"),
+		Currency: c.Param("currency"),
+		Amount:   req.Amount,
+		Bonus:    req.Bonus,
+		Wagering: req.Wagering,
+		Operator: operator,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, transactionToTransport(txn))
+}
+
+func (h *Handler) CurrencyList(c echo.Context) error {
+	currencies, err := h.adminService.CurrencyList(c.Request().Context())
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	res := make([]model.AdminCurrencyRes, 0, len(currencies))
+	for i := range currencies {
+		res = append(res, currencyToTransport(&currencies[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) CurrencyGet(c echo.Context) error {
+	cur, err := h.adminService.CurrencyGet(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, currencyToTransport(cur))
+}
+
+func (h *Handler) CurrencyCreate(c echo.Context) error {
+	req := &model.AdminCurrencyReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	cur, err := h.adminService.CurrencyCreate(c.Request().Context(), &domain.Currency{
+		Code:         req.Code,
+		Denomination: req.Denomination,
+		MaxWin:       req.MaxWin,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, currencyToTransport(cur))
+}
+
+func (h *Handler) CurrencyUpdate(c echo.Context) error {
+	req := &model.AdminCurrencyReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	cur, err := h.adminService.CurrencyUpdate(c.Request().Context(), &domain.Currency{
+		Code:         c.Param("code"),
+		Denomination: req.Denomination,
+		MaxWin:       req.MaxWin,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, currencyToTransport(cur))
+}
+
+func (h *Handler) CurrencyDelete(c echo.Context) error {
+	err := h.adminService.CurrencyDelete(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) ExchangeRateList(c echo.Context) error {
+	rates, err := h.adminService.ExchangeRateList(c.Request().Context())
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, exchangeRatesToTransport(rates))
+}
+
+func (h *Handler) ExchangeRateSave(c echo.Context) error {
+	req := &model.AdminExchangeRateReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	rate, err := h.adminService.ExchangeRateSave(c.Request().Context(), &domain.ExchangeRate{
+		From: c.Param("from"),
+		To:   c.Param("to"),
+		Rate: req.Rate,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, exchangeRateToTransport(rate))
+}
+
+// ExchangeRateImport saves the rates from csv body of "from,to,rate" lines, the header line is optional
+func (h *Handler) ExchangeRateImport(c echo.Context) error {
+	rates, err := exchangeRatesFromCSV(c.Request().Body)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	rates, err = h.adminService.ExchangeRateImport(c.Request().Context(), rates)
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, exchangeRatesToTransport(rates))
+}
+
+func (h *Handler) ExchangeRateDelete(c echo.Context) error {
+	err := h.adminService.ExchangeRateDelete(c.Request().Context(), c.Param("from"), c.Param("to"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) ProviderList(c echo.Context) error {
+	providers, err := h.adminService.ProviderList(c.Request().Context())
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	res := make([]model.AdminProviderRes, 0, len(providers))
+	for i := range providers {
+		res = append(res, providerToTransport(&providers[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) ProviderGet(c echo.Context) error {
+	provider, err := h.adminService.ProviderGet(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, providerToTransport(provider))
+}
+
+func (h *Handler) ProviderCreate(c echo.Context) error {
+	req := &model.AdminProviderReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	provider := &domain.Provider{
+		UID:          req.ProviderUID,
+		Name:         req.Name,
+		AllowedIPs:   req.AllowedIPs,
+		Enabled:      req.Enabled,
+		Currencies:   req.Currencies,
+		SignScheme:   domain.SignScheme(req.SignScheme),
+		AmountFormat: domain.AmountFormat(req.AmountFormat),
+	}
+	if req.Secret != "" {
+		provider.Secrets = []string{req.Secret}
+	}
+
+	provider, err := h.adminService.ProviderCreate(c.Request().Context(), provider)
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, providerWithSecretToTransport(provider))
+}
+
+func (h *Handler) ProviderUpdate(c echo.Context) error {
+	req := &model.AdminProviderReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	provider, err := h.adminService.ProviderUpdate(c.Request().Context(), &domain.Provider{
+		UID:          c.Param("uid"),
+		Name:         req.Name,
+		AllowedIPs:   req.AllowedIPs,
+		Enabled:      req.Enabled,
+		Currencies:   req.Currencies,
+		SignScheme:   domain.SignScheme(req.SignScheme),
+		AmountFormat: domain.AmountFormat(req.AmountFormat),
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, providerToTransport(provider))
+}
+
+func (h *Handler) ProviderDelete(c echo.Context) error {
+	err := h.adminService.ProviderDelete(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) ProviderRotateSecret(c echo.Context) error {
+	req := &model.AdminProviderSecretReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	provider, err := h.adminService.ProviderRotateSecret(c.Request().Context(), c.Param("uid"), req.Secret)
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, providerWithSecretToTransport(provider))
+}
+
+func (h *Handler) ProviderFinishRotation(c echo.Context) error {
+	provider, err := h.adminService.ProviderFinishRotation(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, providerToTransport(provider))
+}
+
+func (h *Handler) SessionOpen(c echo.Context) error {
+	req := &model.AdminSessionReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	session, err := h.sessionService.Open(c.Request().Context(), &domain.SessionOpenReq{
+		UserUID:     req.UserUID,
+		GameUID:     req.GameUID,
+		ProviderUID: req.ProviderUID,
+		Currency:    req.Currency,
+		MaxWin:      req.MaxWin,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, sessionToTransport(session))
+}
+
+func (h *Handler) SessionClose(c echo.Context) error {
+	session, err := h.sessionService.Close(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, sessionToTransport(session))
+}
+
+func (h *Handler) CampaignList(c echo.Context) error {
+	campaigns, err := h.adminService.CampaignList(c.Request().Context(), domain.CampaignFilter{
+		UserUID: c.QueryParam("userId"),
+		Status:  domain.CampaignStatus(c.QueryParam("status")),
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	res := make([]model.AdminCampaignRes, 0, len(campaigns))
+	for i := range campaigns {
+		res = append(res, campaignToTransport(&campaigns[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) CampaignGet(c echo.Context) error {
+	campaign, err := h.adminService.CampaignGet(c.Request().Context(), c.Param("uid"))
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, campaignToTransport(campaign))
+}
+
+func (h *Handler) CampaignCreate(c echo.Context) error {
+	req := &model.AdminCampaignReq{}
+	if err := c.Bind(req); err != nil {
+		return h.error(c, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+	}
+
+	operator, _ := c.Get(operatorKey).(string)
+	campaign, err := h.adminService.CampaignCreate(c.Request().Context(), &domain.Campaign{
+		UserUID:            req.UserUID,
+		GameUID:            req.GameUID,
+		Currency:           req.Currency,
+		Rounds:             req.Rounds,
+		BetValue:           req.BetValue,
+		Win:                domain.CampaignWin(req.Win),
+		WageringMultiplier: req.WageringMultiplier,
+		Operator:           operator,
+		ExpiresAt:          req.ExpiresAt,
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusCreated, campaignToTransport(campaign))
+}
+
+func (h *Handler) CampaignCancel(c echo.Context) error {
+	operator, _ := c.Get(operatorKey).(string)
+	campaign, err := h.adminService.CampaignCancel(c.Request().Context(), c.Param("uid"), operator)
+	if err != nil {
+		return h.error(c, err)
+	}
+	return c.JSON(http.StatusOK, campaignToTransport(campaign))
+}
+
+func (h *Handler) TransactionList(c echo.Context) error {
+	page, err := paginationFromQuery(c)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	filter := domain.TransactionFilter{
+		UserUID:                c.QueryParam("userId"),
+		SessionUID:             c.QueryParam("gameSessionId"),
+		RoundUID:               c.QueryParam("betId"),
+		ProviderTransactionUID: c.QueryParam("providerTransactionId"),
+		Currency:               c.QueryParam("currency"),
+		Type:                   domain.TransactionType(c.QueryParam("type")),
+		Status:                 domain.TransactionStatus(c.QueryParam("status")),
+		Pagination:             page,
+	}
+	if filter.From, err = timeFromQuery(c, "from"); err != nil {
+		return h.error(c, err)
+	}
+	if filter.To, err = timeFromQuery(c, "to"); err != nil {
+		return h.error(c, err)
+	}
+
+	transactions, err := h.adminService.TransactionList(c.Request().Context(), filter)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	page = page.Normalize()
+	res := model.AdminPageRes[model.AdminTransactionRes]{
+		Items:  make([]model.AdminTransactionRes, 0, len(transactions.Transactions)),
+		Total:  transactions.Total,
+		Offset: page.Offset,
+		Limit:  page.Limit,
+	}
+	for i := range transactions.Transactions {
+		res.Items = append(res.Items, transactionToTransport(&transactions.Transactions[i]))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// error writes error response with http status matching the error code in the error catalogue
+func (h *Handler) error(c echo.Context, err error) error {
+	code := domain.AsError(err).Code
+	info := domain.ErrorInfoOf(code)
+
+	h.logger.Error("error processing admin request", "path", c.Request().URL.Path, "status", info.HTTPStatus, "error", err)
+	return c.JSON(info.HTTPStatus, model.AdminErrorRes{Error: code, Message: info.Message})
+}
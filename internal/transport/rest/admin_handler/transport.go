@@ -0,0 +1,250 @@
+package admin_handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/transport/rest/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func paginationFromQuery(c echo.Context) (domain.Pagination, error) {
+	var page domain.Pagination
+	var err error
+	if v := c.QueryParam("offset"); v != "" {
+		if page.Offset, err = strconv.Atoi(v); err != nil {
+			return page, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err)
+		}
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		if page.Limit, err = strconv.Atoi(v); err != nil {
+			return page, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err)
+		}
+	}
+	return page, nil
+}
+
+func timeFromQuery(c echo.Context, name string) (time.Time, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err)
+	}
+	return t, nil
+}
+
+// exchangeRatesFromCSV parses "from,to,rate" lines, the first line is skipped when it's a header
+func exchangeRatesFromCSV(r io.Reader) ([]domain.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err)
+	}
+	line := 1
+	if len(records) > 0 && strings.EqualFold(records[0][0], "from") {
+		records = records[1:]
+		line++
+	}
+
+	rates := make([]domain.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		rate, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(fmt.Errorf("line %d: %w", line+i, err))
+		}
+		rates = append(rates, domain.ExchangeRate{
+			From: strings.ToUpper(record[0]),
+			To:   strings.ToUpper(record[1]),
+			Rate: rate,
+		})
+	}
+	return rates, nil
+}
+
+func userToTransport(user *domain.User) model.AdminUserRes {
+	res := model.AdminUserRes{
+		UserUID:             user.UID,
+		Nick:                user.Nick,
+		MaxWin:              user.MaxWin,
+		SessionLimitMinutes: int(user.SessionLimit / time.Minute),
+		RealityCheckMinutes: int(user.RealityCheckInterval / time.Minute),
+	}
+	if len(user.Limits) > 0 {
+		res.Limits = make(map[string]model.AdminGamingLimits, len(user.Limits))
+		for currency, limits := range user.Limits {
+			res.Limits[currency] = model.AdminGamingLimits{
+				DailyLoss:      limits.Loss.Day,
+				WeeklyLoss:     limits.Loss.Week,
+				MonthlyLoss:    limits.Loss.Month,
+				DailyWager:     limits.Wager.Day,
+				WeeklyWager:    limits.Wager.Week,
+				MonthlyWager:   limits.Wager.Month,
+				DailyDeposit:   limits.Deposit.Day,
+				WeeklyDeposit:  limits.Deposit.Week,
+				MonthlyDeposit: limits.Deposit.Month,
+			}
+		}
+	}
+	if !user.SelfExcludedUntil.IsZero() {
+		res.SelfExcludedUntil = &user.SelfExcludedUntil
+	}
+	if !user.CoolOffUntil.IsZero() {
+		res.CoolOffUntil = &user.CoolOffUntil
+	}
+	return res
+}
+
+func gamingLimitsFromTransport(limits map[string]model.AdminGamingLimits) map[string]domain.GamingLimits {
+	if len(limits) == 0 {
+		return nil
+	}
+	res := make(map[string]domain.GamingLimits, len(limits))
+	for currency, l := range limits {
+		res[currency] = domain.GamingLimits{
+			Loss:    domain.PeriodLimits{Day: l.DailyLoss, Week: l.WeeklyLoss, Month: l.MonthlyLoss},
+			Wager:   domain.PeriodLimits{Day: l.DailyWager, Week: l.WeeklyWager, Month: l.MonthlyWager},
+			Deposit: domain.PeriodLimits{Day: l.DailyDeposit, Week: l.WeeklyDeposit, Month: l.MonthlyDeposit},
+		}
+	}
+	return res
+}
+
+func currencyToTransport(cur *domain.Currency) model.AdminCurrencyRes {
+	return model.AdminCurrencyRes{
+		Code:         cur.Code,
+		Denomination: cur.Denomination,
+		MaxWin:       cur.MaxWin,
+	}
+}
+
+func providerToTransport(provider *domain.Provider) model.AdminProviderRes {
+	return model.AdminProviderRes{
+		ProviderUID:  provider.UID,
+		Name:         provider.Name,
+		AllowedIPs:   provider.AllowedIPs,
+		Enabled:      provider.Enabled,
+		Currencies:   provider.Currencies,
+		SignScheme:   string(provider.Scheme()),
+		AmountFormat: string(provider.Format()),
+		SecretsCount: len(provider.Secrets),
+	}
+}
+
+// providerWithSecretToTransport adds current secret to the response, used when the secret is just created
+func providerWithSecretToTransport(provider *domain.Provider) model.AdminProviderRes {
+	res := providerToTransport(provider)
+	if len(provider.Secrets) > 0 {
+		res.Secret = provider.Secrets[0]
+	}
+	return res
+}
+
+func exchangeRateToTransport(rate *domain.ExchangeRate) model.AdminExchangeRateRes {
+	return model.AdminExchangeRateRes{
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func exchangeRatesToTransport(rates []domain.ExchangeRate) []model.AdminExchangeRateRes {
+	res := make([]model.AdminExchangeRateRes, 0, len(rates))
+	for i := range rates {
+		res = append(res, exchangeRateToTransport(&rates[i]))
+	}
+	return res
+}
+
+func balanceToTransport(balance *domain.Balance) model.AdminBalanceRes {
+	return model.AdminBalanceRes{
+		UserUID:      balance.UserUID,
+		Amount:       balance.Amount,
+		RealAmount:   balance.Real(),
+		BonusAmount:  balance.Bonus,
+		Wagering:     balance.Wagering,
+		Currency:     balance.Currency,
+		Denomination: balance.Denomination,
+	}
+}
+
+func sessionToTransport(session *domain.Session) model.AdminSessionRes {
+	res := model.AdminSessionRes{
+		GameSessionUID: session.UID,
+		UserUID:        session.UserUID,
+		GameUID:        session.GameUID,
+		ProviderUID:    session.ProviderUID,
+		Currency:       session.Currency,
+		WalletCurrency: session.WalletCurrency,
+		MaxWin:         session.MaxWin,
+		Status:         string(session.Status),
+		CreatedAt:      session.CreatedAt,
+		LastActivityAt: session.LastActivityAt,
+		ExpiresAt:      session.ExpiresAt,
+		TotalBet:       session.TotalBet,
+		TotalWin:       session.TotalWin,
+	}
+	if !session.RealityCheckedAt.IsZero() {
+		res.RealityCheckedAt = &session.RealityCheckedAt
+	}
+	return res
+}
+
+func campaignToTransport(campaign *domain.Campaign) model.AdminCampaignRes {
+	return model.AdminCampaignRes{
+		CampaignUID:        campaign.UID,
+		UserUID:            campaign.UserUID,
+		GameUID:            campaign.GameUID,
+		Currency:           campaign.Currency,
+		Rounds:             campaign.Rounds,
+		RoundsLeft:         campaign.RoundsLeft,
+		BetValue:           campaign.BetValue,
+		Win:                string(campaign.Win),
+		WageringMultiplier: campaign.WageringMultiplier,
+		Status:             string(campaign.Status),
+		Operator:           campaign.Operator,
+		CreatedAt:          campaign.CreatedAt,
+		ExpiresAt:          campaign.ExpiresAt,
+	}
+}
+
+func transactionToTransport(txn *domain.Transaction) model.AdminTransactionRes {
+	return model.AdminTransactionRes{
+		TransactionUID:         txn.UID,
+		ProviderTransactionUID: txn.ProviderTransactionUID,
+		UserUID:                txn.UserUID,
+		GameSessionUID:         txn.SessionUID,
+		BetUID:                 txn.RoundUID,
+		Type:                   string(txn.Type),
+		Status:                 string(txn.Status),
+		Amount:                 txn.Amount,
+		CappedAmount:           txn.CappedAmount,
+		OriginalAmount:         txn.OriginalAmount,
+		OriginalCurrency:       txn.OriginalCurrency,
+		ExchangeRate:           txn.ExchangeRate,
+		Currency:               txn.Currency,
+		Denomination:           txn.Denomination,
+		BalanceBefore:          txn.BalanceBefore,
+		Balance:                txn.Balance,
+		BonusAmount:            txn.BonusAmount,
+		BonusBalance:           txn.BonusBalance,
+		Wagering:               txn.Wagering,
+		ParentTransactionUID:   txn.ParentTransactionUID,
+		RollbackTransactionUID: txn.RollbackTransactionUID,
+		JackpotKey:             txn.JackpotKey,
+		CampaignUID:            txn.CampaignUID,
+		Operator:               txn.Operator,
+		Reason:                 txn.Reason,
+		CreatedAt:              txn.CreatedAt,
+	}
+}
@@ -3,37 +3,59 @@ package game_processor_handler
 import (
 	"bytes"
 	"context"
-	"crypto/md5"
-	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"io"
 	"log/slog"
-	"open-api-games/internal/config"
 	"open-api-games/internal/domain"
 	"open-api-games/internal/transport/rest/model"
 )
 
 const (
 	errorSource = "[transport.rest.game_processor_handler]"
+
+	// ProviderParam is the path segment identifying the provider
+	ProviderParam = "provider"
+	// providerHeader identifies the provider when the path has no provider segment
+	providerHeader = "Provider-Id"
+	// providerKey is the echo context key of the authenticated provider
+	providerKey = "provider"
+
+	// headers of the request sign
+	signHeader          = "Sign"
+	signTimestampHeader = "Sign-Timestamp"
+	signNonceHeader     = "Sign-Nonce"
 )
 
+//go:generate mockery --dir . --name GameProcessorService --output ./mocks --case=underscore
 type GameProcessorService interface {
 	Balance(ctx context.Context, req *domain.ProcessBalanceReq) (*domain.ProcessBalanceRes, error)
 	Debit(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error)
 	Credit(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error)
 	Rollback(ctx context.Context, req *domain.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackRes, error)
 	MetaData(ctx context.Context, req *domain.ProcessMetaDataReq) (*domain.ProcessMetaDataRes, error)
+	Batch(ctx context.Context, req *domain.ProcessBatchReq) (*domain.ProcessBatchRes, error)
+	DebitCredit(ctx context.Context, req *domain.ProcessDebitCreditReq) (*domain.ProcessDebitCreditRes, error)
+	Currency(ctx context.Context, code string) (*domain.Currency, error)
+}
+
+//go:generate mockery --dir . --name ProviderService --output ./mocks --case=underscore
+type ProviderService interface {
+	Authenticate(ctx context.Context, req *domain.ProviderAuthReq) (*domain.Provider, error)
+	SignResponse(ctx context.Context, provider *domain.Provider, payload *domain.SignPayload) (string, error)
 }
 
 type Handler struct {
 	gameProcessorService GameProcessorService
+	providerService      ProviderService
 	logger               *slog.Logger
 }
 
-func New(gameProcessorService GameProcessorService, logger *slog.Logger) *Handler {
+func New(gameProcessorService GameProcessorService, providerService ProviderService, logger *slog.Logger) *Handler {
 	return &Handler{
 		gameProcessorService: gameProcessorService,
+		providerService:      providerService,
 		logger:               logger,
 	}
 }
@@ -48,12 +70,14 @@ func (h *Handler) Process(c echo.Context) error {
 	err = json.Unmarshal(b, apiCommand)
 	if err != nil {
 		h.logger.Error("error parsing request api command", "error", err)
-		return c.JSON(400, makeError[*model.ProcessBalanceRes](apiCommand.Api, domain.AsError(err).Code))
+		return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
 	}
 	if !apiCommand.Api.IsValid() {
 		h.logger.Error("invalid request api command", "api", apiCommand.Api)
-		return c.JSON(400, makeError[*model.ProcessBalanceRes](apiCommand.Api, domain.AsError(err).Code))
+		return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidApiCommand))
 	}
+	format := h.amountFormat(c)
+	providerUID := h.providerUID(c)
 
 	switch apiCommand.Api {
 	case model.ProcessApiCommandBalance:
@@ -62,25 +86,36 @@ func (h *Handler) Process(c echo.Context) error {
 		err = json.Unmarshal(b, req)
 		if err != nil {
 			h.logger.Error("error parsing request", "error", err)
-			return c.JSON(400, makeError[*model.ProcessBalanceRes](apiCommand.Api, domain.AsError(err).Code))
+			return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+		}
+		if violations := req.Validate(); len(violations) > 0 {
+			return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...))
+		}
+		if !h.currencyAllowed(c, req.Data.Currency) {
+			return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrProviderCurrency))
 		}
 
-		resp, err := h.gameProcessorService.Balance(ctx, h.balanceFromTransport(req.Data))
+		resp, err := h.gameProcessorService.Balance(ctx, h.balanceFromTransport(providerUID, req.Data))
 		if err != nil {
 			h.logger.Error("error processing request", "error", err)
-			return c.JSON(500, makeError[*model.ProcessBalanceRes](apiCommand.Api, domain.AsError(err).Code))
+			return respondError[*model.ProcessBalanceRes](h, c, apiCommand.Api, err)
 		}
 
-		return c.JSON(200, model.ProcessRes[*model.ProcessBalanceRes]{
+		return h.respond(c, 200, model.ProcessRes[*model.ProcessBalanceRes]{
 			Api: model.ProcessApiCommandBalance,
 			Data: &model.ProcessBalanceRes{
 				UserUID:      resp.UserUID,
 				UserNick:     resp.UserNick,
-				Amount:       resp.Amount,
+				Amount:       model.AmountOf(format, resp.Amount, resp.Denomination),
+				RealAmount:   model.AmountOf(format, resp.RealAmount, resp.Denomination),
+				BonusAmount:  model.AmountOf(format, resp.BonusAmount, resp.Denomination),
+				Wagering:     model.AmountOf(format, resp.Wagering, resp.Denomination),
 				Currency:     resp.Currency,
 				Denomination: resp.Denomination,
-				MaxWin:       resp.MaxWin,
+				MaxWin:       model.AmountOf(format, resp.MaxWin, resp.Denomination),
 				JpKey:        resp.JpKey,
+				Jackpots:     h.jackpotsToTransport(format, resp.Jackpots, resp.Denomination),
+				RealityCheck: h.realityCheckToTransport(format, resp.RealityCheck),
 			},
 			IsSuccess: true,
 			Error:     "",
@@ -92,34 +127,82 @@ func (h *Handler) Process(c echo.Context) error {
 
 		err = json.Unmarshal(b, req)
 		if err != nil {
-			h.logger.Error("error parsing request", err)
-			return c.JSON(400, makeError[*model.ProcessDebitCreditRollbackRes](apiCommand.Api, domain.AsError(err).Code))
+			h.logger.Error("error parsing request", "error", err)
+			return respondError[*model.ProcessDebitCreditRollbackRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+		}
+		if violations := req.Validate(); len(violations) > 0 {
+			return respondError[*model.ProcessDebitCreditRollbackRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...))
+		}
+		if !h.currencyAllowed(c, req.Data.Currency) {
+			return respondError[*model.ProcessDebitCreditRollbackRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrProviderCurrency))
+		}
+
+		data, err := h.debitCreditRollbackFromTransport(ctx, providerUID, format, req.Data)
+		if err != nil {
+			return respondError[*model.ProcessDebitCreditRollbackRes](h, c, apiCommand.Api, err)
 		}
 
 		var resp *domain.ProcessDebitCreditRollbackRes
 
 		switch apiCommand.Api {
 		case model.ProcessApiCommandDebit:
-			resp, err = h.gameProcessorService.Debit(ctx, h.debitCreditRollbackFromTransport(req.Data))
+			resp, err = h.gameProcessorService.Debit(ctx, data)
 		case model.ProcessApiCommandCredit:
-			resp, err = h.gameProcessorService.Credit(ctx, h.debitCreditRollbackFromTransport(req.Data))
+			resp, err = h.gameProcessorService.Credit(ctx, data)
 		case model.ProcessApiCommandRollback:
-			resp, err = h.gameProcessorService.Rollback(ctx, h.debitCreditRollbackFromTransport(req.Data))
+			resp, err = h.gameProcessorService.Rollback(ctx, data)
 		}
 		if err != nil {
-			h.logger.Error("error processing request", err)
-			return c.JSON(500, makeError[*model.ProcessDebitCreditRollbackRes](apiCommand.Api, domain.AsError(err).Code))
+			h.logger.Error("error processing request", "error", err)
+			return respondError[*model.ProcessDebitCreditRollbackRes](h, c, apiCommand.Api, err)
 		}
 
-		return c.JSON(200, model.ProcessRes[*model.ProcessDebitCreditRollbackRes]{
-			Api: model.ProcessApiCommandBalance,
-			Data: &model.ProcessDebitCreditRollbackRes{
-				TransactionUID: resp.TransactionUID,
-				UserNick:       resp.UserNick,
-				Amount:         resp.Amount,
-				Currency:       resp.Currency,
-				Denomination:   resp.Denomination,
-				MaxWin:         resp.MaxWin,
+		return h.respond(c, 200, model.ProcessRes[*model.ProcessDebitCreditRollbackRes]{
+			Api:       model.ProcessApiCommandBalance,
+			Data:      h.debitCreditRollbackToTransport(format, resp),
+			IsSuccess: true,
+			Error:     "",
+			ErrorMsg:  domain.ErrNone,
+		})
+
+	case model.ProcessApiCommandDebitCredit:
+		req := &model.ProcessReq[*model.ProcessDebitCreditReq]{}
+
+		err = json.Unmarshal(b, req)
+		if err != nil {
+			h.logger.Error("error parsing request", "error", err)
+			return respondError[*model.ProcessDebitCreditRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+		}
+		if violations := req.Validate(); len(violations) > 0 {
+			return respondError[*model.ProcessDebitCreditRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...))
+		}
+		if !h.currencyAllowed(c, req.Data.Currency) {
+			return respondError[*model.ProcessDebitCreditRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrProviderCurrency))
+		}
+
+		data, err := h.debitCreditFromTransport(ctx, providerUID, format, req.Data)
+		if err != nil {
+			return respondError[*model.ProcessDebitCreditRes](h, c, apiCommand.Api, err)
+		}
+
+		resp, err := h.gameProcessorService.DebitCredit(ctx, data)
+		if err != nil {
+			h.logger.Error("error processing request", "error", err)
+			return respondError[*model.ProcessDebitCreditRes](h, c, apiCommand.Api, err)
+		}
+
+		return h.respond(c, 200, model.ProcessRes[*model.ProcessDebitCreditRes]{
+			Api: model.ProcessApiCommandDebitCredit,
+			Data: &model.ProcessDebitCreditRes{
+				DebitTransactionUID:  resp.DebitTransactionUID,
+				CreditTransactionUID: resp.CreditTransactionUID,
+				UserNick:             resp.UserNick,
+				BetAmount:            model.AmountOf(format, resp.BetAmount, resp.Denomination),
+				WinAmount:            model.AmountOf(format, resp.WinAmount, resp.Denomination),
+				Balance:              model.AmountOf(format, resp.Balance, resp.Denomination),
+				Currency:             resp.Currency,
+				Denomination:         resp.Denomination,
+				MaxWin:               model.AmountOf(format, resp.MaxWin, resp.Denomination),
 			},
 			IsSuccess: true,
 			Error:     "",
@@ -131,40 +214,97 @@ func (h *Handler) Process(c echo.Context) error {
 
 		err = json.Unmarshal(b, req)
 		if err != nil {
-			h.logger.Error("error parsing request", err)
-			return c.JSON(400, makeError[*model.ProcessMetaDataRes](apiCommand.Api, domain.AsError(err).Code))
+			h.logger.Error("error parsing request", "error", err)
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+		}
+		if violations := req.Validate(); len(violations) > 0 {
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...))
+		}
+		if !h.currencyAllowed(c, req.Data.Currency) {
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrProviderCurrency))
 		}
 
-		resp, err := h.gameProcessorService.MetaData(ctx, h.metaDataFromTransport(req.Data))
+		resp, err := h.gameProcessorService.MetaData(ctx, h.metaDataFromTransport(providerUID, req.Data))
 		if err != nil {
-			h.logger.Error("error processing request", err)
-			return c.JSON(500, makeError[*model.ProcessMetaDataRes](apiCommand.Api, domain.AsError(err).Code))
+			h.logger.Error("error processing request", "error", err)
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, err)
 		}
 
-		return c.JSON(200, model.ProcessRes[*model.ProcessMetaDataRes]{
+		round, err := h.roundToTransport(ctx, format, resp.Round)
+		if err != nil {
+			h.logger.Error("error processing request", "error", err)
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, err)
+		}
+
+		return h.respond(c, 200, model.ProcessRes[*model.ProcessMetaDataRes]{
 			Api: model.ProcessApiCommandMetaData,
 			Data: &model.ProcessMetaDataRes{
-				Api:  req.Data.Api,
-				Data: resp.Data,
+				Api:          req.Data.Api,
+				Data:         round,
+				RealityCheck: h.realityCheckToTransport(format, resp.RealityCheck),
 			},
 			IsSuccess: true,
 			Error:     "",
 			ErrorMsg:  domain.ErrNone,
 		})
 
+	case model.ProcessApiCommandBatch:
+		req := &model.ProcessReq[*model.ProcessBatchReq]{}
+
+		err = json.Unmarshal(b, req)
+		if err != nil {
+			h.logger.Error("error parsing request", "error", err)
+			return respondError[*model.ProcessBatchRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).Add(err))
+		}
+		if violations := req.Validate(); len(violations) > 0 {
+			return respondError[*model.ProcessBatchRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...))
+		}
+		for _, item := range req.Data.Items {
+			if !h.currencyAllowed(c, item.Data.Currency) {
+				return respondError[*model.ProcessBatchRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrProviderCurrency))
+			}
+		}
+
+		data, err := h.batchFromTransport(ctx, providerUID, format, req.Data)
+		if err != nil {
+			return respondError[*model.ProcessBatchRes](h, c, apiCommand.Api, err)
+		}
+
+		resp, err := h.gameProcessorService.Batch(ctx, data)
+		if err != nil && resp == nil {
+			h.logger.Error("error processing request", "error", err)
+			return respondError[*model.ProcessBatchRes](h, c, apiCommand.Api, err)
+		}
+		if err != nil {
+			// failed atomic batch reports the failed item along with the error of the whole batch
+			h.logger.Error("error processing request", "error", err)
+			code := domain.AsError(err).Code
+			res := makeError[*model.ProcessBatchRes](apiCommand.Api, code)
+			res.Data = h.batchToTransport(format, resp)
+			return h.respond(c, domain.ErrorInfoOf(code).HTTPStatus, res)
+		}
+
+		return h.respond(c, 200, model.ProcessRes[*model.ProcessBatchRes]{
+			Api:       model.ProcessApiCommandBatch,
+			Data:      h.batchToTransport(format, resp),
+			IsSuccess: true,
+			Error:     "",
+			ErrorMsg:  domain.ErrNone,
+		})
+
 	default:
 		h.logger.Error("invalid request api command", "api", apiCommand.Api)
-		return c.JSON(400, makeError[*model.ProcessMetaDataRes](apiCommand.Api, domain.ErrInvalidApiCommand))
+		return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, domain.NewError(errorSource).SetCode(domain.ErrInvalidApiCommand))
 	}
 }
 
+// CheckSign authenticates the provider identified by the path segment or the header and verifies the request sign,
+// timestamp and nonce of the sign are passed in headers as well
 func (h *Handler) CheckSign(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		cfg := config.Get(h.logger)
-
 		bytesToHash, err := io.ReadAll(c.Request().Body)
 		if err != nil {
-			return c.JSON(400, makeError[*model.ProcessMetaDataRes](model.ProcessApiCommandMetaData, domain.ErrReadBody))
+			return c.JSON(domain.ErrorInfoOf(domain.ErrReadBody).HTTPStatus, makeError[*model.ProcessMetaDataRes](model.ProcessApiCommandMetaData, domain.ErrReadBody))
 		}
 
 		c.Request().Body = io.NopCloser(bytes.NewBuffer(bytesToHash))
@@ -172,69 +312,295 @@ func (h *Handler) CheckSign(next echo.HandlerFunc) echo.HandlerFunc {
 		apiCommand := &model.ProcessCommand{}
 		err = json.Unmarshal(bytesToHash, apiCommand)
 		if err != nil {
-			return c.JSON(400, makeError[*model.ProcessMetaDataRes](model.ProcessApiCommandMetaData, domain.ErrReadBody))
+			return c.JSON(domain.ErrorInfoOf(domain.ErrReadBody).HTTPStatus, makeError[*model.ProcessMetaDataRes](model.ProcessApiCommandMetaData, domain.ErrReadBody))
 		}
 
-		headerSign := c.Request().Header.Get("Sign")
-		if len(headerSign) == 0 {
-			return c.JSON(400, makeError[*model.ProcessMetaDataRes](apiCommand.Api, domain.ErrSignEmpty))
+		providerUID := c.Param(ProviderParam)
+		if providerUID == "" {
+			providerUID = c.Request().Header.Get(providerHeader)
 		}
 
-		stringToHash := string(bytesToHash) + cfg.ApiKey
-		hash := md5.Sum([]byte(stringToHash))
-
-		if hex.EncodeToString(hash[:]) != headerSign {
-			return c.JSON(400, makeError[*model.ProcessMetaDataRes](apiCommand.Api, domain.ErrSignInvalid))
+		provider, err := h.providerService.Authenticate(c.Request().Context(), &domain.ProviderAuthReq{
+			ProviderUID: providerUID,
+			IP:          c.RealIP(),
+			Payload: domain.SignPayload{
+				Method:    c.Request().Method,
+				Path:      c.Request().URL.RequestURI(),
+				Timestamp: c.Request().Header.Get(signTimestampHeader),
+				Nonce:     c.Request().Header.Get(signNonceHeader),
+				Body:      bytesToHash,
+			},
+			Sign: c.Request().Header.Get(signHeader),
+		})
+		if err != nil {
+			h.logger.Warn("provider authentication failed", "provider", providerUID, "ip", c.RealIP(), "error", err)
+			// the provider isn't set, so rejected authentication is answered unsigned and can't be used as a signing oracle
+			return respondError[*model.ProcessMetaDataRes](h, c, apiCommand.Api, err)
 		}
 
+		c.Set(providerKey, provider)
 		return next(c)
 	}
 }
 
-func makeError[T model.ProcessApiResData](api model.ProcessApiCommand, error string) model.ProcessRes[T] {
+// respond writes json response signed with the scheme and the current secret of the authenticated provider,
+// the sign covers method, path and nonce of the request, so the response can't be passed off as the response to another request,
+// responses rejecting the authentication aren't signed
+func (h *Handler) respond(c echo.Context, status int, res any) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	provider, ok := c.Get(providerKey).(*domain.Provider)
+	if ok {
+		payload := &domain.SignPayload{
+			Method: c.Request().Method,
+			Path:   c.Request().URL.RequestURI(),
+			Nonce:  c.Request().Header.Get(signNonceHeader),
+			Body:   b,
+		}
+		sign, err := h.providerService.SignResponse(c.Request().Context(), provider, payload)
+		if err != nil {
+			h.logger.Error("error signing response", "provider", provider.UID, "error", err)
+		} else {
+			c.Response().Header().Set(signHeader, sign)
+			c.Response().Header().Set(signTimestampHeader, payload.Timestamp)
+			c.Response().Header().Set(signNonceHeader, payload.Nonce)
+		}
+	}
+
+	return c.JSONBlob(status, b)
+}
+
+// currencyAllowed checks the currency against the currencies of the authenticated provider
+func (h *Handler) currencyAllowed(c echo.Context, currency string) bool {
+	provider, ok := c.Get(providerKey).(*domain.Provider)
+	if !ok {
+		return false
+	}
+	// request without currency, e.g. rollback, refers the currency of the original transaction
+	if currency != "" && !provider.IsCurrencyAllowed(currency) {
+		h.logger.Warn("currency is not allowed for provider", "provider", provider.UID, "currency", currency)
+		return false
+	}
+	return true
+}
+
+// providerUID returns the uid of the authenticated provider, the service scopes sessions and transactions by it
+func (h *Handler) providerUID(c echo.Context) string {
+	provider, ok := c.Get(providerKey).(*domain.Provider)
+	if !ok {
+		return ""
+	}
+	return provider.UID
+}
+
+// amountFormat returns the amount format of the authenticated provider
+func (h *Handler) amountFormat(c echo.Context) domain.AmountFormat {
+	provider, ok := c.Get(providerKey).(*domain.Provider)
+	if !ok {
+		return domain.AmountFormatMinor
+	}
+	return provider.Format()
+}
+
+// denomination returns the denomination the amounts of the request are read in, decimal amounts of major units
+// are read in minor units of the currency, request without currency keeps its own denomination
+func (h *Handler) denomination(ctx context.Context, format domain.AmountFormat, currency string, denomination int) (int, error) {
+	if format != domain.AmountFormatDecimal || currency == "" {
+		return denomination, nil
+	}
+	cur, err := h.gameProcessorService.Currency(ctx, currency)
+	if err != nil {
+		return 0, err
+	}
+	return cur.Denomination, nil
+}
+
+// makeError describes the error code by the error catalogue, so the provider can tell final rejection from retryable failure
+func makeError[T model.ProcessApiResData](api model.ProcessApiCommand, code string, violations ...domain.Violation) model.ProcessRes[T] {
+	info := domain.ErrorInfoOf(code)
 	return model.ProcessRes[T]{
-		Api:       api,
-		Data:      nil,
-		IsSuccess: false,
-		Error:     error,
-		ErrorMsg:  error,
+		Api:        api,
+		Data:       nil,
+		IsSuccess:  false,
+		Error:      code,
+		ErrorCode:  info.ProviderCode,
+		ErrorMsg:   info.Message,
+		Retryable:  info.Retryable,
+		Violations: violations,
 	}
 }
 
-func (h *Handler) balanceFromTransport(req *model.ProcessBalanceReq) *domain.ProcessBalanceReq {
+// respondError writes signed error response with http status from the error catalogue
+func respondError[T model.ProcessApiResData](h *Handler, c echo.Context, api model.ProcessApiCommand, err error) error {
+	dErr := domain.AsError(err)
+	return h.respond(c, domain.ErrorInfoOf(dErr.Code).HTTPStatus, makeError[T](api, dErr.Code, dErr.Violations...))
+}
+
+func (h *Handler) balanceFromTransport(providerUID string, req *model.ProcessBalanceReq) *domain.ProcessBalanceReq {
 	if req == nil {
 		return nil
 	}
 	return &domain.ProcessBalanceReq{
+		ProviderUID:    providerUID,
 		GameSessionUID: req.GameSessionUID,
 		Currency:       req.Currency,
 	}
 }
 
-func (h *Handler) debitCreditRollbackFromTransport(req *model.ProcessDebitCreditRollbackReq) *domain.ProcessDebitCreditRollbackReq {
+// debitCreditRollbackFromTransport reads the amounts by the amount format in minor units of the request denomination,
+// the amount losing precision is reported as violation
+func (h *Handler) debitCreditRollbackFromTransport(ctx context.Context, providerUID string, format domain.AmountFormat, req *model.ProcessDebitCreditRollbackReq) (*domain.ProcessDebitCreditRollbackReq, error) {
 	if req == nil {
-		return nil
+		return nil, nil
+	}
+	denomination, err := h.denomination(ctx, format, req.Currency, req.Denomination)
+	if err != nil {
+		return nil, err
+	}
+	if violations := domain.Validate(
+		domain.Field("amount", req.Amount, model.AmountFits(format, denomination)),
+		domain.Field("maxWin", req.MaxWin, model.AmountFits(format, denomination)),
+	); len(violations) > 0 {
+		return nil, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...)
 	}
+
+	amount, _ := req.Amount.Minor(format, denomination)
+	maxWin, _ := req.MaxWin.Minor(format, denomination)
 	return &domain.ProcessDebitCreditRollbackReq{
+		ProviderUID:    providerUID,
 		TransactionUID: req.TransactionUID,
 		GameSessionUID: req.GameSessionUID,
 		UserUID:        req.UserUID,
 		UserNick:       req.UserNick,
-		Amount:         req.Amount,
+		Amount:         amount,
 		Currency:       req.Currency,
-		Denomination:   req.Denomination,
-		MaxWin:         req.MaxWin,
+		Denomination:   denomination,
+		MaxWin:         maxWin,
 		JpKey:          req.JpKey,
 		SpinMeta:       req.SpinMeta,
 		BetMeta:        req.BetMeta,
+		BetUID:         req.BetUID,
+		CampaignUID:    req.CampaignUID,
+	}, nil
+}
+
+func (h *Handler) debitCreditRollbackToTransport(format domain.AmountFormat, res *domain.ProcessDebitCreditRollbackRes) *model.ProcessDebitCreditRollbackRes {
+	return &model.ProcessDebitCreditRollbackRes{
+		TransactionUID: res.TransactionUID,
+		UserNick:       res.UserNick,
+		Amount:         model.AmountOf(format, res.Amount, res.Denomination),
+		Balance:        model.AmountOf(format, res.Balance, res.Denomination),
+		Currency:       res.Currency,
+		Denomination:   res.Denomination,
+		MaxWin:         model.AmountOf(format, res.MaxWin, res.Denomination),
+		RealityCheck:   h.realityCheckToTransport(format, res.RealityCheck),
 	}
 }
 
-func (h *Handler) metaDataFromTransport(req *model.ProcessMetaDataReq) *domain.ProcessMetaDataReq {
+func (h *Handler) debitCreditFromTransport(ctx context.Context, providerUID string, format domain.AmountFormat, req *model.ProcessDebitCreditReq) (*domain.ProcessDebitCreditReq, error) {
+	if req == nil {
+		return nil, nil
+	}
+	denomination, err := h.denomination(ctx, format, req.Currency, req.Denomination)
+	if err != nil {
+		return nil, err
+	}
+	if violations := domain.Validate(
+		domain.Field("betAmount", req.BetAmount, model.AmountFits(format, denomination)),
+		domain.Field("winAmount", req.WinAmount, model.AmountFits(format, denomination)),
+		domain.Field("maxWin", req.MaxWin, model.AmountFits(format, denomination)),
+	); len(violations) > 0 {
+		return nil, domain.NewError(errorSource).SetCode(domain.ErrInvalidRequest).SetViolations(violations...)
+	}
+
+	betAmount, _ := req.BetAmount.Minor(format, denomination)
+	winAmount, _ := req.WinAmount.Minor(format, denomination)
+	maxWin, _ := req.MaxWin.Minor(format, denomination)
+	return &domain.ProcessDebitCreditReq{
+		ProviderUID:    providerUID,
+		TransactionUID: req.TransactionUID,
+		GameSessionUID: req.GameSessionUID,
+		UserUID:        req.UserUID,
+		UserNick:       req.UserNick,
+		BetAmount:      betAmount,
+		WinAmount:      winAmount,
+		Currency:       req.Currency,
+		Denomination:   denomination,
+		MaxWin:         maxWin,
+		JpKey:          req.JpKey,
+		SpinMeta:       req.SpinMeta,
+		BetMeta:        req.BetMeta,
+		BetUID:         req.BetUID,
+	}, nil
+}
+
+// batchFromTransport reads every item as the separate request, item violations are prefixed by the item path
+func (h *Handler) batchFromTransport(ctx context.Context, providerUID string, format domain.AmountFormat, req *model.ProcessBatchReq) (*domain.ProcessBatchReq, error) {
+	if req == nil {
+		return nil, nil
+	}
+	items := make([]domain.ProcessBatchItemReq, 0, len(req.Items))
+	for i, item := range req.Items {
+		data, err := h.debitCreditRollbackFromTransport(ctx, providerUID, format, item.Data)
+		if err != nil {
+			dErr := domain.AsError(err)
+			for j := range dErr.Violations {
+				dErr.Violations[j].Field = fmt.Sprintf("items[%d].data.%s", i, dErr.Violations[j].Field)
+			}
+			return nil, dErr
+		}
+		items = append(items, domain.ProcessBatchItemReq{
+			Type: domain.TransactionType(item.Api),
+			Data: *data,
+		})
+	}
+	return &domain.ProcessBatchReq{
+		Mode:  domain.ProcessBatchMode(req.Mode),
+		Items: items,
+	}, nil
+}
+
+// batchToTransport describes every item result as the response to the separate request
+func (h *Handler) batchToTransport(format domain.AmountFormat, res *domain.ProcessBatchRes) *model.ProcessBatchRes {
+	items := make([]model.ProcessBatchItemRes, 0, len(res.Items))
+	for _, item := range res.Items {
+		api := model.ProcessApiCommand(item.Type)
+		if item.Error != nil {
+			dErr := domain.AsError(item.Error)
+			info := domain.ErrorInfoOf(dErr.Code)
+			items = append(items, model.ProcessBatchItemRes{
+				Api:        api,
+				IsSuccess:  false,
+				Error:      dErr.Code,
+				ErrorCode:  info.ProviderCode,
+				ErrorMsg:   info.Message,
+				Retryable:  info.Retryable,
+				Violations: dErr.Violations,
+			})
+			continue
+		}
+		items = append(items, model.ProcessBatchItemRes{
+			Api:       api,
+			Data:      h.debitCreditRollbackToTransport(format, item.Result),
+			IsSuccess: true,
+			ErrorMsg:  domain.ErrNone,
+		})
+	}
+	return &model.ProcessBatchRes{
+		Mode:  model.ProcessBatchMode(res.Mode),
+		Items: items,
+	}
+}
+
+func (h *Handler) metaDataFromTransport(providerUID string, req *model.ProcessMetaDataReq) *domain.ProcessMetaDataReq {
 	if req == nil {
 		return nil
 	}
 	return &domain.ProcessMetaDataReq{
+		ProviderUID:    providerUID,
 		UserUID:        req.UserUID,
 		GameSessionUID: req.GameSessionUID,
 		Currency:       req.Currency,
@@ -244,3 +610,57 @@ func (h *Handler) metaDataFromTransport(req *model.ProcessMetaDataReq) *domain.P
 		},
 	}
 }
+
+// roundToTransport describes the round amounts in minor units of the round currency
+func (h *Handler) roundToTransport(ctx context.Context, format domain.AmountFormat, round *domain.Round) (*model.ProcessRoundRes, error) {
+	if round == nil {
+		return nil, nil
+	}
+	denomination, err := h.denomination(ctx, format, round.Currency, 0)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]model.ProcessRoundTransactionRes, 0, len(round.Transactions))
+	for _, txn := range round.Transactions {
+		transactions = append(transactions, model.ProcessRoundTransactionRes{
+			TransactionUID:       txn.UID,
+			Type:                 string(txn.Type),
+			Amount:               model.AmountOf(format, txn.Amount, denomination),
+			ParentTransactionUID: txn.ParentTransactionUID,
+		})
+	}
+	return &model.ProcessRoundRes{
+		BetUID:         round.UID,
+		GameSessionUID: round.SessionUID,
+		UserUID:        round.UserUID,
+		Currency:       round.Currency,
+		Status:         string(round.Status),
+		TotalBet:       model.AmountOf(format, round.TotalBet, denomination),
+		TotalWin:       model.AmountOf(format, round.TotalWin, denomination),
+		Transactions:   transactions,
+	}, nil
+}
+
+// realityCheckToTransport describes the reality check in the game denomination, nil when it isn't due
+func (h *Handler) realityCheckToTransport(format domain.AmountFormat, check *domain.RealityCheck) *model.ProcessRealityCheckRes {
+	if check == nil {
+		return nil
+	}
+	return &model.ProcessRealityCheckRes{
+		ElapsedSeconds: int(check.Elapsed.Seconds()),
+		NetResult:      model.AmountOf(format, check.NetResult, check.Denomination),
+		Currency:       check.Currency,
+	}
+}
+
+func (h *Handler) jackpotsToTransport(format domain.AmountFormat, jackpots []domain.Jackpot, denomination int) []model.ProcessJackpotRes {
+	res := make([]model.ProcessJackpotRes, 0, len(jackpots))
+	for _, jackpot := range jackpots {
+		res = append(res, model.ProcessJackpotRes{
+			JpKey:  jackpot.Key,
+			Name:   jackpot.Name,
+			Amount: model.AmountOf(format, jackpot.Amount, denomination),
+		})
+	}
+	return res
+}
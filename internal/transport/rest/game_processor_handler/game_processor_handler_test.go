@@ -0,0 +1,291 @@
+package game_processor_handler
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"open-api-games/internal/domain"
+	"open-api-games/internal/transport/rest/game_processor_handler/mocks"
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	processPath = "/open-api-games/v1/games-processor"
+	balanceBody = `{"api":"balance","data":{"gameSessionId":"s1","currency":"USD"}}`
+	debitBody   = `{"api":"debit","data":{"transactionId":"tx-1","gameSessionId":"s1","amount":100,"currency":"USD"}}`
+)
+
+// serve sends the request of the provider through the sign check and the processing as the router does
+func serve(h *Handler, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.POST(processPath, h.Process, h.CheckSign)
+
+	req := httptest.NewRequest(http.MethodPost, processPath, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(providerHeader, "p1")
+	req.Header.Set(signHeader, "request-sign")
+	req.Header.Set(signTimestampHeader, "1700000000")
+	req.Header.Set(signNonceHeader, "n1")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// errorRes decodes the error fields of the response
+func errorRes(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	res := map[string]any{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
+
+func provider() *domain.Provider {
+	return &domain.Provider{
+		UID:        "p1",
+		Secrets:    []string{"current"},
+		Enabled:    true,
+		Currencies: []string{"USD"},
+		SignScheme: domain.SignSchemeHMACSHA256,
+	}
+}
+
+// signed expects the response to be signed for the provider, the sign covers the request method, path and nonce
+func signed(providerMock *mocks.ProviderService) {
+	providerMock.
+		On("SignResponse", mock.Anything, mock.Anything, mock.MatchedBy(func(payload *domain.SignPayload) bool {
+			return payload.Method == http.MethodPost && payload.Path == processPath && payload.Nonce == "n1"
+		})).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*domain.SignPayload).Timestamp = "1700000001"
+		}).
+		Return("response-sign", nil)
+}
+
+func TestCheckSign(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	t.Run("response of authenticated request is signed", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		providerMock := &mocks.ProviderService{}
+		h := New(serviceMock, providerMock, logger)
+
+		providerMock.
+			On("Authenticate", mock.Anything, mock.MatchedBy(func(req *domain.ProviderAuthReq) bool {
+				return req.ProviderUID == "p1" && req.Sign == "request-sign" && string(req.Payload.Body) == balanceBody
+			})).
+			Return(provider(), nil)
+		signed(providerMock)
+
+		serviceMock.
+			On("Balance", mock.Anything, &domain.ProcessBalanceReq{ProviderUID: "p1", GameSessionUID: "s1", Currency: "USD"}).
+			Return(&domain.ProcessBalanceRes{UserUID: "u1", Amount: 1000, Currency: "USD", Denomination: 2}, nil)
+
+		rec := serve(h, balanceBody)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "response-sign", rec.Header().Get(signHeader))
+		assert.Equal(t, "1700000001", rec.Header().Get(signTimestampHeader))
+		assert.Equal(t, "n1", rec.Header().Get(signNonceHeader))
+		assert.Equal(t, true, errorRes(t, rec)["isSuccess"])
+
+		serviceMock.AssertExpectations(t)
+		providerMock.AssertExpectations(t)
+	})
+
+	t.Run("rejected authentication isn't signed", func(t *testing.T) {
+		tests := []struct {
+			code   string
+			status int
+		}{
+			{code: domain.ErrProviderIP, status: http.StatusForbidden},
+			{code: domain.ErrSignReplay, status: http.StatusConflict},
+			{code: domain.ErrSignTimestamp, status: http.StatusUnauthorized},
+			{code: domain.ErrSignInvalid, status: http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(tt.code, func(t *testing.T) {
+				serviceMock := &mocks.GameProcessorService{}
+				providerMock := &mocks.ProviderService{}
+				h := New(serviceMock, providerMock, logger)
+
+				providerMock.
+					On("Authenticate", mock.Anything, mock.Anything).
+					Return(nil, domain.NewError("test").SetCode(tt.code))
+
+				rec := serve(h, balanceBody)
+
+				assert.Equal(t, tt.status, rec.Code)
+				assert.Empty(t, rec.Header().Get(signHeader))
+				assert.Empty(t, rec.Header().Get(signTimestampHeader))
+				res := errorRes(t, rec)
+				assert.Equal(t, tt.code, res["error"])
+				assert.Equal(t, false, res["isSuccess"])
+
+				serviceMock.AssertNotCalled(t, "Balance", mock.Anything, mock.Anything)
+				providerMock.AssertNotCalled(t, "SignResponse", mock.Anything, mock.Anything, mock.Anything)
+				providerMock.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("rejection of unknown provider isn't signed", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		providerMock := &mocks.ProviderService{}
+		h := New(serviceMock, providerMock, logger)
+
+		providerMock.
+			On("Authenticate", mock.Anything, mock.Anything).
+			Return(nil, domain.NewError("test").SetCode(domain.ErrProviderNotFound))
+
+		rec := serve(h, balanceBody)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get(signHeader))
+		assert.Equal(t, domain.ErrProviderNotFound, errorRes(t, rec)["error"])
+
+		providerMock.AssertNotCalled(t, "SignResponse", mock.Anything, mock.Anything, mock.Anything)
+		providerMock.AssertExpectations(t)
+	})
+
+	t.Run("unreadable request isn't signed", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		providerMock := &mocks.ProviderService{}
+		h := New(serviceMock, providerMock, logger)
+
+		rec := serve(h, `{"api":`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, rec.Header().Get(signHeader))
+		assert.Equal(t, domain.ErrReadBody, errorRes(t, rec)["error"])
+
+		providerMock.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
+	// authenticated returns the handler of the authenticated provider with signed responses
+	authenticated := func(serviceMock *mocks.GameProcessorService) *Handler {
+		providerMock := &mocks.ProviderService{}
+		providerMock.On("Authenticate", mock.Anything, mock.Anything).Return(provider(), nil)
+		signed(providerMock)
+		return New(serviceMock, providerMock, logger)
+	}
+
+	t.Run("error code is answered with http status of the catalogue", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			err       error
+			status    int
+			code      string
+			retryable bool
+		}{
+			{name: "insufficient balance", err: domain.NewError("test").SetCode(domain.ErrDecrement), status: http.StatusPaymentRequired, code: domain.ErrDecrement},
+			{name: "session not found", err: domain.NewError("test").SetCode(domain.ErrSessionNotFound), status: http.StatusNotFound, code: domain.ErrSessionNotFound},
+			{name: "session of another provider", err: domain.NewError("test").SetCode(domain.ErrProviderSession), status: http.StatusForbidden, code: domain.ErrProviderSession},
+			{name: "transaction conflict", err: domain.NewError("test").SetCode(domain.ErrTransactionConflict), status: http.StatusConflict, code: domain.ErrTransactionConflict},
+			{name: "gaming limit", err: domain.NewError("test").SetCode(domain.ErrGamingLimitExceeded), status: http.StatusUnprocessableEntity, code: domain.ErrGamingLimitExceeded},
+			{name: "storage unavailable", err: domain.NewError("test").SetCode(domain.ErrConnect), status: http.StatusServiceUnavailable, code: domain.ErrConnect, retryable: true},
+			{name: "unknown error", err: assert.AnError, status: http.StatusInternalServerError, code: domain.AsError(assert.AnError).Code, retryable: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				serviceMock := &mocks.GameProcessorService{}
+				h := authenticated(serviceMock)
+
+				serviceMock.
+					On("Debit", mock.Anything, mock.MatchedBy(func(req *domain.ProcessDebitCreditRollbackReq) bool {
+						return req.ProviderUID == "p1" && req.TransactionUID == "tx-1" && req.Amount == 100
+					})).
+					Return(nil, tt.err)
+
+				rec := serve(h, debitBody)
+
+				assert.Equal(t, tt.status, rec.Code)
+				assert.Equal(t, "response-sign", rec.Header().Get(signHeader))
+				res := errorRes(t, rec)
+				assert.Equal(t, tt.code, res["error"])
+				assert.Equal(t, float64(domain.ErrorInfoOf(tt.code).ProviderCode), res["errorCode"])
+				assert.Equal(t, tt.retryable, res["retryable"])
+
+				serviceMock.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("invalid request is answered with bad request", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		h := authenticated(serviceMock)
+
+		rec := serve(h, `{"api":"debit","data":{"gameSessionId":"s1","amount":-1,"currency":"USD"}}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "response-sign", rec.Header().Get(signHeader))
+		res := errorRes(t, rec)
+		assert.Equal(t, domain.ErrInvalidRequest, res["error"])
+		assert.Len(t, res["violations"], 2)
+
+		serviceMock.AssertNotCalled(t, "Debit", mock.Anything, mock.Anything)
+	})
+
+	t.Run("currency not allowed for provider", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		h := authenticated(serviceMock)
+
+		rec := serve(h, `{"api":"balance","data":{"gameSessionId":"s1","currency":"EUR"}}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, domain.ErrProviderCurrency, errorRes(t, rec)["error"])
+
+		serviceMock.AssertNotCalled(t, "Balance", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown api command", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		h := authenticated(serviceMock)
+
+		rec := serve(h, `{"api":"transfer","data":{}}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "response-sign", rec.Header().Get(signHeader))
+		assert.Equal(t, domain.ErrInvalidApiCommand, errorRes(t, rec)["error"])
+	})
+
+	t.Run("failed atomic batch reports failed item with status of the batch error", func(t *testing.T) {
+		serviceMock := &mocks.GameProcessorService{}
+		h := authenticated(serviceMock)
+
+		serviceMock.
+			On("Batch", mock.Anything, mock.MatchedBy(func(req *domain.ProcessBatchReq) bool {
+				return len(req.Items) == 1 && req.Items[0].Data.ProviderUID == "p1"
+			})).
+			Return(&domain.ProcessBatchRes{
+				Mode: domain.ProcessBatchModeAtomic,
+				Items: []domain.ProcessBatchItemRes{
+					{Type: domain.TransactionTypeDebit, Error: domain.NewError("test").SetCode(domain.ErrDecrement)},
+				},
+			}, domain.NewError("test").SetCode(domain.ErrDecrement))
+
+		rec := serve(h, `{"api":"batch","data":{"mode":"atomic","items":[`+debitBody+`]}}`)
+
+		assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+		res := errorRes(t, rec)
+		assert.Equal(t, domain.ErrDecrement, res["error"])
+		items := res["data"].(map[string]any)["items"].([]any)
+		assert.Equal(t, domain.ErrDecrement, items[0].(map[string]any)["error"])
+
+		serviceMock.AssertExpectations(t)
+	})
+}
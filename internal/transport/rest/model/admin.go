@@ -0,0 +1,208 @@
+package model
+
+import "time"
+
+type AdminErrorRes struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+type AdminPageRes[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type AdminUserReq struct {
+	UserUID string                       `json:"userId"`
+	Nick    string                       `json:"nick"`
+	MaxWin  map[string]int               `json:"maxWin"`
+	Limits  map[string]AdminGamingLimits `json:"limits"`
+	// SelfExcludedUntil and CoolOffUntil left out of the update keep the current ones, the active ones can only be extended
+	SelfExcludedUntil time.Time `json:"selfExcludedUntil"`
+	CoolOffUntil      time.Time `json:"coolOffUntil"`
+	// SessionLimitMinutes is the longest game session of the user, RealityCheckMinutes is the reality check interval,
+	// zero means none
+	SessionLimitMinutes int `json:"sessionLimitMinutes"`
+	RealityCheckMinutes int `json:"realityCheckMinutes"`
+}
+
+type AdminUserRes struct {
+	UserUID             string                       `json:"userId"`
+	Nick                string                       `json:"nick"`
+	MaxWin              map[string]int               `json:"maxWin,omitempty"`
+	Limits              map[string]AdminGamingLimits `json:"limits,omitempty"`
+	SelfExcludedUntil   *time.Time                   `json:"selfExcludedUntil,omitempty"`
+	CoolOffUntil        *time.Time                   `json:"coolOffUntil,omitempty"`
+	SessionLimitMinutes int                          `json:"sessionLimitMinutes,omitempty"`
+	RealityCheckMinutes int                          `json:"realityCheckMinutes,omitempty"`
+}
+
+// AdminGamingLimits are responsible gaming limits of the user in the currency, zero means no limit
+type AdminGamingLimits struct {
+	DailyLoss    int `json:"dailyLoss,omitempty"`
+	WeeklyLoss   int `json:"weeklyLoss,omitempty"`
+	MonthlyLoss  int `json:"monthlyLoss,omitempty"`
+	DailyWager   int `json:"dailyWager,omitempty"`
+	WeeklyWager  int `json:"weeklyWager,omitempty"`
+	MonthlyWager int `json:"monthlyWager,omitempty"`
+	// deposit limits cap the real money added by positive adjustments
+	DailyDeposit   int `json:"dailyDeposit,omitempty"`
+	WeeklyDeposit  int `json:"weeklyDeposit,omitempty"`
+	MonthlyDeposit int `json:"monthlyDeposit,omitempty"`
+}
+
+type AdminCurrencyReq struct {
+	Code         string `json:"code"`
+	Denomination int    `json:"denomination"`
+	MaxWin       int    `json:"maxWin"`
+}
+
+type AdminCurrencyRes struct {
+	Code         string `json:"code"`
+	Denomination int    `json:"denomination"`
+	MaxWin       int    `json:"maxWin"`
+}
+
+type AdminExchangeRateReq struct {
+	Rate float64 `json:"rate"`
+}
+
+type AdminExchangeRateRes struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type AdminProviderReq struct {
+	ProviderUID string   `json:"providerId"`
+	Name        string   `json:"name"`
+	Secret      string   `json:"secret"`
+	AllowedIPs  []string `json:"allowedIps"`
+	Enabled     bool     `json:"enabled"`
+	Currencies  []string `json:"currencies"`
+	SignScheme  string   `json:"signScheme"`
+	// AmountFormat is minor for integer minor units or decimal for decimal strings of major units
+	AmountFormat string `json:"amountFormat"`
+}
+
+type AdminProviderSecretReq struct {
+	Secret string `json:"secret"`
+}
+
+type AdminProviderRes struct {
+	ProviderUID  string   `json:"providerId"`
+	Name         string   `json:"name"`
+	AllowedIPs   []string `json:"allowedIps"`
+	Enabled      bool     `json:"enabled"`
+	Currencies   []string `json:"currencies"`
+	SignScheme   string   `json:"signScheme"`
+	AmountFormat string   `json:"amountFormat"`
+	SecretsCount int      `json:"secretsCount"`
+	// Secret is the current secret, it's shown only once when it's created
+	Secret string `json:"secret,omitempty"`
+}
+
+type AdminBalanceReq struct {
+	Currency string `json:"currency"`
+}
+
+type AdminBalanceRes struct {
+	UserUID      string `json:"userId"`
+	Amount       int    `json:"amount"`
+	RealAmount   int    `json:"realAmount"`
+	BonusAmount  int    `json:"bonusAmount"`
+	Wagering     int    `json:"wagering"`
+	Currency     string `json:"currency"`
+	Denomination int    `json:"denomination"`
+}
+
+type AdminAdjustmentReq struct {
+	Amount   int    `json:"amount"`
+	Bonus    bool   `json:"bonus"`
+	Wagering int    `json:"wagering"`
+	Reason   string `json:"reason"`
+}
+
+type AdminSessionReq struct {
+	UserUID     string `json:"userId"`
+	GameUID     string `json:"gameId"`
+	ProviderUID string `json:"providerId"`
+	Currency    string `json:"currency"`
+	MaxWin      int    `json:"maxWin"`
+}
+
+type AdminSessionRes struct {
+	GameSessionUID   string     `json:"gameSessionId"`
+	UserUID          string     `json:"userId"`
+	GameUID          string     `json:"gameId"`
+	ProviderUID      string     `json:"providerId"`
+	Currency         string     `json:"currency"`
+	WalletCurrency   string     `json:"walletCurrency,omitempty"`
+	MaxWin           int        `json:"maxWin"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	LastActivityAt   time.Time  `json:"lastActivityAt"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	TotalBet         int        `json:"totalBet"`
+	TotalWin         int        `json:"totalWin"`
+	RealityCheckedAt *time.Time `json:"realityCheckedAt,omitempty"`
+}
+
+type AdminCampaignReq struct {
+	UserUID            string    `json:"userId"`
+	GameUID            string    `json:"gameId"`
+	Currency           string    `json:"currency"`
+	Rounds             int       `json:"rounds"`
+	BetValue           int       `json:"betValue"`
+	Win                string    `json:"win"`
+	WageringMultiplier int       `json:"wageringMultiplier"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+type AdminCampaignRes struct {
+	CampaignUID        string    `json:"campaignId"`
+	UserUID            string    `json:"userId"`
+	GameUID            string    `json:"gameId"`
+	Currency           string    `json:"currency"`
+	Rounds             int       `json:"rounds"`
+	RoundsLeft         int       `json:"roundsLeft"`
+	BetValue           int       `json:"betValue"`
+	Win                string    `json:"win"`
+	WageringMultiplier int       `json:"wageringMultiplier,omitempty"`
+	Status             string    `json:"status"`
+	Operator           string    `json:"operator,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+type AdminTransactionRes struct {
+	TransactionUID         string    `json:"transactionId"`
+	ProviderTransactionUID string    `json:"providerTransactionId,omitempty"`
+	UserUID                string    `json:"userId"`
+	GameSessionUID         string    `json:"gameSessionId,omitempty"`
+	BetUID                 string    `json:"betId,omitempty"`
+	Type                   string    `json:"type"`
+	Status                 string    `json:"status"`
+	Amount                 int       `json:"amount"`
+	CappedAmount           int       `json:"cappedAmount,omitempty"`
+	OriginalAmount         int       `json:"originalAmount,omitempty"`
+	OriginalCurrency       string    `json:"originalCurrency,omitempty"`
+	ExchangeRate           float64   `json:"exchangeRate,omitempty"`
+	Currency               string    `json:"currency"`
+	Denomination           int       `json:"denomination"`
+	BalanceBefore          int       `json:"balanceBefore"`
+	Balance                int       `json:"balance"`
+	BonusAmount            int       `json:"bonusAmount,omitempty"`
+	BonusBalance           int       `json:"bonusBalance,omitempty"`
+	Wagering               int       `json:"wagering,omitempty"`
+	ParentTransactionUID   string    `json:"parentTransactionId,omitempty"`
+	RollbackTransactionUID string    `json:"rollbackTransactionId,omitempty"`
+	JackpotKey             string    `json:"jpKey,omitempty"`
+	CampaignUID            string    `json:"campaignId,omitempty"`
+	Operator               string    `json:"operator,omitempty"`
+	Reason                 string    `json:"reason,omitempty"`
+	CreatedAt              time.Time `json:"createdAt"`
+}
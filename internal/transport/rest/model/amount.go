@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"open-api-games/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// Amount is the money amount of the games processor api, it's decoded from json number or string
+// and is read by the amount format of the provider as minor units or decimal of major units
+type Amount struct {
+	text string
+	// decimal amount is encoded as json string
+	decimal bool
+}
+
+// AmountOf encodes minor units of the denomination by the amount format
+func AmountOf(format domain.AmountFormat, amount, denomination int) Amount {
+	if format == domain.AmountFormatDecimal {
+		return Amount{text: domain.FormatDecimal(amount, denomination), decimal: true}
+	}
+	return Amount{text: strconv.Itoa(amount)}
+}
+
+// Minor reads the amount in minor units of the denomination, decimal amount is read as major units,
+// false is returned for malformed amount and amount losing precision, omitted amount is zero
+func (a Amount) Minor(format domain.AmountFormat, denomination int) (int, bool) {
+	if a.text == "" {
+		return 0, true
+	}
+	if format == domain.AmountFormatDecimal {
+		return domain.ParseDecimal(a.text, denomination)
+	}
+	return domain.ParseDecimal(a.text, 0)
+}
+
+// sign returns -1, 0 or 1 by the sign of the amount, false is returned for malformed amount
+func (a Amount) sign() (int, bool) {
+	if a.text == "" {
+		return 0, true
+	}
+	if !domain.IsDecimal(a.text) {
+		return 0, false
+	}
+	if strings.Trim(a.text, "-0.") == "" {
+		return 0, true
+	}
+	if strings.HasPrefix(a.text, "-") {
+		return -1, true
+	}
+	return 1, true
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Amount{text: s, decimal: true}
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*a = Amount{text: n.String()}
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	if a.decimal {
+		return json.Marshal(a.text)
+	}
+	if a.text == "" {
+		return []byte("0"), nil
+	}
+	return []byte(a.text), nil
+}
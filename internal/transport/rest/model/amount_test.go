@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"open-api-games/internal/domain"
+	"testing"
+)
+
+func TestAmountMinor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		json         string
+		format       domain.AmountFormat
+		denomination int
+		want         int
+		ok           bool
+	}{
+		{name: "minor units", json: `100`, format: domain.AmountFormatMinor, denomination: 2, want: 100, ok: true},
+		{name: "decimal of major units", json: `"1.25"`, format: domain.AmountFormatDecimal, denomination: 2, want: 125, ok: true},
+		{name: "decimal without fraction", json: `"12"`, format: domain.AmountFormatDecimal, denomination: 2, want: 1200, ok: true},
+		{name: "trailing zeros of fraction", json: `"1.2500"`, format: domain.AmountFormatDecimal, denomination: 2, want: 125, ok: true},
+		{name: "too many decimals", json: `"1.255"`, format: domain.AmountFormatDecimal, denomination: 2},
+		{name: "fraction of minor units", json: `12.5`, format: domain.AmountFormatMinor, denomination: 2},
+		{name: "negative minor units", json: `-100`, format: domain.AmountFormatMinor, denomination: 2, want: -100, ok: true},
+		{name: "negative decimal", json: `"-1.5"`, format: domain.AmountFormatDecimal, denomination: 2, want: -150, ok: true},
+		{name: "exponent number", json: `1e3`, format: domain.AmountFormatMinor, denomination: 2},
+		{name: "exponent decimal", json: `"1e3"`, format: domain.AmountFormatDecimal, denomination: 2},
+		{name: "upper case exponent", json: `1E+2`, format: domain.AmountFormatMinor, denomination: 2},
+		{name: "malformed decimal", json: `"1,5"`, format: domain.AmountFormatDecimal, denomination: 2},
+		{name: "largest minor units", json: `9223372036854775807`, format: domain.AmountFormatMinor, denomination: 2, want: math.MaxInt, ok: true},
+		{name: "minor units overflow", json: `9223372036854775808`, format: domain.AmountFormatMinor, denomination: 2},
+		{name: "largest decimal", json: `"92233720368547758.07"`, format: domain.AmountFormatDecimal, denomination: 2, want: math.MaxInt, ok: true},
+		{name: "decimal overflow", json: `"92233720368547758.08"`, format: domain.AmountFormatDecimal, denomination: 2},
+		{name: "whole denomination", json: `"12"`, format: domain.AmountFormatDecimal, denomination: 0, want: 12, ok: true},
+		{name: "fraction of whole denomination", json: `"12.5"`, format: domain.AmountFormatDecimal, denomination: 0},
+		{name: "finest denomination", json: `"9.223372036854775807"`, format: domain.AmountFormatDecimal, denomination: domain.MaxDenomination, want: math.MaxInt, ok: true},
+		{name: "finest denomination overflow", json: `"10"`, format: domain.AmountFormatDecimal, denomination: domain.MaxDenomination},
+		{name: "denomination out of int range", json: `"1"`, format: domain.AmountFormatDecimal, denomination: domain.MaxDenomination + 1},
+		{name: "zero in denomination out of int range", json: `"0"`, format: domain.AmountFormatDecimal, denomination: 64},
+		{name: "minor units ignore denomination", json: `5`, format: domain.AmountFormatMinor, denomination: 64, want: 5, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Amount
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &a))
+
+			amount, ok := a.Minor(tt.format, tt.denomination)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, amount)
+		})
+	}
+
+	t.Run("omitted amount is zero", func(t *testing.T) {
+		var req struct {
+			Amount Amount `json:"amount"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{}`), &req))
+
+		amount, ok := req.Amount.Minor(domain.AmountFormatDecimal, 2)
+
+		assert.True(t, ok)
+		assert.Equal(t, 0, amount)
+	})
+
+	t.Run("not a number", func(t *testing.T) {
+		var a Amount
+
+		assert.Error(t, json.Unmarshal([]byte(`true`), &a))
+	})
+}
+
+func TestAmountOf(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		format       domain.AmountFormat
+		amount       int
+		denomination int
+		want         string
+	}{
+		{format: domain.AmountFormatMinor, amount: 1234, denomination: 2, want: `1234`},
+		{format: domain.AmountFormatDecimal, amount: 1234, denomination: 2, want: `"12.34"`},
+		{format: domain.AmountFormatDecimal, amount: -5, denomination: 2, want: `"-0.05"`},
+		{format: domain.AmountFormatDecimal, amount: 12, denomination: 0, want: `"12"`},
+		{format: domain.AmountFormatDecimal, amount: math.MaxInt, denomination: domain.MaxDenomination, want: `"9.223372036854775807"`},
+	} {
+		b, err := json.Marshal(AmountOf(tt.format, tt.amount, tt.denomination))
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, string(b))
+	}
+}
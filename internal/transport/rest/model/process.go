@@ -1,13 +1,17 @@
 package model
 
+import "open-api-games/internal/domain"
+
 type ProcessApiCommand string
 
 const (
-	ProcessApiCommandBalance  ProcessApiCommand = "balance"
-	ProcessApiCommandDebit    ProcessApiCommand = "debit"
-	ProcessApiCommandCredit   ProcessApiCommand = "credit"
-	ProcessApiCommandRollback ProcessApiCommand = "rollback"
-	ProcessApiCommandMetaData ProcessApiCommand = "metaData"
+	ProcessApiCommandBalance     ProcessApiCommand = "balance"
+	ProcessApiCommandDebit       ProcessApiCommand = "debit"
+	ProcessApiCommandCredit      ProcessApiCommand = "credit"
+	ProcessApiCommandRollback    ProcessApiCommand = "rollback"
+	ProcessApiCommandMetaData    ProcessApiCommand = "metaData"
+	ProcessApiCommandBatch       ProcessApiCommand = "batch"
+	ProcessApiCommandDebitCredit ProcessApiCommand = "debitCredit"
 )
 
 func (p ProcessApiCommand) String() string {
@@ -15,15 +19,17 @@ func (p ProcessApiCommand) String() string {
 }
 
 func (p ProcessApiCommand) IsValid() bool {
-	return p == ProcessApiCommandBalance || p == ProcessApiCommandDebit || p == ProcessApiCommandCredit || p == ProcessApiCommandRollback || p == ProcessApiCommandMetaData
+	return p == ProcessApiCommandBalance || p == ProcessApiCommandDebit || p == ProcessApiCommandCredit || p == ProcessApiCommandRollback || p == ProcessApiCommandMetaData || p == ProcessApiCommandBatch || p == ProcessApiCommandDebitCredit
 }
 
 type ProcessApiReqData interface {
-	*ProcessBalanceReq | *ProcessDebitCreditRollbackReq | *ProcessMetaDataReq
+	*ProcessBalanceReq | *ProcessDebitCreditRollbackReq | *ProcessMetaDataReq | *ProcessBatchReq | *ProcessDebitCreditReq
+	// Validate checks the data of the api command, nil data is reported as violation
+	Validate(api ProcessApiCommand) []domain.Violation
 }
 
 type ProcessApiResData interface {
-	*ProcessBalanceRes | *ProcessDebitCreditRollbackRes | *ProcessMetaDataRes
+	*ProcessBalanceRes | *ProcessDebitCreditRollbackRes | *ProcessMetaDataRes | *ProcessBatchRes | *ProcessDebitCreditRes
 }
 
 type ProcessCommand struct {
@@ -35,12 +41,23 @@ type ProcessReq[T ProcessApiReqData] struct {
 	Data T                 `json:"data"`
 }
 
+// Validate checks the data block of the request
+func (r *ProcessReq[T]) Validate() []domain.Violation {
+	return r.Data.Validate(r.Api)
+}
+
 type ProcessRes[T ProcessApiResData] struct {
 	Api       ProcessApiCommand `json:"api"`
 	Data      T                 `json:"data"`
 	IsSuccess bool              `json:"isSuccess"`
-	Error     string            `json:"error"`
-	ErrorMsg  string            `json:"errorMsg"`
+	// Error is the error code, ErrorCode is its numeric value and ErrorMsg is the human readable message
+	Error     string `json:"error"`
+	ErrorCode int    `json:"errorCode"`
+	ErrorMsg  string `json:"errorMsg"`
+	// Retryable reports whether the failed request could succeed when it's sent again
+	Retryable bool `json:"retryable"`
+	// Violations list failed validation rules of the request fields
+	Violations []domain.Violation `json:"violations,omitempty"`
 }
 
 type ProcessBalanceReq struct {
@@ -53,13 +70,31 @@ type ProcessDebitCreditRollbackReq struct {
 	GameSessionUID string `json:"gameSessionId"`
 	UserUID        string `json:"userId"`
 	UserNick       string `json:"userNick"`
-	Amount         int    `json:"amount"`
+	Amount         Amount `json:"amount"`
+	Currency       string `json:"currency"`
+	Denomination   int    `json:"denomination"`
+	MaxWin         Amount `json:"maxWin"`
+	JpKey          string `json:"jpKey"`
+	SpinMeta       string `json:"spinMeta"`
+	BetMeta        string `json:"betMeta"`
+	BetUID         string `json:"betId"`
+	CampaignUID    string `json:"campaignId,omitempty"`
+}
+
+type ProcessDebitCreditReq struct {
+	TransactionUID string `json:"transactionId"`
+	GameSessionUID string `json:"gameSessionId"`
+	UserUID        string `json:"userId"`
+	UserNick       string `json:"userNick"`
+	BetAmount      Amount `json:"betAmount"`
+	WinAmount      Amount `json:"winAmount"`
 	Currency       string `json:"currency"`
 	Denomination   int    `json:"denomination"`
-	MaxWin         int    `json:"maxWin"`
+	MaxWin         Amount `json:"maxWin"`
 	JpKey          string `json:"jpKey"`
 	SpinMeta       string `json:"spinMeta"`
 	BetMeta        string `json:"betMeta"`
+	BetUID         string `json:"betId"`
 }
 
 type ProcessMetaDataReq struct {
@@ -70,10 +105,28 @@ type ProcessMetaDataReq struct {
 	Data           ProcessApiDataData `json:"data"`
 }
 
+type ProcessBatchMode string
+
+const (
+	ProcessBatchModeAtomic     ProcessBatchMode = "atomic"
+	ProcessBatchModeBestEffort ProcessBatchMode = "bestEffort"
+)
+
+type ProcessBatchReq struct {
+	Mode  ProcessBatchMode      `json:"mode"`
+	Items []ProcessBatchItemReq `json:"items"`
+}
+
+type ProcessBatchItemReq struct {
+	Api  ProcessApiCommand              `json:"api"`
+	Data *ProcessDebitCreditRollbackReq `json:"data"`
+}
+
 type ProcessApiDataApi string
 
 const (
 	ProcessApiDataApiRoundComplete ProcessApiDataApi = "roundComplete"
+	ProcessApiDataApiRealityCheck  ProcessApiDataApi = "realityCheck"
 )
 
 type ProcessApiDataData struct {
@@ -81,25 +134,93 @@ type ProcessApiDataData struct {
 }
 
 type ProcessBalanceRes struct {
-	UserUID      string `json:"userId"`
-	UserNick     string `json:"userNick"`
-	Amount       int    `json:"amount"`
-	Currency     string `json:"currency"`
-	Denomination int    `json:"denomination"`
-	MaxWin       int    `json:"maxWin"`
-	JpKey        string `json:"jpKey"`
+	UserUID      string                  `json:"userId"`
+	UserNick     string                  `json:"userNick"`
+	Amount       Amount                  `json:"amount"`
+	RealAmount   Amount                  `json:"realAmount"`
+	BonusAmount  Amount                  `json:"bonusAmount"`
+	Wagering     Amount                  `json:"wagering"`
+	Currency     string                  `json:"currency"`
+	Denomination int                     `json:"denomination"`
+	MaxWin       Amount                  `json:"maxWin"`
+	JpKey        string                  `json:"jpKey"`
+	Jackpots     []ProcessJackpotRes     `json:"jackpots"`
+	RealityCheck *ProcessRealityCheckRes `json:"realityCheck,omitempty"`
+}
+
+type ProcessJackpotRes struct {
+	JpKey  string `json:"jpKey"`
+	Name   string `json:"name"`
+	Amount Amount `json:"amount"`
 }
 
 type ProcessDebitCreditRollbackRes struct {
-	TransactionUID string `json:"transactionId"`
-	UserNick       string `json:"userNick"`
-	Amount         int    `json:"amount"`
+	TransactionUID string                  `json:"transactionId"`
+	UserNick       string                  `json:"userNick"`
+	Amount         Amount                  `json:"amount"`
+	Balance        Amount                  `json:"balance"`
+	Currency       string                  `json:"currency"`
+	Denomination   int                     `json:"denomination"`
+	MaxWin         Amount                  `json:"maxWin"`
+	RealityCheck   *ProcessRealityCheckRes `json:"realityCheck,omitempty"`
+}
+
+// ProcessRealityCheckRes asks the provider to show the reality check dialog with the time and the net result of the session
+type ProcessRealityCheckRes struct {
+	ElapsedSeconds int    `json:"elapsedSeconds"`
+	NetResult      Amount `json:"netResult"`
 	Currency       string `json:"currency"`
-	Denomination   int    `json:"denomination"`
-	MaxWin         int    `json:"maxWin"`
+}
+
+type ProcessDebitCreditRes struct {
+	DebitTransactionUID  string `json:"debitTransactionId"`
+	CreditTransactionUID string `json:"creditTransactionId"`
+	UserNick             string `json:"userNick"`
+	BetAmount            Amount `json:"betAmount"`
+	WinAmount            Amount `json:"winAmount"`
+	Balance              Amount `json:"balance"`
+	Currency             string `json:"currency"`
+	Denomination         int    `json:"denomination"`
+	MaxWin               Amount `json:"maxWin"`
 }
 
 type ProcessMetaDataRes struct {
-	Api  ProcessApiDataApi `json:"api"`
-	Data string            `json:"data"`
+	Api          ProcessApiDataApi       `json:"api"`
+	Data         *ProcessRoundRes        `json:"data"`
+	RealityCheck *ProcessRealityCheckRes `json:"realityCheck,omitempty"`
+}
+
+type ProcessRoundRes struct {
+	BetUID         string                       `json:"betId"`
+	GameSessionUID string                       `json:"gameSessionId"`
+	UserUID        string                       `json:"userId"`
+	Currency       string                       `json:"currency"`
+	Status         string                       `json:"status"`
+	TotalBet       Amount                       `json:"totalBet"`
+	TotalWin       Amount                       `json:"totalWin"`
+	Transactions   []ProcessRoundTransactionRes `json:"transactions"`
+}
+
+type ProcessRoundTransactionRes struct {
+	TransactionUID       string `json:"transactionId"`
+	Type                 string `json:"type"`
+	Amount               Amount `json:"amount"`
+	ParentTransactionUID string `json:"parentTransactionId,omitempty"`
+}
+
+type ProcessBatchRes struct {
+	Mode  ProcessBatchMode      `json:"mode"`
+	Items []ProcessBatchItemRes `json:"items"`
+}
+
+// ProcessBatchItemRes is the result of the batch item described the same way as the response to the separate request
+type ProcessBatchItemRes struct {
+	Api        ProcessApiCommand              `json:"api"`
+	Data       *ProcessDebitCreditRollbackRes `json:"data"`
+	IsSuccess  bool                           `json:"isSuccess"`
+	Error      string                         `json:"error"`
+	ErrorCode  int                            `json:"errorCode"`
+	ErrorMsg   string                         `json:"errorMsg"`
+	Retryable  bool                           `json:"retryable"`
+	Violations []domain.Violation             `json:"violations,omitempty"`
 }
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"fmt"
+	"open-api-games/internal/domain"
+)
+
+// batchMaxItems limits the number of items of the batch request
+const batchMaxItems = 100
+
+// dataRequired is the violation of the request without data block
+var dataRequired = []domain.Violation{{Field: "data", Rule: domain.Required.Name, Message: domain.Required.Message}}
+
+func (r *ProcessBalanceReq) Validate(_ ProcessApiCommand) []domain.Violation {
+	if r == nil {
+		return dataRequired
+	}
+	return domain.Validate(
+		domain.Field("gameSessionId", r.GameSessionUID, domain.Required),
+		domain.Field("currency", r.Currency, domain.Required, domain.CurrencyCode),
+	)
+}
+
+// Validate checks debit and credit move money of the user in the session, credit could be zero win,
+// rollback refers the transaction only
+func (r *ProcessDebitCreditRollbackReq) Validate(api ProcessApiCommand) []domain.Violation {
+	if r == nil {
+		return dataRequired
+	}
+	isMove := api == ProcessApiCommandDebit || api == ProcessApiCommandCredit
+	return domain.Validate(
+		domain.Field("transactionId", r.TransactionUID, domain.Required),
+		domain.When(isMove && r.UserUID == "",
+			domain.Field("gameSessionId", r.GameSessionUID, domain.Required),
+		),
+		domain.When(api == ProcessApiCommandDebit && r.CampaignUID == "",
+			domain.Field("amount", r.Amount, amountNumber, amountSign(domain.Positive)),
+		),
+		// free round bet is paid by the campaign
+		domain.When(api == ProcessApiCommandDebit && r.CampaignUID != "",
+			domain.Field("amount", r.Amount, amountNumber, amountSign(domain.NotNegative), amountSign(domain.Max(0))),
+		),
+		domain.When(api != ProcessApiCommandDebit,
+			domain.Field("amount", r.Amount, amountNumber, amountSign(domain.NotNegative)),
+		),
+		domain.When(isMove,
+			domain.Field("currency", r.Currency, domain.Required, domain.CurrencyCode),
+		),
+		domain.When(!isMove && r.Currency != "",
+			domain.Field("currency", r.Currency, domain.CurrencyCode),
+		),
+		domain.Field("denomination", r.Denomination, domain.NotNegative, domain.Max(domain.MaxDenomination)),
+		domain.Field("maxWin", r.MaxWin, amountNumber, amountSign(domain.NotNegative)),
+	)
+}
+
+// Validate checks the bet and the win of the instant round, the win could be zero
+func (r *ProcessDebitCreditReq) Validate(_ ProcessApiCommand) []domain.Violation {
+	if r == nil {
+		return dataRequired
+	}
+	return domain.Validate(
+		domain.Field("transactionId", r.TransactionUID, domain.Required),
+		domain.When(r.UserUID == "",
+			domain.Field("gameSessionId", r.GameSessionUID, domain.Required),
+		),
+		domain.Field("betAmount", r.BetAmount, amountNumber, amountSign(domain.Positive)),
+		domain.Field("winAmount", r.WinAmount, amountNumber, amountSign(domain.NotNegative)),
+		domain.Field("currency", r.Currency, domain.Required, domain.CurrencyCode),
+		domain.Field("denomination", r.Denomination, domain.NotNegative, domain.Max(domain.MaxDenomination)),
+		domain.Field("maxWin", r.MaxWin, amountNumber, amountSign(domain.NotNegative)),
+	)
+}
+
+func (r *ProcessMetaDataReq) Validate(_ ProcessApiCommand) []domain.Violation {
+	if r == nil {
+		return dataRequired
+	}
+	return domain.Validate(
+		domain.Field("gameSessionId", r.GameSessionUID, domain.Required),
+		domain.Field("api", r.Api, domain.OneOf(ProcessApiDataApiRoundComplete, ProcessApiDataApiRealityCheck)),
+		domain.When(r.Api == ProcessApiDataApiRoundComplete,
+			domain.Field("data.betId", r.Data.BetId, domain.Required),
+		),
+		domain.When(r.Currency != "",
+			domain.Field("currency", r.Currency, domain.CurrencyCode),
+		),
+	)
+}
+
+// Validate checks the mode and every item of the batch, item violations are prefixed by the item path,
+// provider transaction can't repeat within the batch and atomic batch can't roll back its own item
+func (r *ProcessBatchReq) Validate(_ ProcessApiCommand) []domain.Violation {
+	if r == nil {
+		return dataRequired
+	}
+	violations := domain.Validate(
+		domain.Field("mode", r.Mode, domain.OneOf(ProcessBatchModeAtomic, ProcessBatchModeBestEffort)),
+		domain.Field("items", len(r.Items), domain.Positive, domain.Max(batchMaxItems)),
+	)
+	if len(r.Items) > batchMaxItems {
+		return violations
+	}
+
+	moved := make(map[string]bool, len(r.Items))
+	seen := make(map[string]bool, len(r.Items))
+	for _, item := range r.Items {
+		if item.Data != nil && item.Api != ProcessApiCommandRollback {
+			moved[item.Data.TransactionUID] = true
+		}
+	}
+	for i, item := range r.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		violations = append(violations, domain.Validate(
+			domain.Field(prefix+"api", item.Api, domain.OneOf(ProcessApiCommandDebit, ProcessApiCommandCredit, ProcessApiCommandRollback)),
+		)...)
+		if item.Data == nil {
+			violations = append(violations, domain.Validate(domain.Field(prefix+"data", "", domain.Required))...)
+			continue
+		}
+		for _, v := range item.Data.Validate(item.Api) {
+			v.Field = prefix + "data." + v.Field
+			violations = append(violations, v)
+		}
+
+		key := item.Api.String() + ":" + item.Data.TransactionUID
+		violations = append(violations, domain.Validate(
+			domain.When(seen[key],
+				domain.Field(prefix+"data.transactionId", item.Data.TransactionUID, batchUnique),
+			),
+			domain.When(r.Mode == ProcessBatchModeAtomic && item.Api == ProcessApiCommandRollback,
+				domain.Field(prefix+"data.transactionId", item.Data.TransactionUID, batchForeign(moved)),
+			),
+		)...)
+		seen[key] = true
+	}
+	return violations
+}
+
+// batchUnique fails the provider transaction repeated within the batch
+var batchUnique = domain.Rule[string]{
+	Name:    "unique",
+	Message: "value must be unique within the batch",
+	Check:   func(string) bool { return false },
+}
+
+// batchForeign fails the rollback of the transaction moved by the same atomic batch
+func batchForeign(moved map[string]bool) domain.Rule[string] {
+	return domain.Rule[string]{
+		Name:    "foreign",
+		Message: "value must not refer the transaction of the same atomic batch",
+		Check:   func(v string) bool { return !moved[v] },
+	}
+}
+
+// amountNumber fails the amount which is neither integer nor decimal number
+var amountNumber = domain.Rule[Amount]{
+	Name:    "number",
+	Message: "value must be integer or decimal number",
+	Check: func(v Amount) bool {
+		_, ok := v.sign()
+		return ok
+	},
+}
+
+// amountSign applies the number rule to the sign of the amount
+func amountSign(rule domain.Rule[int]) domain.Rule[Amount] {
+	return domain.Rule[Amount]{
+		Name:    rule.Name,
+		Message: rule.Message,
+		Check: func(v Amount) bool {
+			sign, _ := v.sign()
+			return rule.Check(sign)
+		},
+	}
+}
+
+// AmountFits fails the amount which can't be read in minor units of the denomination without losing precision
+func AmountFits(format domain.AmountFormat, denomination int) domain.Rule[Amount] {
+	return domain.Rule[Amount]{
+		Name:    "precision",
+		Message: fmt.Sprintf("value must fit denomination %d", denomination),
+		Check: func(v Amount) bool {
+			_, ok := v.Minor(format, denomination)
+			return ok
+		},
+	}
+}
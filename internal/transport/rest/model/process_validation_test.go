@@ -0,0 +1,262 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"open-api-games/internal/domain"
+	"testing"
+)
+
+// failed lists the violations as field:rule pairs
+func failed(violations []domain.Violation) []string {
+	res := make([]string, 0, len(violations))
+	for _, v := range violations {
+		res = append(res, v.Field+":"+v.Rule)
+	}
+	return res
+}
+
+func amountOf(text string) Amount {
+	return Amount{text: text}
+}
+
+func TestProcessDebitCreditRollbackReqValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *ProcessDebitCreditRollbackReq {
+		return &ProcessDebitCreditRollbackReq{
+			TransactionUID: "tx-1",
+			GameSessionUID: "s1",
+			Amount:         amountOf("100"),
+			Currency:       "USD",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		api    ProcessApiCommand
+		modify func(r *ProcessDebitCreditRollbackReq)
+		want   []string
+	}{
+		{name: "valid debit", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {}, want: []string{}},
+		{name: "debit of user without session", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.GameSessionUID, r.UserUID = "", "u1"
+		}, want: []string{}},
+		{name: "debit without session and user", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.GameSessionUID = ""
+		}, want: []string{"gameSessionId:required"}},
+		{name: "debit without transaction id", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.TransactionUID = ""
+		}, want: []string{"transactionId:required"}},
+		{name: "zero debit", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Amount = amountOf("0")
+		}, want: []string{"amount:positive"}},
+		{name: "malformed amount", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Amount = amountOf("1e3")
+		}, want: []string{"amount:number"}},
+		{name: "free round debit without amount", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.CampaignUID, r.Amount = "fr-1", Amount{}
+		}, want: []string{}},
+		{name: "free round debit with amount", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.CampaignUID = "fr-1"
+		}, want: []string{"amount:max"}},
+		{name: "zero win credit", api: ProcessApiCommandCredit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Amount = amountOf("0")
+		}, want: []string{}},
+		{name: "negative credit", api: ProcessApiCommandCredit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Amount = amountOf("-1")
+		}, want: []string{"amount:notNegative"}},
+		{name: "credit without currency", api: ProcessApiCommandCredit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Currency = ""
+		}, want: []string{"currency:required"}},
+		{name: "credit in lowercase currency", api: ProcessApiCommandCredit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Currency = "usd"
+		}, want: []string{"currency:currency"}},
+		{name: "rollback refers the transaction only", api: ProcessApiCommandRollback, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.GameSessionUID, r.Amount, r.Currency = "", Amount{}, ""
+		}, want: []string{}},
+		{name: "rollback in malformed currency", api: ProcessApiCommandRollback, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Currency = "US"
+		}, want: []string{"currency:currency"}},
+		{name: "negative denomination", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Denomination = -1
+		}, want: []string{"denomination:notNegative"}},
+		{name: "finest denomination", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Denomination = domain.MaxDenomination
+		}, want: []string{}},
+		{name: "denomination out of int range", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.Denomination = domain.MaxDenomination + 1
+		}, want: []string{"denomination:max"}},
+		{name: "negative max win", api: ProcessApiCommandDebit, modify: func(r *ProcessDebitCreditRollbackReq) {
+			r.MaxWin = amountOf("-10")
+		}, want: []string{"maxWin:notNegative"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(req)
+
+			assert.Equal(t, tt.want, failed(req.Validate(tt.api)))
+		})
+	}
+
+	t.Run("data is required", func(t *testing.T) {
+		var req *ProcessDebitCreditRollbackReq
+
+		assert.Equal(t, []string{"data:required"}, failed(req.Validate(ProcessApiCommandDebit)))
+	})
+}
+
+func TestProcessDebitCreditReqValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *ProcessDebitCreditReq {
+		return &ProcessDebitCreditReq{
+			TransactionUID: "tx-1",
+			GameSessionUID: "s1",
+			BetAmount:      amountOf("100"),
+			WinAmount:      amountOf("250"),
+			Currency:       "USD",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *ProcessDebitCreditReq)
+		want   []string
+	}{
+		{name: "valid round", modify: func(r *ProcessDebitCreditReq) {}, want: []string{}},
+		{name: "lost round", modify: func(r *ProcessDebitCreditReq) {
+			r.WinAmount = amountOf("0")
+		}, want: []string{}},
+		{name: "round of user without session", modify: func(r *ProcessDebitCreditReq) {
+			r.GameSessionUID, r.UserUID = "", "u1"
+		}, want: []string{}},
+		{name: "round without session and user", modify: func(r *ProcessDebitCreditReq) {
+			r.GameSessionUID = ""
+		}, want: []string{"gameSessionId:required"}},
+		{name: "round without transaction id", modify: func(r *ProcessDebitCreditReq) {
+			r.TransactionUID = ""
+		}, want: []string{"transactionId:required"}},
+		{name: "zero bet", modify: func(r *ProcessDebitCreditReq) {
+			r.BetAmount = amountOf("0")
+		}, want: []string{"betAmount:positive"}},
+		{name: "negative win", modify: func(r *ProcessDebitCreditReq) {
+			r.WinAmount = amountOf("-1")
+		}, want: []string{"winAmount:notNegative"}},
+		{name: "malformed amounts", modify: func(r *ProcessDebitCreditReq) {
+			r.BetAmount, r.WinAmount = amountOf("ten"), amountOf("1.2.3")
+		}, want: []string{"betAmount:number", "winAmount:number"}},
+		{name: "round without currency", modify: func(r *ProcessDebitCreditReq) {
+			r.Currency = ""
+		}, want: []string{"currency:required"}},
+		{name: "negative denomination", modify: func(r *ProcessDebitCreditReq) {
+			r.Denomination = -2
+		}, want: []string{"denomination:notNegative"}},
+		{name: "denomination out of int range", modify: func(r *ProcessDebitCreditReq) {
+			r.Denomination = 64
+		}, want: []string{"denomination:max"}},
+		{name: "negative max win", modify: func(r *ProcessDebitCreditReq) {
+			r.MaxWin = amountOf("-1")
+		}, want: []string{"maxWin:notNegative"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(req)
+
+			assert.Equal(t, tt.want, failed(req.Validate(ProcessApiCommandDebitCredit)))
+		})
+	}
+}
+
+func TestProcessBatchReqValidate(t *testing.T) {
+	t.Parallel()
+
+	item := func(api ProcessApiCommand, transactionUID string) ProcessBatchItemReq {
+		return ProcessBatchItemReq{
+			Api: api,
+			Data: &ProcessDebitCreditRollbackReq{
+				TransactionUID: transactionUID,
+				GameSessionUID: "s1",
+				Amount:         amountOf("100"),
+				Currency:       "USD",
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  *ProcessBatchReq
+		want []string
+	}{
+		{name: "valid atomic batch", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), item(ProcessApiCommandCredit, "tx-2")},
+		}, want: []string{}},
+		{name: "unknown mode", req: &ProcessBatchReq{
+			Mode:  "parallel",
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1")},
+		}, want: []string{"mode:oneOf"}},
+		{name: "empty batch", req: &ProcessBatchReq{
+			Mode: ProcessBatchModeAtomic,
+		}, want: []string{"items:positive"}},
+		{name: "too many items", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeBestEffort,
+			Items: make([]ProcessBatchItemReq, batchMaxItems+1),
+		}, want: []string{"items:max"}},
+		{name: "item of not batched api", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandBalance, "tx-1")},
+		}, want: []string{"items[0].api:oneOf"}},
+		{name: "item without data", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{{Api: ProcessApiCommandDebit}},
+		}, want: []string{"items[0].data:required"}},
+		{name: "item violations are prefixed by item path", req: &ProcessBatchReq{
+			Mode: ProcessBatchModeBestEffort,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), {
+				Api:  ProcessApiCommandDebit,
+				Data: &ProcessDebitCreditRollbackReq{TransactionUID: "tx-2", GameSessionUID: "s1", Amount: amountOf("0"), Currency: "USD", Denomination: 19},
+			}},
+		}, want: []string{"items[1].data.amount:positive", "items[1].data.denomination:max"}},
+		{name: "transaction repeated within the batch", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeBestEffort,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), item(ProcessApiCommandDebit, "tx-1")},
+		}, want: []string{"items[1].data.transactionId:unique"}},
+		{name: "debit and credit share the transaction id", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), item(ProcessApiCommandCredit, "tx-1")},
+		}, want: []string{}},
+		{name: "atomic batch rolls back its own item", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), item(ProcessApiCommandRollback, "tx-1")},
+		}, want: []string{"items[1].data.transactionId:foreign"}},
+		{name: "best effort batch rolls back its own item", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeBestEffort,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-1"), item(ProcessApiCommandRollback, "tx-1")},
+		}, want: []string{}},
+		{name: "atomic batch rolls back earlier transaction", req: &ProcessBatchReq{
+			Mode:  ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{item(ProcessApiCommandDebit, "tx-2"), item(ProcessApiCommandRollback, "tx-1")},
+		}, want: []string{}},
+		{name: "free round debit with amount", req: &ProcessBatchReq{
+			Mode: ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{{
+				Api:  ProcessApiCommandDebit,
+				Data: &ProcessDebitCreditRollbackReq{TransactionUID: "tx-1", GameSessionUID: "s1", Amount: amountOf("100"), Currency: "USD", CampaignUID: "fr-1"},
+			}},
+		}, want: []string{"items[0].data.amount:max"}},
+		{name: "free round debit without amount", req: &ProcessBatchReq{
+			Mode: ProcessBatchModeAtomic,
+			Items: []ProcessBatchItemReq{{
+				Api:  ProcessApiCommandDebit,
+				Data: &ProcessDebitCreditRollbackReq{TransactionUID: "tx-1", GameSessionUID: "s1", Currency: "USD", CampaignUID: "fr-1"},
+			}},
+		}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, failed(tt.req.Validate(ProcessApiCommandBatch)))
+		})
+	}
+}
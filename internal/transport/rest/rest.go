@@ -5,11 +5,19 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	slogecho "github.com/samber/slog-echo"
+	"io"
 	"log/slog"
+	"net"
 	"open-api-games/internal/config"
+	"open-api-games/internal/domain"
 	"open-api-games/internal/repository"
+	"open-api-games/internal/service/admin"
 	"open-api-games/internal/service/game_processor"
+	"open-api-games/internal/service/outbox"
+	"open-api-games/internal/service/provider"
 	"open-api-games/internal/service/seed"
+	"open-api-games/internal/service/session"
+	"open-api-games/internal/transport/rest/admin_handler"
 	"open-api-games/internal/transport/rest/game_processor_handler"
 	"os"
 	"os/signal"
@@ -49,14 +57,56 @@ func Start() error {
 
 	// Initialize service
 	logger.Info("services initializing...")
-	gameProcessor := game_processor.New(repo, logger)
+	gameProcessor := game_processor.New(repo, logger, game_processor.Settings{
+		MaxWinMode: domain.MaxWinMode(cfg.MaxWinMode),
+		BonusOrder: domain.BonusOrder(cfg.BonusSpendOrder),
+		SessionTTL: cfg.SessionTTL,
+	})
+	sessionService := session.New(repo, logger, session.Settings{
+		TTL:           cfg.SessionTTL,
+		SweepInterval: cfg.SessionSweepInterval,
+	})
+	adminService := admin.New(repo, logger)
+	providerService := provider.New(repo, logger, provider.Settings{ClockSkew: cfg.SignClockSkew})
+
+	// Expire sessions idle for the TTL in background, the activity moves their expiry forward
+	go sessionService.Sweep(ctx)
+
+	// Publish wallet movement events in background
+	sink, err := outbox.NewSink(outbox.SinkSettings{
+		Kind:           cfg.OutboxSink,
+		File:           cfg.OutboxFile,
+		WebhookURL:     cfg.OutboxWebhookURL,
+		WebhookTimeout: cfg.OutboxWebhookTimeout,
+	}, logger)
+	if err != nil {
+		logger.Error("failed to create outbox sink", "error", err)
+		return err
+	}
+	relay := outbox.New(repo, sink, logger, outbox.Settings{
+		Interval:    cfg.OutboxRelayInterval,
+		BatchSize:   cfg.OutboxBatchSize,
+		MaxAttempts: cfg.OutboxMaxAttempts,
+		Backoff:     cfg.OutboxRetryBackoff,
+	})
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Relay(ctx)
+	}()
 
 	// Initialize handler
 	logger.Info("handlers initializing...")
-	gameProcessorHandler := game_processor_handler.New(gameProcessor, logger)
+	gameProcessorHandler := game_processor_handler.New(gameProcessor, providerService, logger)
+	adminHandler := admin_handler.New(adminService, sessionService, logger, cfg.AdminTokens)
 
 	// Echo instance
 	e := echo.New()
+	e.IPExtractor, err = ipExtractor(cfg)
+	if err != nil {
+		logger.Error("failed to parse trusted proxies", "error", err)
+		return err
+	}
 
 	// Middleware
 	e.Use(slogecho.New(logger))
@@ -66,6 +116,11 @@ func Start() error {
 	rootGroup := e.Group("/open-api-games", gameProcessorHandler.CheckSign)
 	v1Group := rootGroup.Group("/v1")
 	v1Group.POST("/games-processor", gameProcessorHandler.Process)
+	v1Group.POST("/:"+game_processor_handler.ProviderParam+"/games-processor", gameProcessorHandler.Process)
+
+	// Admin routes authenticated by operator token
+	adminGroup := e.Group("/admin/v1", adminHandler.CheckAuth)
+	adminHandler.Register(adminGroup)
 
 	// Healthcheck
 	e.GET("/healthcheck", func(c echo.Context) error {
@@ -92,6 +147,14 @@ func Start() error {
 	// TODO: wait for shutdown of dependencies with tasks in progress
 	<-ctx.Done()
 
+	logger.Info("closing outbox sink...")
+	<-relayDone
+	if closer, ok := sink.(io.Closer); ok {
+		if err = closer.Close(); err != nil {
+			logger.Error("failed to close outbox sink", "error", err)
+		}
+	}
+
 	logger.Info("closing repository...")
 	err = repo.Close(ctx)
 	if err != nil {
@@ -108,3 +171,25 @@ func Start() error {
 
 	return nil
 }
+
+// ipExtractor takes client ip from X-Forwarded-For set by trusted proxies only,
+// the direct peer address is used when no proxies are configured
+func ipExtractor(cfg *config.Config) (echo.IPExtractor, error) {
+	if len(cfg.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range cfg.TrustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}